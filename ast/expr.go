@@ -2,6 +2,7 @@ package ast
 
 import (
 	"reflect"
+	"sort"
 
 	"github.com/shinanca/gonec/bincode/binstmt"
 	"github.com/shinanca/gonec/core"
@@ -14,12 +15,13 @@ type Expr interface {
 	pos.Pos
 	expr()
 	Simplify() Expr
-	BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int)
+	BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int)
+	format(p *printer)
 }
 
 type CanLetExpr interface {
 	Expr
-	BinLetTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int)
+	BinLetTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int)
 }
 
 // ExprImpl provide commonly implementations for Expr.
@@ -36,7 +38,7 @@ type NoneExpr struct {
 }
 
 func (x *NoneExpr) Simplify() Expr { return x }
-func (e *NoneExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
+func (e *NoneExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
 	bins.Append(binstmt.NewBinLOAD(reg, nil, false, e))
 	if reg > *maxreg {
 		*maxreg = reg
@@ -57,7 +59,7 @@ func (x *NumberExpr) Simplify() Expr {
 	return &NativeExpr{Value: rv}
 }
 
-func (e *NumberExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
+func (e *NumberExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
 	// команда на загрузку строки в регистр и ее преобразование в число, в регистр reg
 	bins.Append(binstmt.NewBinLOAD(reg, core.VMString(e.Lit), false, e))
 	bins.Append(binstmt.NewBinCASTNUM(reg, e))
@@ -76,7 +78,7 @@ func (x *StringExpr) Simplify() Expr {
 	return &NativeExpr{Value: core.VMString(x.Lit)}
 }
 
-func (e *StringExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
+func (e *StringExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
 	bins.Append(binstmt.NewBinLOAD(reg, core.VMString(e.Lit), false, e))
 	if reg > *maxreg {
 		*maxreg = reg
@@ -84,12 +86,18 @@ func (e *StringExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt boo
 }
 
 // ArrayExpr provide Array expression.
+// Rest задается только в шаблоне присваивания [голова, ...хвост] = слайс
 type ArrayExpr struct {
 	ExprImpl
 	Exprs []Expr
+	Rest  Expr
 }
 
 func (x *ArrayExpr) Simplify() Expr {
+	if x.Rest != nil {
+		// шаблон присваивания не сворачиваем в константу
+		return x
+	}
 	waserrors := false
 	a := make(core.VMSlice, len(x.Exprs))
 	for i := range x.Exprs {
@@ -107,13 +115,16 @@ func (x *ArrayExpr) Simplify() Expr {
 	}
 }
 
-func (e *ArrayExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
+func (e *ArrayExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
+	if e.Rest != nil {
+		panic(binstmt.NewStringError(e, "Остаток массива допустим только в левой части присваивания"))
+	}
 	// создание слайса
 	bins.Append(binstmt.NewBinMAKESLICE(reg, len(e.Exprs), len(e.Exprs), e))
 
 	for i, ee := range e.Exprs {
 		// каждое выражение сохраняем в следующем по номеру регистре (относительно регистра слайса)
-		ee.BinTo(bins, reg+1, lid, false, maxreg)
+		ee.BinTo(bins, reg+1, cc, false, maxreg)
 		bins.Append(binstmt.NewBinSETIDX(reg, i, reg+1, ee))
 	}
 	if reg+1 > *maxreg {
@@ -121,6 +132,32 @@ func (e *ArrayExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool
 	}
 }
 
+// BinLetTo присваивает элементы слайса, используя литерал массива как шаблон:
+// [первый, второй] = слайс, [голова, ...хвост] = слайс.
+// Элементы присваиваются по порядку, остаток получает слайс из оставшихся элементов (возможно, пустой).
+// Если элементов в слайсе меньше, чем переменных в шаблоне (не считая остатка),
+// возникает ошибка "Индекс за пределами границ", при этом начальные переменные уже будут присвоены
+func (e *ArrayExpr) BinLetTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
+	for i, ex := range e.Exprs {
+		ee, ok := ex.(CanLetExpr)
+		if !ok {
+			panic(binstmt.NewStringError(ex, "В шаблоне массива допустимы только переменные, элементы, поля и вложенные шаблоны"))
+		}
+		bins.Append(binstmt.NewBinMV(reg, reg+1, e))
+		bins.Append(binstmt.NewBinLOAD(reg+2, core.VMInt(i), false, e))
+		bins.Append(binstmt.NewBinGETIDX(reg+1, reg+2, e))
+		ee.BinLetTo(bins, reg+1, cc, maxreg)
+	}
+	if e.Rest != nil {
+		bins.Append(binstmt.NewBinMV(reg, reg+1, e))
+		bins.Append(binstmt.NewBinGETREST(reg+1, len(e.Exprs), e))
+		e.Rest.(CanLetExpr).BinLetTo(bins, reg+1, cc, maxreg)
+	}
+	if reg+3 > *maxreg {
+		*maxreg = reg + 3
+	}
+}
+
 // PairExpr provide one of Map key/value pair.
 type PairExpr struct {
 	ExprImpl
@@ -133,7 +170,7 @@ func (x *PairExpr) Simplify() Expr {
 	return x
 }
 
-func (e *PairExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {}
+func (e *PairExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {}
 
 // MapExpr provide Map expression.
 type MapExpr struct {
@@ -160,13 +197,13 @@ func (x *MapExpr) Simplify() Expr {
 	}
 }
 
-func (e *MapExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
+func (e *MapExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
 	// создание мапы
 	bins.Append(binstmt.NewBinMAKEMAP(reg, len(e.MapExpr), e))
 
 	for k, ee := range e.MapExpr {
 		// каждое выражение сохраняем в следующем по номеру регистре (относительно регистра слайса)
-		ee.BinTo(bins, reg+1, lid, false, maxreg)
+		ee.BinTo(bins, reg+1, cc, false, maxreg)
 		bins.Append(binstmt.NewBinSETKEY(reg, reg+1, k, ee))
 	}
 	if reg+1 > *maxreg {
@@ -174,23 +211,53 @@ func (e *MapExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool,
 	}
 }
 
+// BinLetTo присваивает значения по ключам структуры, используя литерал как шаблон:
+// {"x": а, "y": б} = карта. Значениями шаблона могут быть переменные, элементы, поля и вложенные шаблоны,
+// отсутствующим ключам соответствует Неопределено
+func (e *MapExpr) BinLetTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
+	keys := make([]string, 0, len(e.MapExpr))
+	for k := range e.MapExpr {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		ee, ok := e.MapExpr[k].(CanLetExpr)
+		if !ok {
+			panic(binstmt.NewStringError(e.MapExpr[k], "В шаблоне структуры допустимы только переменные, элементы, поля и вложенные шаблоны"))
+		}
+		bins.Append(binstmt.NewBinMV(reg, reg+1, e))
+		bins.Append(binstmt.NewBinLOAD(reg+2, core.VMString(k), false, e))
+		bins.Append(binstmt.NewBinGETIDX(reg+1, reg+2, e))
+		ee.BinLetTo(bins, reg+1, cc, maxreg)
+	}
+	if reg+2 > *maxreg {
+		*maxreg = reg + 2
+	}
+}
+
 // IdentExpr provide identity expression.
 type IdentExpr struct {
 	ExprImpl
 	Lit string
 	Id  int
+	// Global - переменная объявлена в функции как Глобальная, присваивание изменяет глобальную переменную
+	Global bool
 }
 
 func (x *IdentExpr) Simplify() Expr { return x }
 
-func (e *IdentExpr) BinLetTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
-	bins.Append(binstmt.NewBinSET(reg, e.Id, e))
+func (e *IdentExpr) BinLetTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
+	if e.Global {
+		bins.Append(binstmt.NewBinSETGLOBAL(reg, e.Id, e))
+	} else {
+		bins.Append(binstmt.NewBinSET(reg, e.Id, e))
+	}
 	if reg > *maxreg {
 		*maxreg = reg
 	}
 }
 
-func (e *IdentExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
+func (e *IdentExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
 	bins.Append(binstmt.NewBinGET(reg, e.Id, e))
 	if reg > *maxreg {
 		*maxreg = reg
@@ -198,6 +265,7 @@ func (e *IdentExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool
 }
 
 // UnaryExpr provide unary minus expression. ex: -1, ^1, ~1.
+// Унарный плюс +x приводит значение к числу (ЦелоеЧисло или Число), как числовой литерал
 type UnaryExpr struct {
 	ExprImpl
 	Operator string
@@ -206,9 +274,24 @@ type UnaryExpr struct {
 
 func (x *UnaryExpr) Simplify() Expr {
 	x.Expr = x.Expr.Simplify()
+	if x.Operator == "+" {
+		if v, ok := x.Expr.(*NativeExpr); ok {
+			if vv, ok := v.Value.(core.VMNumberInvoker); ok {
+				rv, err := vv.InvokeNumber()
+				if err == nil {
+					return &NativeExpr{Value: rv}
+				}
+			}
+		}
+		return x
+	}
+	oper, ok := core.UnaryOperMap[x.Operator]
+	if !ok {
+		// ошибка будет выдана при компиляции
+		return x
+	}
 	if v, ok := x.Expr.(*NativeExpr); ok {
 		if vv, ok := v.Value.(core.VMUnarer); ok {
-			oper := rune(x.Operator[0])
 			rv, err := vv.EvalUnOp(oper)
 			if err == nil {
 				return &NativeExpr{Value: rv}
@@ -218,14 +301,67 @@ func (x *UnaryExpr) Simplify() Expr {
 	return x
 }
 
-func (e *UnaryExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
-	e.Expr.BinTo(bins, reg, lid, false, maxreg)
-	bins.Append(binstmt.NewBinUNARY(reg, rune(e.Operator[0]), e))
+func (e *UnaryExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
+	if e.Operator == "+" {
+		e.Expr.BinTo(bins, reg, cc, false, maxreg)
+		bins.Append(binstmt.NewBinCASTNUM(reg, e))
+		if reg > *maxreg {
+			*maxreg = reg
+		}
+		return
+	}
+	oper, ok := core.UnaryOperMap[e.Operator]
+	if !ok {
+		panic(binstmt.NewStringError(e, "Неизвестный унарный оператор '"+e.Operator+"'"))
+	}
+	e.Expr.BinTo(bins, reg, cc, false, maxreg)
+	bins.Append(binstmt.NewBinUNARY(reg, oper, e))
 	if reg > *maxreg {
 		*maxreg = reg
 	}
 }
 
+// TryExpr выражение "попытаться выражение" - вычисляет выражение, перехватывая ошибку,
+// и возвращает массив [значение, ошибка]. Отсутствующий элемент пары равен Null
+type TryExpr struct {
+	ExprImpl
+	Expr Expr
+}
+
+func (x *TryExpr) Simplify() Expr {
+	x.Expr = x.Expr.Simplify()
+	return x
+}
+
+func (e *TryExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
+	// ошибка попадает в reg, значение вычисляется в reg+1, чтобы выражение не затерло ошибку
+	cc.Labels++
+	li := cc.Labels
+	cc.Labels++
+	lok := cc.Labels
+	cc.Labels++
+	lend := cc.Labels
+	try := binstmt.NewBinTRY(reg, li, e)
+	bins.Append(try)
+	inBlock(cc, try, func() { e.Expr.BinTo(bins, reg+1, cc, false, maxreg) })
+	bins.Append(binstmt.NewBinLABEL(li, e))
+	bins.Append(binstmt.NewBinPOPTRY(li, e))
+	bins.Append(binstmt.NewBinCATCH(reg, lok, e))
+	bins.Append(binstmt.NewBinLOAD(reg+1, core.VMNullVar, false, e))
+	bins.Append(binstmt.NewBinJMP(lend, e))
+	bins.Append(binstmt.NewBinLABEL(lok, e))
+	bins.Append(binstmt.NewBinLOAD(reg, core.VMNullVar, false, e))
+	bins.Append(binstmt.NewBinLABEL(lend, e))
+
+	bins.Append(binstmt.NewBinMAKESLICE(reg+2, 2, 2, e))
+	bins.Append(binstmt.NewBinSETIDX(reg+2, 0, reg+1, e))
+	bins.Append(binstmt.NewBinSETIDX(reg+2, 1, reg, e))
+	bins.Append(binstmt.NewBinMV(reg+2, reg, e))
+	if reg+2 > *maxreg {
+		*maxreg = reg + 2
+	}
+}
+
 // AddrExpr provide referencing address expression.
 // type AddrExpr struct {
 // 	ExprImpl
@@ -237,12 +373,12 @@ func (e *UnaryExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool
 // 	return x
 // }
 
-// func (e *AddrExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
+// func (e *AddrExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
 // 	switch ee := e.Expr.(type) {
 // 	case *IdentExpr:
 // 		bins.Append(binstmt.NewBinADDRID(reg, ee.Id, e))
 // 	case *MemberExpr:
-// 		ee.Expr.BinTo(bins, reg, lid, false, maxreg)
+// 		ee.Expr.BinTo(bins, reg, cc, false, maxreg)
 // 		bins.Append(binstmt.NewBinADDRMBR(reg, ee.Name, e))
 // 	default:
 // 		panic(binstmt.NewStringError(e, "Неверная операция над значением"))
@@ -263,12 +399,12 @@ func (e *UnaryExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool
 // 	return x
 // }
 
-// func (e *DerefExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
+// func (e *DerefExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
 // 	switch ee := e.Expr.(type) {
 // 	case *IdentExpr:
 // 		bins.Append(binstmt.NewBinUNREFID(reg, ee.Id, e))
 // 	case *MemberExpr:
-// 		ee.Expr.BinTo(bins, reg, lid, false, maxreg)
+// 		ee.Expr.BinTo(bins, reg, cc, false, maxreg)
 // 		bins.Append(binstmt.NewBinUNREFMBR(reg, ee.Name, e))
 // 	default:
 // 		panic(binstmt.NewStringError(e, "Неверная операция над значением"))
@@ -292,8 +428,8 @@ func (x *ParenExpr) Simplify() Expr {
 	return x
 }
 
-func (e *ParenExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
-	e.SubExpr.BinTo(bins, reg, lid, false, maxreg)
+func (e *ParenExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
+	e.SubExpr.BinTo(bins, reg, cc, false, maxreg)
 	if reg > *maxreg {
 		*maxreg = reg
 	}
@@ -332,10 +468,37 @@ func (x *BinOpExpr) Simplify() Expr {
 			}
 		}
 	}
+	if x.Operator == "+" && len(x.Lhss) == 1 && len(x.Rhss) == 1 {
+		if ops := concatOperands(x); len(ops) > 2 {
+			for _, op := range ops {
+				if v, ok := op.(*NativeExpr); ok {
+					if _, ok := v.Value.(core.VMString); ok {
+						// цепочка сложений со строковой константой - это сборка строки
+						c := &ConcatExpr{Exprs: ops}
+						c.SetPosition(x.Position())
+						return c
+					}
+				}
+			}
+		}
+	}
 	return x
 }
 
-func (e *BinOpExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
+// concatOperands раскладывает левоассоциативную цепочку a + b + c + ... на операнды по порядку
+func concatOperands(e Expr) []Expr {
+	switch x := e.(type) {
+	case *ConcatExpr:
+		return x.Exprs
+	case *BinOpExpr:
+		if x.Operator == "+" && len(x.Lhss) == 1 && len(x.Rhss) == 1 {
+			return append(concatOperands(x.Lhss[0]), x.Rhss[0])
+		}
+	}
+	return []Expr{e}
+}
+
+func (e *BinOpExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
 
 	oper := core.OperMap[e.Operator]
 	// если это равенство в контексте исполнения блока кода, то это присваивание, а не вычисление выражения
@@ -344,31 +507,31 @@ func (e *BinOpExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool
 			Lhss:     e.Lhss,
 			Operator: "=",
 			Rhss:     e.Rhss,
-		}).BinTo(bins, reg, lid, maxreg)
+		}).BinTo(bins, reg, cc, maxreg)
 		return
 	}
 	if len(e.Lhss) != 1 || len(e.Rhss) != 1 {
 		panic(binstmt.NewStringError(e, "С каждой стороны операции может быть только одно выражение"))
 	}
 	// сначала вычисляем левую часть
-	e.Lhss[0].BinTo(bins, reg, lid, false, maxreg)
+	e.Lhss[0].BinTo(bins, reg, cc, false, maxreg)
 	switch oper {
 	case core.LOR:
-		*lid++
-		lab := *lid
+		cc.Labels++
+		lab := cc.Labels
 		// вставляем проверку на истину слева и возвращаем ее, не вычисляя правую часть, иначе возвращаем правую часть
 		bins.Append(binstmt.NewBinJTRUE(reg, lab, e))
-		e.Rhss[0].BinTo(bins, reg, lid, false, maxreg)
+		e.Rhss[0].BinTo(bins, reg, cc, false, maxreg)
 		bins.Append(binstmt.NewBinLABEL(lab, e))
 	case core.LAND:
-		*lid++
-		lab := *lid
+		cc.Labels++
+		lab := cc.Labels
 		// вставляем проверку на ложь слева и возвращаем ее, не вычисляя правую часть, иначе возвращаем правую часть
 		bins.Append(binstmt.NewBinJFALSE(reg, lab, e))
-		e.Rhss[0].BinTo(bins, reg, lid, false, maxreg)
+		e.Rhss[0].BinTo(bins, reg, cc, false, maxreg)
 		bins.Append(binstmt.NewBinLABEL(lab, e))
 	default:
-		e.Rhss[0].BinTo(bins, reg+1, lid, false, maxreg)
+		e.Rhss[0].BinTo(bins, reg+1, cc, false, maxreg)
 		bins.Append(binstmt.NewBinOPER(reg, reg+1, oper, e))
 	}
 	if reg+1 > *maxreg {
@@ -376,6 +539,31 @@ func (e *BinOpExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool
 	}
 }
 
+// ConcatExpr - цепочка сложений a + b + c + ..., в которой есть строковая константа.
+// Создается при упрощении BinOpExpr и вычисляется одной инструкцией: строки собираются в один буфер,
+// а если среди значений окажутся не строки, то сложения выполняются по очереди, как в исходной цепочке
+type ConcatExpr struct {
+	ExprImpl
+	Exprs []Expr
+}
+
+func (x *ConcatExpr) Simplify() Expr {
+	for i := range x.Exprs {
+		x.Exprs[i] = x.Exprs[i].Simplify()
+	}
+	return x
+}
+
+func (e *ConcatExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
+	for i, ee := range e.Exprs {
+		ee.BinTo(bins, reg+i, cc, false, maxreg)
+	}
+	bins.Append(binstmt.NewBinCONCAT(reg, len(e.Exprs), e))
+	if reg+len(e.Exprs)-1 > *maxreg {
+		*maxreg = reg + len(e.Exprs) - 1
+	}
+}
+
 type TernaryOpExpr struct {
 	ExprImpl
 	Expr Expr
@@ -399,20 +587,20 @@ func (x *TernaryOpExpr) Simplify() Expr {
 	return x
 }
 
-func (e *TernaryOpExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
-	e.Expr.BinTo(bins, reg, lid, false, maxreg)
-	*lid++
-	lab := *lid
+func (e *TernaryOpExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
+	e.Expr.BinTo(bins, reg, cc, false, maxreg)
+	cc.Labels++
+	lab := cc.Labels
 	bins.Append(binstmt.NewBinJFALSE(reg, lab, e))
 	// если истина - берем левое выражение
-	e.Lhs.BinTo(bins, reg, lid, false, maxreg)
+	e.Lhs.BinTo(bins, reg, cc, false, maxreg)
 	// прыгаем в конец
-	*lid++
-	lend := *lid
+	cc.Labels++
+	lend := cc.Labels
 	bins.Append(binstmt.NewBinJMP(lend, e))
 	// правое выражение
 	bins.Append(binstmt.NewBinLABEL(lab, e))
-	e.Rhs.BinTo(bins, reg, lid, false, maxreg)
+	e.Rhs.BinTo(bins, reg, cc, false, maxreg)
 	bins.Append(binstmt.NewBinLABEL(lend, e))
 	if reg > *maxreg {
 		*maxreg = reg
@@ -436,7 +624,7 @@ func (x *CallExpr) Simplify() Expr {
 	return x
 }
 
-func (e *CallExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
+func (e *CallExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
 	// если это анонимный вызов, то в reg сама функция, значит, параметры записываем в reg+1, иначе в reg
 	var regoff int
 	if e.Name == 0 {
@@ -450,16 +638,19 @@ func (e *CallExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool,
 	for i, ee := range e.SubExprs {
 		// каждое выражение сохраняем в следующем по номеру регистре
 		ri := reg + regoff + i
-		ee.BinTo(bins, ri, lid, false, maxreg)
+		ee.BinTo(bins, ri, cc, false, maxreg)
 		if ri > *maxreg {
 			*maxreg = ri
 		}
-		// ee.BinTo(bins, reg+sliceoff+regoff, lid, false, maxreg)
+		// ee.BinTo(bins, reg+sliceoff+regoff, cc, false, maxreg)
 		// bins.Append(binstmt.NewBinSETIDX(reg+regoff, i, reg+sliceoff+regoff, ee))
 	}
 
 	// для анонимных (Name==0) - в reg будет функция, иначе первый аргумент (см. выше) или слайс аргументов
-	bins.Append(binstmt.NewBinCALL(e.Name, len(e.SubExprs), reg, reg, e.VarArg, e.Go, e))
+	call := binstmt.NewBinCALL(e.Name, len(e.SubExprs), reg, reg, e.VarArg, e.Go, e)
+	// результат горутины, запущенной отдельным оператором, не сохраняется, поэтому ее ошибку никто не получит
+	call.Detached = e.Go && inStmt
+	bins.Append(call)
 
 	// if reg+regoff+sliceoff > *maxreg {
 	// 	*maxreg = reg + regoff + sliceoff
@@ -486,16 +677,16 @@ func (x *AnonCallExpr) Simplify() Expr {
 	return x
 }
 
-func (e *AnonCallExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
+func (e *AnonCallExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
 	// помещаем в регистр значение функции (тип func, или ссылку на него, или интерфейс с ним)
-	e.Expr.BinTo(bins, reg, lid, false, maxreg)
+	e.Expr.BinTo(bins, reg, cc, false, maxreg)
 	// далее аргументы, как при вызове обычной функции
 	(&CallExpr{
 		Name:     0,
 		SubExprs: e.SubExprs,
 		VarArg:   e.VarArg,
 		Go:       e.Go,
-	}).BinTo(bins, reg, lid, false, maxreg) // передаем именно reg, т.к. он для Name==0 означает функцию, которую надо вызвать в BinCALL
+	}).BinTo(bins, reg, cc, inStmt, maxreg) // передаем именно reg, т.к. он для Name==0 означает функцию, которую надо вызвать в BinCALL
 	if reg > *maxreg {
 		*maxreg = reg
 	}
@@ -510,22 +701,36 @@ type MemberExpr struct {
 
 func (x *MemberExpr) Simplify() Expr {
 	x.Expr = x.Expr.Simplify()
+	// поле константной структуры сворачивается так же, как его прочитает GETMEMBER,
+	// цепочка а.б.в сворачивается целиком, т.к. вложенные обращения упрощаются первыми
+	if v, ok := x.Expr.(*NativeExpr); ok {
+		if m, ok := v.Value.(core.VMStringMap); ok {
+			if rv, ok := m[names.UniqueNames.Get(x.Name)]; ok {
+				return &NativeExpr{Value: rv}
+			}
+			if _, ok := m.MethodMember(x.Name); !ok {
+				return &NativeExpr{Value: core.VMNil}
+			}
+		}
+	}
 	return x
 }
 
-func (e *MemberExpr) BinLetTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
-	e.Expr.BinTo(bins, reg+1, lid, false, maxreg)
+func (e *MemberExpr) BinLetTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
+	e.Expr.BinTo(bins, reg+1, cc, false, maxreg)
 	bins.Append(binstmt.NewBinSETMEMBER(reg+1, e.Name, reg, e))
 	if reg+1 > *maxreg {
 		*maxreg = reg + 1
 	}
 }
 
-func (e *MemberExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
-	e.Expr.BinTo(bins, reg, lid, false, maxreg)
+func (e *MemberExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
+	// значение заменяется его полем в том же регистре, поэтому цепочка а.б.в
+	// читается одним GET и последовательными GETMEMBER без промежуточных пересылок
+	e.Expr.BinTo(bins, reg, cc, false, maxreg)
 	bins.Append(binstmt.NewBinGETMEMBER(reg, e.Name, e))
-	if reg+1 > *maxreg {
-		*maxreg = reg + 1
+	if reg > *maxreg {
+		*maxreg = reg
 	}
 }
 
@@ -539,16 +744,25 @@ type ItemExpr struct {
 func (x *ItemExpr) Simplify() Expr {
 	x.Value = x.Value.Simplify()
 	x.Index = x.Index.Simplify()
+	// сворачиваем только индексы в пределах границ, остальные случаи (в т.ч. ошибки) обрабатываются при исполнении
 	if v, ok := x.Value.(*NativeExpr); ok {
 		if i, ok := x.Index.(*NativeExpr); ok {
-			if vv, ok := v.Value.(core.VMSlicer); ok {
-				if ii, ok := i.Value.(core.VMInt); ok {
-					return &NativeExpr{Value: vv.Slice()[ii.Int()]}
+			switch vv := v.Value.(type) {
+			case core.VMSlice:
+				if ii, ok := i.Value.(core.VMInt); ok && ii >= 0 && int(ii) < len(vv) {
+					return &NativeExpr{Value: vv[ii]}
 				}
-			}
-			if vv, ok := v.Value.(core.VMStringMaper); ok {
+			case core.VMString:
+				r := []rune(string(vv))
+				if ii, ok := i.Value.(core.VMInt); ok && ii >= 0 && int(ii) < len(r) {
+					return &NativeExpr{Value: core.VMString(string(r[ii]))}
+				}
+			case core.VMStringMap:
 				if ii, ok := i.Value.(core.VMString); ok {
-					return &NativeExpr{Value: vv.StringMap()[ii.String()]}
+					if iv, ok := vv[string(ii)]; ok {
+						return &NativeExpr{Value: iv}
+					}
+					return &NativeExpr{Value: core.VMNil}
 				}
 			}
 		}
@@ -556,25 +770,29 @@ func (x *ItemExpr) Simplify() Expr {
 	return x
 }
 
-func (e *ItemExpr) BinLetTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
+func (e *ItemExpr) BinLetTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
+	e.Value.BinTo(bins, reg+1, cc, false, maxreg)
+	e.Index.BinTo(bins, reg+2, cc, false, maxreg)
+	e.binSetItemTo(bins, reg, cc, maxreg)
+}
 
-	*lid++
-	lend := *lid
-	e.Value.BinTo(bins, reg+1, lid, false, maxreg)
-	e.Index.BinTo(bins, reg+2, lid, false, maxreg)
+// binSetItemTo записывает значение из reg в элемент, коллекция и индекс которого уже вычислены в reg+1 и reg+2
+func (e *ItemExpr) binSetItemTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
+	cc.Labels++
+	lend := cc.Labels
 	bins.Append(binstmt.NewBinSETITEM(reg+1, reg+2, reg, reg+3, e))
 	bins.Append(binstmt.NewBinJFALSE(reg+3, lend, e))
 	ee := e.Value.(CanLetExpr)
-	ee.BinLetTo(bins, reg+1, lid, maxreg)
+	ee.BinLetTo(bins, reg+1, cc, maxreg)
 	bins.Append(binstmt.NewBinLABEL(lend, e))
 	if reg+3 > *maxreg {
 		*maxreg = reg + 3
 	}
 }
 
-func (e *ItemExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
-	e.Value.BinTo(bins, reg, lid, false, maxreg)
-	e.Index.BinTo(bins, reg+1, lid, false, maxreg)
+func (e *ItemExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
+	e.Value.BinTo(bins, reg, cc, false, maxreg)
+	e.Index.BinTo(bins, reg+1, cc, false, maxreg)
 	bins.Append(binstmt.NewBinGETIDX(reg, reg+1, e))
 	if reg+1 > *maxreg {
 		*maxreg = reg + 1
@@ -593,13 +811,22 @@ func (x *SliceExpr) Simplify() Expr {
 	x.Value = x.Value.Simplify()
 	x.Begin = x.Begin.Simplify()
 	x.End = x.End.Simplify()
+	// сворачиваем только диапазоны в пределах границ, отрицательные и выходящие за границы вычисляются при исполнении
 	if v, ok := x.Value.(*NativeExpr); ok {
 		if ib, ok := x.Begin.(*NativeExpr); ok {
 			if ie, ok := x.End.(*NativeExpr); ok {
-				if vv, ok := v.Value.(core.VMSlicer); ok {
-					if iib, ok := ib.Value.(core.VMInt); ok {
-						if iie, ok := ie.Value.(core.VMInt); ok {
-							return &NativeExpr{Value: vv.Slice()[iib.Int():iie.Int()]}
+				iib, okb := ib.Value.(core.VMInt)
+				iie, oke := ie.Value.(core.VMInt)
+				if okb && oke && iib >= 0 && iib <= iie {
+					switch vv := v.Value.(type) {
+					case core.VMSlice:
+						if int(iie) <= len(vv) {
+							return &NativeExpr{Value: vv[iib:iie]}
+						}
+					case core.VMString:
+						r := []rune(string(vv))
+						if int(iie) <= len(r) {
+							return &NativeExpr{Value: core.VMString(string(r[iib:iie]))}
 						}
 					}
 				}
@@ -609,27 +836,27 @@ func (x *SliceExpr) Simplify() Expr {
 	return x
 }
 
-func (e *SliceExpr) BinLetTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
-	*lid++
-	lend := *lid
-	e.Value.BinTo(bins, reg+1, lid, false, maxreg)
-	e.Begin.BinTo(bins, reg+2, lid, false, maxreg)
-	e.End.BinTo(bins, reg+3, lid, false, maxreg)
+func (e *SliceExpr) BinLetTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
+	cc.Labels++
+	lend := cc.Labels
+	e.Value.BinTo(bins, reg+1, cc, false, maxreg)
+	e.Begin.BinTo(bins, reg+2, cc, false, maxreg)
+	e.End.BinTo(bins, reg+3, cc, false, maxreg)
 	bins.Append(binstmt.NewBinSETSLICE(reg+1, reg+2, reg+3, reg, reg+4, e))
 
 	bins.Append(binstmt.NewBinJFALSE(reg+4, lend, e))
 	ee := e.Value.(CanLetExpr)
-	ee.BinLetTo(bins, reg+1, lid, maxreg)
+	ee.BinLetTo(bins, reg+1, cc, maxreg)
 	bins.Append(binstmt.NewBinLABEL(lend, e))
 	if reg+4 > *maxreg {
 		*maxreg = reg + 4
 	}
 }
 
-func (e *SliceExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
-	e.Value.BinTo(bins, reg, lid, false, maxreg)
-	e.Begin.BinTo(bins, reg+1, lid, false, maxreg)
-	e.End.BinTo(bins, reg+2, lid, false, maxreg)
+func (e *SliceExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
+	e.Value.BinTo(bins, reg, cc, false, maxreg)
+	e.Begin.BinTo(bins, reg+1, cc, false, maxreg)
+	e.End.BinTo(bins, reg+2, cc, false, maxreg)
 	bins.Append(binstmt.NewBinGETSUBSLICE(reg, reg+1, reg+2, e))
 	if reg+2 > *maxreg {
 		*maxreg = reg + 2
@@ -643,6 +870,9 @@ type FuncExpr struct {
 	Stmts  Stmts
 	Args   []int //string
 	VarArg bool
+	// для методов структур: имя получателя и тип структуры
+	Receiver int //string
+	RecvType int //string
 }
 
 func (x *FuncExpr) Simplify() Expr {
@@ -652,21 +882,90 @@ func (x *FuncExpr) Simplify() Expr {
 	return x
 }
 
-func (e *FuncExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
-	*lid++
-	lstart := *lid
-	*lid++
-	lend := *lid
+func (e *FuncExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
+	cc.Labels++
+	lstart := cc.Labels
+	cc.Labels++
+	lend := cc.Labels
 	ii := len(*bins)
-	bins.Append(binstmt.NewBinFUNC(reg, e.Name, e.Args, e.VarArg, lstart, lend, e))
+	if e.RecvType != 0 {
+		// получатель метода передается первым аргументом
+		args := append([]int{e.Receiver}, e.Args...)
+		fn := binstmt.NewBinFUNC(reg, e.Name, args, e.VarArg, lstart, lend, e)
+		fn.RecvType = e.RecvType
+		bins.Append(fn)
+	} else {
+		bins.Append(binstmt.NewBinFUNC(reg, e.Name, e.Args, e.VarArg, lstart, lend, e))
+	}
 	bins.Append(binstmt.NewBinLABEL(lstart, e))
-	e.Stmts.BinTo(bins, reg, lid, maxreg)
-	bins.Append(binstmt.NewBinRET(reg, e))
+	// тело исполняется в собственном наборе регистров, поэтому нумерация регистров в нем начинается с нуля,
+	// а размер набора определяется только регистрами самой функции, а не окружающего кода
+	fmaxreg := 0
+	e.markGlobals()
+	inBlock(cc, (*bins)[ii], func() { e.Stmts.BinTo(bins, 0, cc, &fmaxreg) })
+	bins.Append(binstmt.NewBinRET(0, e))
 	bins.Append(binstmt.NewBinLABEL(lend, e))
 	if reg > *maxreg {
 		*maxreg = reg
 	}
-	(*bins)[ii].(*binstmt.BinFUNC).MaxReg = *maxreg
+	(*bins)[ii].(*binstmt.BinFUNC).MaxReg = fmaxreg
+}
+
+// markGlobals отмечает в теле функции присваивания переменным, объявленным как Глобальная.
+// Объявление действует во всем теле функции, но не во вложенных функциях
+func (e *FuncExpr) markGlobals() {
+	ids := make(map[int]bool)
+	walkFuncBody(reflect.ValueOf(e.Stmts), func(n interface{}) {
+		if g, ok := n.(*GlobalStmt); ok {
+			for _, id := range g.Names {
+				ids[id] = true
+			}
+		}
+	})
+	if len(ids) == 0 {
+		return
+	}
+	for _, arg := range append([]int{e.Receiver}, e.Args...) {
+		if ids[arg] {
+			panic(binstmt.NewStringError(e, "Параметр функции '"+names.UniqueNames.Get(arg)+"' не может быть глобальной переменной"))
+		}
+	}
+	walkFuncBody(reflect.ValueOf(e.Stmts), func(n interface{}) {
+		if id, ok := n.(*IdentExpr); ok && ids[id.Id] {
+			id.Global = true
+		}
+	})
+}
+
+// walkFuncBody обходит узлы дерева, не заходя во вложенные функции
+func walkFuncBody(v reflect.Value, f func(interface{})) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		if _, ok := v.Interface().(*FuncExpr); ok {
+			return
+		}
+		if v.Kind() == reflect.Ptr {
+			f(v.Interface())
+		}
+		walkFuncBody(v.Elem(), f)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if ft := v.Field(i).Type(); isNode(ft) || ft == reflectStmts || isNodeSlice(ft) {
+				walkFuncBody(v.Field(i), f)
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			walkFuncBody(v.Index(i), f)
+		}
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			walkFuncBody(v.MapIndex(k), f)
+		}
+	}
 }
 
 // LetExpr provide expression to let variable.
@@ -682,9 +981,9 @@ func (x *LetExpr) Simplify() Expr {
 	return x
 }
 
-func (e *LetExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
-	e.Rhs.BinTo(bins, reg, lid, false, maxreg)
-	e.Lhs.(CanLetExpr).BinLetTo(bins, reg, lid, maxreg)
+func (e *LetExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
+	e.Rhs.BinTo(bins, reg, cc, false, maxreg)
+	e.Lhs.(CanLetExpr).BinLetTo(bins, reg, cc, maxreg)
 	if reg > *maxreg {
 		*maxreg = reg
 	}
@@ -704,38 +1003,82 @@ type AssocExpr struct {
 	Lhs      Expr
 	Operator string
 	Rhs      Expr
+	Prefix   bool // префиксная форма ++и, --и
 }
 
 func (x *AssocExpr) Simplify() Expr {
 	x.Lhs = x.Lhs.Simplify()
-	x.Rhs = x.Rhs.Simplify()
+	if x.Rhs != nil {
+		x.Rhs = x.Rhs.Simplify()
+	}
 	return x
 }
 
-func (e *AssocExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
+func (e *AssocExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
+	incdec := e.Operator == "++" || e.Operator == "--"
+	lhs, ok := e.Lhs.(CanLetExpr)
+	if !ok {
+		switch e.Operator {
+		case "++":
+			panic(binstmt.NewStringError(e, "Инкремент применим только к переменным, элементам и полям"))
+		case "--":
+			panic(binstmt.NewStringError(e, "Декремент применим только к переменным, элементам и полям"))
+		}
+		panic(binstmt.NewStringError(e, "Присваивание возможно только переменным, элементам и полям"))
+	}
+
+	// чтение текущего значения в reg, при этом коллекция, индекс и объект вычисляются однократно
+	// и остаются в регистрах для последующей записи; tmp - первый свободный после них регистр
+	tmp := reg + 1
+	switch l := lhs.(type) {
+	case *IdentExpr:
+		bins.Append(binstmt.NewBinGET(reg, l.Id, l))
+	case *ItemExpr:
+		l.Value.BinTo(bins, reg+1, cc, false, maxreg)
+		l.Index.BinTo(bins, reg+2, cc, false, maxreg)
+		bins.Append(binstmt.NewBinMV(reg+1, reg, l))
+		bins.Append(binstmt.NewBinGETIDX(reg, reg+2, l))
+		tmp = reg + 4
+	case *MemberExpr:
+		l.Expr.BinTo(bins, reg+1, cc, false, maxreg)
+		bins.Append(binstmt.NewBinMV(reg+1, reg, l))
+		bins.Append(binstmt.NewBinGETMEMBER(reg, l.Name, l))
+		tmp = reg + 2
+	default:
+		lhs.BinTo(bins, reg, cc, false, maxreg)
+	}
+
+	// постфиксная форма в выражении возвращает прежнее значение
+	postfix := incdec && !inStmt && !e.Prefix
+	if postfix {
+		bins.Append(binstmt.NewBinMV(reg, tmp, e))
+		tmp++
+	}
+
 	switch e.Operator {
 	case "++":
-		if alhs, ok := e.Lhs.(*IdentExpr); ok {
-			bins.Append(binstmt.NewBinGET(reg, alhs.Id, alhs))
-			bins.Append(binstmt.NewBinINC(reg, alhs))
-			bins.Append(binstmt.NewBinSET(reg, alhs.Id, alhs))
-		} else {
-			panic(binstmt.NewStringError(alhs, "Инкремент применим только к переменным"))
-		}
+		bins.Append(binstmt.NewBinINC(reg, e))
 	case "--":
-		if alhs, ok := e.Lhs.(*IdentExpr); ok {
-			bins.Append(binstmt.NewBinGET(reg, alhs.Id, alhs))
-			bins.Append(binstmt.NewBinDEC(reg, alhs))
-			bins.Append(binstmt.NewBinSET(reg, alhs.Id, alhs))
-		} else {
-			panic(binstmt.NewStringError(alhs, "Декремент применим только к переменным"))
-		}
+		bins.Append(binstmt.NewBinDEC(reg, e))
 	default:
-		(&BinOpExpr{Lhss: []Expr{e.Lhs}, Operator: e.Operator[0:1], Rhss: []Expr{e.Rhs}}).BinTo(bins, reg, lid, false, maxreg)
-		e.Lhs.(CanLetExpr).BinLetTo(bins, reg, lid, maxreg)
+		e.Rhs.BinTo(bins, tmp, cc, false, maxreg)
+		bins.Append(binstmt.NewBinOPER(reg, tmp, core.OperMap[e.Operator[0:1]], e))
 	}
-	if reg > *maxreg {
-		*maxreg = reg
+	if tmp > *maxreg {
+		*maxreg = tmp
+	}
+
+	switch l := lhs.(type) {
+	case *ItemExpr:
+		l.binSetItemTo(bins, reg, cc, maxreg)
+	case *MemberExpr:
+		bins.Append(binstmt.NewBinSETMEMBER(reg+1, l.Name, reg, l))
+	default:
+		lhs.BinLetTo(bins, reg, cc, maxreg)
+	}
+
+	if postfix {
+		bins.Append(binstmt.NewBinMV(tmp-1, reg, e))
 	}
 }
 
@@ -765,7 +1108,7 @@ func (x *ConstExpr) Simplify() Expr {
 	return x
 }
 
-func (e *ConstExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
+func (e *ConstExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
 	var v core.VMValuer
 
 	switch names.FastToLower(e.Value) {
@@ -799,33 +1142,33 @@ func (x *ChanExpr) Simplify() Expr {
 	return x
 }
 
-func (e *ChanExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
+func (e *ChanExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
 	// определяем значение справа
-	e.Rhs.BinTo(bins, reg+1, lid, false, maxreg)
+	e.Rhs.BinTo(bins, reg+1, cc, false, maxreg)
 	if e.Lhs == nil {
 		// слева нет значения - это временное чтение из канала без сохранения значения в переменной
 		bins.Append(binstmt.NewBinCHANRECV(reg+1, reg, e))
 	} else {
 		// значение слева
-		e.Lhs.BinTo(bins, reg+2, lid, false, maxreg)
+		e.Lhs.BinTo(bins, reg+2, cc, false, maxreg)
 		bins.Append(binstmt.NewBinMV(reg+2, reg+3, e))
 		// слева канал - пишем в него правое
 		bins.Append(binstmt.NewBinISKIND(reg+3, reflect.Chan, e))
-		*lid++
-		li := *lid
+		cc.Labels++
+		li := cc.Labels
 		bins.Append(binstmt.NewBinJFALSE(reg+3, li, e))
 		bins.Append(binstmt.NewBinCHANSEND(reg+2, reg+1, e))
 		bins.Append(binstmt.NewBinLOAD(reg, core.VMBool(true), false, e))
 
-		*lid++
-		li2 := *lid
+		cc.Labels++
+		li2 := cc.Labels
 
 		bins.Append(binstmt.NewBinJMP(li2, e))
 
 		// иначе справа канал, а слева переменная (установим, если прочитали из канала)
 		bins.Append(binstmt.NewBinLABEL(li, e))
 		bins.Append(binstmt.NewBinCHANRECV(reg+1, reg, e))
-		e.Lhs.(CanLetExpr).BinLetTo(bins, reg, lid, maxreg)
+		e.Lhs.(CanLetExpr).BinLetTo(bins, reg, cc, maxreg)
 
 		bins.Append(binstmt.NewBinLABEL(li2, e))
 	}
@@ -853,12 +1196,12 @@ func (x *TypeCast) Simplify() Expr {
 	return x
 }
 
-func (e *TypeCast) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
-	e.CastExpr.BinTo(bins, reg, lid, false, maxreg)
+func (e *TypeCast) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
+	e.CastExpr.BinTo(bins, reg, cc, false, maxreg)
 	if e.TypeExpr == nil {
 		bins.Append(binstmt.NewBinLOAD(reg+1, core.VMInt(e.Type), true, e))
 	} else {
-		e.TypeExpr.BinTo(bins, reg+1, lid, false, maxreg)
+		e.TypeExpr.BinTo(bins, reg+1, cc, false, maxreg)
 		bins.Append(binstmt.NewBinSETNAME(reg+1, e))
 	}
 	bins.Append(binstmt.NewBinCASTTYPE(reg, reg+1, e))
@@ -880,11 +1223,11 @@ func (x *MakeExpr) Simplify() Expr {
 	return x
 }
 
-func (e *MakeExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
+func (e *MakeExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
 	if e.TypeExpr == nil {
 		bins.Append(binstmt.NewBinLOAD(reg, core.VMInt(e.Type), true, e))
 	} else {
-		e.TypeExpr.BinTo(bins, reg, lid, false, maxreg)
+		e.TypeExpr.BinTo(bins, reg, cc, false, maxreg)
 		bins.Append(binstmt.NewBinSETNAME(reg, e))
 	}
 	bins.Append(binstmt.NewBinMAKE(reg, e))
@@ -906,11 +1249,12 @@ func (x *MakeChanExpr) Simplify() Expr {
 	return x
 }
 
-func (e *MakeChanExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
-	if e.SizeExpr == nil {
+func (e *MakeChanExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
+	// без указания размера (новый канал) создается небуферизованный канал
+	if _, ok := e.SizeExpr.(*NoneExpr); ok || e.SizeExpr == nil {
 		bins.Append(binstmt.NewBinLOAD(reg, core.VMInt(0), false, e))
 	} else {
-		e.SizeExpr.BinTo(bins, reg, lid, false, maxreg)
+		e.SizeExpr.BinTo(bins, reg, cc, false, maxreg)
 	}
 	bins.Append(binstmt.NewBinMAKECHAN(reg, e))
 	if reg > *maxreg {
@@ -933,12 +1277,12 @@ func (x *MakeArrayExpr) Simplify() Expr {
 	return x
 }
 
-func (e *MakeArrayExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
-	e.LenExpr.BinTo(bins, reg, lid, false, maxreg)
+func (e *MakeArrayExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
+	e.LenExpr.BinTo(bins, reg, cc, false, maxreg)
 	if e.CapExpr == nil {
 		bins.Append(binstmt.NewBinMV(reg, reg+1, e))
 	} else {
-		e.CapExpr.BinTo(bins, reg+1, lid, false, maxreg)
+		e.CapExpr.BinTo(bins, reg+1, cc, false, maxreg)
 	}
 	bins.Append(binstmt.NewBinMAKEARR(reg, reg+1, e))
 	if reg+1 > *maxreg {
@@ -956,7 +1300,7 @@ func (x *NativeExpr) Simplify() Expr {
 	return x
 }
 
-func (e *NativeExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
+func (e *NativeExpr) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, inStmt bool, maxreg *int) {
 	bins.Append(binstmt.NewBinLOAD(reg, e.Value, false, e))
 	if reg > *maxreg {
 		*maxreg = reg
@@ -0,0 +1,73 @@
+package ast
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/shinanca/gonec/bincode/binstmt"
+	"github.com/shinanca/gonec/core"
+	"github.com/shinanca/gonec/names"
+)
+
+func TestUnaryExprBinTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		operator string
+		wantOp   rune
+		wantErr  bool
+	}{
+		{name: "минус", operator: "-", wantOp: '-'},
+		{name: "не", operator: "!", wantOp: '!'},
+		{name: "побитовое не", operator: "^", wantOp: '^'},
+		{name: "неизвестный", operator: "!!", wantErr: true},
+		{name: "пустой", operator: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &UnaryExpr{Operator: tt.operator, Expr: &IdentExpr{Lit: "а", Id: names.UniqueNames.Set("а")}}
+			var bins binstmt.BinStmts
+			var cc CompileCtx
+			maxreg := 0
+			err := func() (err error) {
+				defer func() {
+					if ex := recover(); ex != nil {
+						err = ex.(error)
+					}
+				}()
+				e.BinTo(&bins, 0, &cc, false, &maxreg)
+				return nil
+			}()
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "Неизвестный унарный оператор") {
+					t.Errorf("ошибка = %v, ожидалась ошибка неизвестного оператора", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ошибка компиляции: %v", err)
+			}
+			un, ok := bins[len(bins)-1].(*binstmt.BinUNARY)
+			if !ok {
+				t.Fatalf("последняя инструкция %v, ожидалась UNARY", bins[len(bins)-1])
+			}
+			if un.Op != tt.wantOp {
+				t.Errorf("UNARY.Op = %q, ожидался %q", un.Op, tt.wantOp)
+			}
+		})
+	}
+}
+
+func TestBinOpExprSimplifyOverflow(t *testing.T) {
+	native := func(v core.VMValuer) Expr { return &NativeExpr{Value: v} }
+
+	e := &BinOpExpr{Lhss: []Expr{native(core.VMInt(math.MaxInt64))}, Operator: "+", Rhss: []Expr{native(core.VMInt(1))}}
+	if _, ok := e.Simplify().(*NativeExpr); ok {
+		t.Error("переполнение не должно сворачиваться в константу")
+	}
+
+	e = &BinOpExpr{Lhss: []Expr{native(core.VMInt(math.MaxInt64 - 1))}, Operator: "+", Rhss: []Expr{native(core.VMInt(1))}}
+	if n, ok := e.Simplify().(*NativeExpr); !ok || n.Value != core.VMInt(math.MaxInt64) {
+		t.Errorf("свертка = %v, ожидалась константа %d", e.Simplify(), int64(math.MaxInt64))
+	}
+}
@@ -0,0 +1,564 @@
+package ast
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/shinanca/gonec/core"
+	"github.com/shinanca/gonec/names"
+)
+
+// Форматирование дерева AST обратно в исходный код.
+// Структура кода сохраняется, комментарии и исходное форматирование - нет.
+// Полученный код снова разбирается в эквивалентное дерево, что удобно для инструментов
+// и для отладки оптимизаций Simplify (свернутые константы выводятся как литералы)
+
+// printer накапливает текст с учетом текущего отступа
+type printer struct {
+	sb     strings.Builder
+	indent int
+}
+
+func (p *printer) write(ss ...string) {
+	for _, s := range ss {
+		p.sb.WriteString(s)
+	}
+}
+
+// line начинает новую строку с текущим отступом
+func (p *printer) line() {
+	p.sb.WriteByte('\n')
+	for i := 0; i < p.indent; i++ {
+		p.sb.WriteByte('\t')
+	}
+}
+
+// block выводит операторы блока с увеличенным отступом, каждый на новой строке
+func (p *printer) block(x Stmts) {
+	p.indent++
+	for _, st := range x {
+		p.line()
+		st.format(p)
+	}
+	p.indent--
+}
+
+func (p *printer) exprs(es []Expr) {
+	for i, e := range es {
+		if i > 0 {
+			p.write(", ")
+		}
+		e.format(p)
+	}
+}
+
+func (p *printer) idents(ids []int) {
+	for i, id := range ids {
+		if i > 0 {
+			p.write(", ")
+		}
+		p.write(names.UniqueNames.Get(id))
+	}
+}
+
+// quote записывает строку в кавычках, экранируя символы так, как их разбирает лексер
+func quote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`, "\b", `\b`, "\f", `\f`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// String возвращает исходный код операторов, по одному на строке
+func (x Stmts) String() string {
+	p := &printer{}
+	for i, st := range x {
+		if i > 0 {
+			p.line()
+		}
+		st.format(p)
+	}
+	return p.sb.String()
+}
+
+// ExprString возвращает исходный код выражения
+func ExprString(e Expr) string {
+	p := &printer{}
+	e.format(p)
+	return p.sb.String()
+}
+
+// StmtString возвращает исходный код оператора
+func StmtString(s Stmt) string {
+	p := &printer{}
+	s.format(p)
+	return p.sb.String()
+}
+
+//////////////////////
+// выражения
+//////////////////////
+
+func (x *NoneExpr) format(p *printer) {}
+
+func (x *NumberExpr) format(p *printer) { p.write(x.Lit) }
+
+func (x *StringExpr) format(p *printer) { p.write(quote(x.Lit)) }
+
+func (x *ArrayExpr) format(p *printer) {
+	p.write("[")
+	p.exprs(x.Exprs)
+	if x.Rest != nil {
+		p.write(", ...")
+		x.Rest.format(p)
+	}
+	p.write("]")
+}
+
+func (x *PairExpr) format(p *printer) {
+	p.write(quote(x.Key), ": ")
+	x.Value.format(p)
+}
+
+func (x *MapExpr) format(p *printer) {
+	keys := make([]string, 0, len(x.MapExpr))
+	for k := range x.MapExpr {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	p.write("{")
+	for i, k := range keys {
+		if i > 0 {
+			p.write(", ")
+		}
+		p.write(quote(k), ": ")
+		x.MapExpr[k].format(p)
+	}
+	p.write("}")
+}
+
+func (x *IdentExpr) format(p *printer) { p.write(x.Lit) }
+
+func (x *UnaryExpr) format(p *printer) {
+	if x.Operator == "!" {
+		p.write("не ")
+		x.Expr.format(p)
+		return
+	}
+	sub := printer{indent: p.indent}
+	x.Expr.format(&sub)
+	s := sub.sb.String()
+	p.write(x.Operator)
+	// "--" и "++" читаются как уменьшение и увеличение, поэтому одинаковые знаки разделяются пробелом
+	if strings.HasPrefix(s, x.Operator) {
+		p.write(" ")
+	}
+	p.write(s)
+}
+
+func (x *TryExpr) format(p *printer) {
+	p.write("попытаться ")
+	x.Expr.format(p)
+}
+
+func (x *ParenExpr) format(p *printer) {
+	p.write("(")
+	x.SubExpr.format(p)
+	p.write(")")
+}
+
+// binOpSource - запись операторов в исходном коде, если она отличается от внутренней
+var binOpSource = map[string]string{
+	"==": "=",
+	"!=": "<>",
+	"&&": "и",
+	"||": "или",
+}
+
+func (x *BinOpExpr) format(p *printer) {
+	op, ok := binOpSource[x.Operator]
+	if !ok {
+		op = x.Operator
+	}
+	p.exprs(x.Lhss)
+	p.write(" ", op, " ")
+	p.exprs(x.Rhss)
+}
+
+func (x *ConcatExpr) format(p *printer) {
+	for i, e := range x.Exprs {
+		if i > 0 {
+			p.write(" + ")
+		}
+		e.format(p)
+	}
+}
+
+func (x *TernaryOpExpr) format(p *printer) {
+	p.write("?(")
+	p.exprs([]Expr{x.Expr, x.Lhs, x.Rhs})
+	p.write(")")
+}
+
+func (x *CallExpr) format(p *printer) {
+	if x.Go {
+		p.write("старт ")
+	}
+	p.write(names.UniqueNames.Get(x.Name), "(")
+	p.exprs(x.SubExprs)
+	if x.VarArg {
+		p.write("...")
+	}
+	p.write(")")
+}
+
+func (x *AnonCallExpr) format(p *printer) {
+	if x.Go {
+		p.write("старт ")
+	}
+	x.Expr.format(p)
+	p.write("(")
+	p.exprs(x.SubExprs)
+	if x.VarArg {
+		p.write("...")
+	}
+	p.write(")")
+}
+
+func (x *MemberExpr) format(p *printer) {
+	x.Expr.format(p)
+	p.write(".", names.UniqueNames.Get(x.Name))
+}
+
+func (x *ItemExpr) format(p *printer) {
+	x.Value.format(p)
+	p.write("[")
+	x.Index.format(p)
+	p.write("]")
+}
+
+func (x *SliceExpr) format(p *printer) {
+	x.Value.format(p)
+	p.write("[")
+	x.Begin.format(p)
+	p.write(":")
+	x.End.format(p)
+	p.write("]")
+}
+
+func (x *FuncExpr) format(p *printer) {
+	p.write("функция")
+	if x.Receiver != 0 {
+		p.write(" (", names.UniqueNames.Get(x.Receiver), " ", names.UniqueNames.Get(x.RecvType), ")")
+	}
+	if name := names.UniqueNames.Get(x.Name); name != "<анонимная функция>" {
+		p.write(" ", name)
+	}
+	p.write("(")
+	p.idents(x.Args)
+	if x.VarArg {
+		p.write("...")
+	}
+	p.write(")")
+	p.block(x.Stmts)
+	p.line()
+	p.write("конецфункции")
+}
+
+func (x *LetExpr) format(p *printer) {
+	x.Lhs.format(p)
+	p.write(" = ")
+	x.Rhs.format(p)
+}
+
+func (x *AssocExpr) format(p *printer) {
+	switch {
+	case x.Rhs != nil:
+		x.Lhs.format(p)
+		p.write(" ", x.Operator, " ")
+		x.Rhs.format(p)
+	case x.Prefix:
+		p.write(x.Operator)
+		x.Lhs.format(p)
+	default:
+		x.Lhs.format(p)
+		p.write(x.Operator)
+	}
+}
+
+func (x *ConstExpr) format(p *printer) { p.write(x.Value) }
+
+func (x *ChanExpr) format(p *printer) {
+	if x.Lhs != nil {
+		x.Lhs.format(p)
+		p.write(" ")
+	}
+	p.write("<- ")
+	x.Rhs.format(p)
+}
+
+func (x *TypeCast) format(p *printer) {
+	if x.TypeExpr != nil {
+		p.write("новый(")
+		p.exprs([]Expr{x.TypeExpr, x.CastExpr})
+		p.write(")")
+		return
+	}
+	p.write(names.UniqueNames.Get(x.Type), "(")
+	x.CastExpr.format(p)
+	p.write(")")
+}
+
+func (x *MakeExpr) format(p *printer) {
+	if x.TypeExpr != nil {
+		p.write("новый(")
+		x.TypeExpr.format(p)
+		p.write(")")
+		return
+	}
+	p.write("новый ", names.UniqueNames.Get(x.Type))
+}
+
+func (x *MakeChanExpr) format(p *printer) {
+	p.write("новый канал")
+	if _, ok := x.SizeExpr.(*NoneExpr); !ok && x.SizeExpr != nil {
+		p.write("(")
+		x.SizeExpr.format(p)
+		p.write(")")
+	}
+}
+
+func (x *MakeArrayExpr) format(p *printer) {
+	p.write("[](")
+	x.LenExpr.format(p)
+	if x.CapExpr != nil {
+		p.write(", ")
+		x.CapExpr.format(p)
+	}
+	p.write(")")
+}
+
+func (x *NativeExpr) format(p *printer) { p.write(valueSource(x.Value)) }
+
+// valueSource возвращает литерал значения, полученного сверткой констант
+func valueSource(v core.VMValuer) string {
+	switch vv := v.(type) {
+	case nil, core.VMNilType:
+		return "неопределено"
+	case core.VMNullType:
+		return "null"
+	case core.VMBool:
+		if vv {
+			return "истина"
+		}
+		return "ложь"
+	case core.VMString:
+		return quote(string(vv))
+	case core.VMSlice:
+		ss := make([]string, len(vv))
+		for i := range vv {
+			ss[i] = valueSource(vv[i])
+		}
+		return "[" + strings.Join(ss, ", ") + "]"
+	case core.VMStringMap:
+		keys := make([]string, 0, len(vv))
+		for k := range vv {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		ss := make([]string, len(keys))
+		for i, k := range keys {
+			ss[i] = quote(k) + ": " + valueSource(vv[k])
+		}
+		return "{" + strings.Join(ss, ", ") + "}"
+	case core.VMStringer:
+		return vv.String()
+	}
+	return "неопределено"
+}
+
+//////////////////////
+// операторы
+//////////////////////
+
+func (x *NoneStmt) format(p *printer) {}
+
+func (x *ExprStmt) format(p *printer) { x.Expr.format(p) }
+
+func (x *IfStmt) format(p *printer) {
+	p.write("если ")
+	x.If.format(p)
+	p.write(" тогда")
+	p.block(x.Then)
+	for _, st := range x.ElseIf {
+		elif := st.(*IfStmt)
+		p.line()
+		p.write("иначеесли ")
+		elif.If.format(p)
+		p.write(" тогда")
+		p.block(elif.Then)
+	}
+	if len(x.Else) > 0 {
+		p.line()
+		p.write("иначе")
+		p.block(x.Else)
+	}
+	p.line()
+	p.write("конецесли")
+}
+
+func (x *TryStmt) format(p *printer) {
+	p.write("попытка")
+	p.block(x.Try)
+	if !x.NoCatch {
+		p.line()
+		p.write("исключение")
+		p.block(x.Catch)
+	}
+	if x.HasFinally() {
+		p.line()
+		p.write("окончательно")
+		p.block(x.Finally)
+	}
+	p.line()
+	p.write("конецпопытки")
+}
+
+func (x *WithStmt) format(p *printer) {
+	p.write("используя ", names.UniqueNames.Get(x.Var), " = ")
+	x.Expr.format(p)
+	p.block(x.Stmts)
+	p.line()
+	p.write("конециспользования")
+}
+
+func (x *ForStmt) format(p *printer) {
+	p.write("для каждого ", names.UniqueNames.Get(x.Var), " из ")
+	x.Value.format(p)
+	p.write(" цикл")
+	p.block(x.Stmts)
+	p.line()
+	p.write("конеццикла")
+}
+
+func (x *NumForStmt) format(p *printer) {
+	p.write("для ", names.UniqueNames.Get(x.Name), " = ")
+	x.Expr1.format(p)
+	p.write(" по ")
+	x.Expr2.format(p)
+	p.write(" цикл")
+	p.block(x.Stmts)
+	p.line()
+	p.write("конеццикла")
+}
+
+func (x *LoopStmt) format(p *printer) {
+	p.write("пока ")
+	x.Expr.format(p)
+	p.write(" цикл")
+	p.block(x.Stmts)
+	p.line()
+	p.write("конеццикла")
+}
+
+func (x *BreakStmt) format(p *printer) { p.write("прервать") }
+
+func (x *ContinueStmt) format(p *printer) { p.write("продолжить") }
+
+func (x *ReturnStmt) format(p *printer) {
+	p.write("возврат")
+	if len(x.Exprs) > 0 {
+		p.write(" ")
+		p.exprs(x.Exprs)
+	}
+}
+
+func (x *GlobalStmt) format(p *printer) {
+	p.write("глобальная ")
+	for i, id := range x.Names {
+		if i > 0 {
+			p.write(", ")
+		}
+		p.write(names.UniqueNames.Get(id))
+	}
+}
+
+func (x *ThrowStmt) format(p *printer) {
+	p.write("вызватьисключение ")
+	x.Expr.format(p)
+}
+
+func (x *ModuleStmt) format(p *printer) {
+	p.write("модуль ", names.UniqueNames.Get(x.Name))
+	for _, st := range x.Stmts {
+		p.line()
+		st.format(p)
+	}
+}
+
+func (x *SwitchStmt) format(p *printer) {
+	p.write("выбор ")
+	x.Expr.format(p)
+	p.write(":")
+	p.block(x.Cases)
+	p.line()
+	p.write("конецвыбора")
+}
+
+func (x *TypeSwitchStmt) format(p *printer) {
+	p.write("выбор по типу ")
+	if x.Var != 0 {
+		p.write(names.UniqueNames.Get(x.Var), " = ")
+	}
+	x.Expr.format(p)
+	p.write(":")
+	p.block(x.Cases)
+	p.line()
+	p.write("конецвыбора")
+}
+
+func (x *TypeCaseStmt) format(p *printer) {
+	p.write("когда ")
+	p.idents(x.Types)
+	p.write(":")
+	p.block(x.Stmts)
+}
+
+func (x *SelectStmt) format(p *printer) {
+	p.write("выбор:")
+	p.block(x.Cases)
+	p.line()
+	p.write("конецвыбора")
+}
+
+func (x *CaseStmt) format(p *printer) {
+	p.write("когда ")
+	x.Expr.format(p)
+	if x.To != nil {
+		p.write(" по ")
+		x.To.format(p)
+	}
+	p.write(":")
+	p.block(x.Stmts)
+}
+
+func (x *DefaultStmt) format(p *printer) {
+	p.write("другое:")
+	p.block(x.Stmts)
+}
+
+func (x *LetsStmt) format(p *printer) {
+	p.exprs(x.Lhss)
+	p.write(" ", x.Operator, " ")
+	p.exprs(x.Rhss)
+}
+
+func (x *VarStmt) format(p *printer) {
+	p.idents(x.Names)
+	p.write(" = ")
+	p.exprs(x.Exprs)
+}
+
+func (x *StructStmt) format(p *printer) {
+	p.write("структура ", names.UniqueNames.Get(x.Name), " {")
+	p.idents(x.Fields)
+	p.write("}")
+}
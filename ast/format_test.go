@@ -0,0 +1,171 @@
+package ast_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/shinanca/gonec/ast"
+	"github.com/shinanca/gonec/parser"
+	"github.com/shinanca/gonec/pos"
+)
+
+func parse(t *testing.T, src string) ast.Stmts {
+	t.Helper()
+	scanner := &parser.Scanner{}
+	scanner.Init(src)
+	stmts, err := parser.Parse(scanner)
+	if err != nil {
+		t.Fatalf("ошибка разбора: %v\n%s", err, src)
+	}
+	return stmts
+}
+
+var reflectPosImpl = reflect.TypeOf(pos.PosImpl{})
+
+// clearPositions обнуляет позиции во всем дереве, чтобы сравнивать только структуру
+func clearPositions(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			clearPositions(v.Elem())
+		}
+	case reflect.Struct:
+		if v.Type() == reflectPosImpl {
+			if v.CanSet() {
+				v.Set(reflect.Zero(reflectPosImpl))
+			}
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			clearPositions(v.Field(i))
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			clearPositions(v.Index(i))
+		}
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			clearPositions(v.MapIndex(k))
+		}
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{
+			name: "выражения",
+			src: `модуль _
+а = -(1 + 2) * 3 ** 2 % 4
+б = не истина и (а <> 3 или а >= 1)
+в = [1, "два\n\"три\"", 3.5, неопределено]
+г = {"ключ": а, "другой": [а, б]}
+д = в[1:] + в[:2] + в[0:1]
+е = ?(а > 0, г.ключ, г["другой"][0])
+ж = Строка(а) + новый("Строка", 1)
+а += 2
+а++
+--б
+з, к = 1, 2
+{"x": л, "y": [м, ...н]} = г`,
+		},
+		{
+			name: "вложенные унарные операции",
+			src: `модуль _
+а = - -б
+в = + +б
+г = -(-б)
+д = -+б - -1
+е = не не б`,
+		},
+		{
+			name: "условия и циклы",
+			src: `модуль _
+если а < 0 тогда
+	сообщить("меньше")
+иначеесли а = 0 тогда
+	сообщить("ноль")
+иначе
+	сообщить("больше")
+конецесли
+для каждого э из [1, 2] цикл
+	если э = 2 тогда
+		прервать
+	конецесли
+	продолжить
+конеццикла
+для н = 1 по 10 цикл
+	сообщить(н)
+конеццикла
+пока а < 10 цикл
+	а = а + 1
+конеццикла
+выбор а:
+	когда 1:
+		сообщить(1)
+	когда 2 по 5:
+		сообщить(2)
+	другое:
+		сообщить(0)
+конецвыбора`,
+		},
+		{
+			name: "функции и исключения",
+			src: `модуль Тест
+функция Сумма(а, б)
+	глобальная всего, последний
+	возврат а + б
+конецфункции
+функция Все(арг...)
+	возврат арг
+конецфункции
+ф = функция(х)
+	возврат х * х
+конецфункции
+попытка
+	вызватьисключение "ошибка"
+исключение
+	сообщить(ОписаниеОшибки())
+окончательно
+	сообщить("конец")
+конецпопытки
+попытка
+	сообщить(1)
+окончательно
+конецпопытки
+р = попытаться Сумма(1, 2) + 1
+используя р = Открыть()
+	р.Записать(Сумма(1, 2), ф(3))
+конециспользования
+к = новый канал(2)
+к <- 1
+старт Сумма(1, 2)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := parse(t, tt.src)
+			out := orig.String()
+			again := parse(t, out)
+			clearPositions(reflect.ValueOf(orig))
+			clearPositions(reflect.ValueOf(again))
+			if !reflect.DeepEqual(orig, again) {
+				t.Errorf("после форматирования дерево изменилось:\n%s", out)
+			}
+			if out2 := again.String(); out2 != out {
+				t.Errorf("повторное форматирование отличается:\n%s\n---\n%s", out, out2)
+			}
+		})
+	}
+}
+
+func TestFormatSimplified(t *testing.T) {
+	stmts := parse(t, "модуль _\nа = 2 * 3 + 1\nб = \"а\" + \"б\"\nв = б + \"в\" + а")
+	stmts = parser.ConstFolding(stmts)
+	want := "модуль _\nа = 7\nб = \"аб\"\nв = б + \"в\" + а"
+	if got := stmts.String(); got != want {
+		t.Errorf("получено:\n%s\nожидалось:\n%s", got, want)
+	}
+}
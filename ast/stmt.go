@@ -37,7 +37,8 @@ type Stmt interface {
 	pos.Pos
 	stmt()
 	Simplify()
-	BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int)
+	BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int)
+	format(p *printer)
 }
 
 // StmtImpl provide commonly implementations for Stmt..
@@ -50,18 +51,19 @@ func (x *StmtImpl) stmt() {}
 
 type Stmts []Stmt
 
-func (x Stmts) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
+func (x Stmts) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
 
 	for _, st := range x {
-		st.BinTo(bins, reg, lid, maxreg)
+		st.BinTo(bins, reg, cc, maxreg)
 	}
 }
 
-func (x Stmts) BinaryCode(reg int, lid *int) (bcd binstmt.BinCode) {
+func (x Stmts) BinaryCode(reg int, cc *CompileCtx) (bcd binstmt.BinCode) {
 	bins := bcd.Code
-	x.BinTo(&bins, reg, lid, &bcd.MaxReg)
+	x.BinTo(&bins, reg, cc, &bcd.MaxReg)
 	bcd.Code = bins
-	bcd.MapLabels(*lid)
+	bcd.MapLabels(cc.Labels)
+	bcd.PoolConsts()
 	return
 }
 
@@ -70,8 +72,8 @@ type NoneStmt struct {
 	StmtImpl
 }
 
-func (x *NoneStmt) Simplify()                                                    {}
-func (s *NoneStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {}
+func (x *NoneStmt) Simplify()                                                          {}
+func (s *NoneStmt) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {}
 
 // ExprStmt provide expression statement.
 type ExprStmt struct {
@@ -83,12 +85,12 @@ func (x *ExprStmt) Simplify() {
 	x.Expr = x.Expr.Simplify()
 }
 
-func (s *ExprStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
-	s.Expr.BinTo(bins, reg, lid, true, maxreg)
+func (s *ExprStmt) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
+	s.Expr.BinTo(bins, reg, cc, true, maxreg)
 	if reg > *maxreg {
 		*maxreg = reg
 	}
-	// *bins = append(*bins, addBinExpr(s.Expr, reg, lid, true)...)
+	// *bins = append(*bins, addBinExpr(s.Expr, reg, cc, true)...)
 }
 
 // IfStmt provide "if/else" statement.
@@ -113,20 +115,20 @@ func (x *IfStmt) Simplify() {
 	}
 }
 
-func (s *IfStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
-	*lid++
-	lend := *lid
+func (s *IfStmt) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
+	cc.Labels++
+	lend := cc.Labels
 
 	// Если
-	s.If.BinTo(bins, reg, lid, false, maxreg)
+	s.If.BinTo(bins, reg, cc, false, maxreg)
 
-	*lid++
-	lf := *lid
+	cc.Labels++
+	lf := cc.Labels
 
 	bins.Append(binstmt.NewBinJFALSE(reg, lf, s))
 
 	// Тогда
-	s.Then.BinTo(bins, reg, lid, maxreg)
+	s.Then.BinTo(bins, reg, cc, maxreg)
 
 	bins.Append(binstmt.NewBinJMP(lend, s))
 
@@ -136,15 +138,15 @@ func (s *IfStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
 	for _, elif := range s.ElseIf {
 		stmtif := elif.(*IfStmt)
 
-		stmtif.If.BinTo(bins, reg, lid, false, maxreg)
+		stmtif.If.BinTo(bins, reg, cc, false, maxreg)
 
 		// если ложь, то перейдем на следующее условие
-		*lid++
-		li := *lid
+		cc.Labels++
+		li := cc.Labels
 
 		bins.Append(binstmt.NewBinJFALSE(reg, li, stmtif))
 
-		stmtif.Then.BinTo(bins, reg, lid, maxreg)
+		stmtif.Then.BinTo(bins, reg, cc, maxreg)
 
 		bins.Append(binstmt.NewBinJMP(lend, stmtif))
 
@@ -153,7 +155,7 @@ func (s *IfStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
 
 	// Иначе
 	if len(s.Else) > 0 {
-		s.Else.BinTo(bins, reg, lid, maxreg)
+		s.Else.BinTo(bins, reg, cc, maxreg)
 	}
 	// КонецЕсли
 	bins.Append(binstmt.NewBinLABEL(lend, s))
@@ -171,8 +173,10 @@ type TryStmt struct {
 	StmtImpl
 	Try Stmts
 	// Var     string
-	Catch Stmts
-	// Finally Stmts
+	Catch   Stmts
+	Finally Stmts
+	// NoCatch - раздел Исключение не указан, ошибка выбрасывается повторно после раздела Окончательно
+	NoCatch bool
 }
 
 func (x *TryStmt) Simplify() {
@@ -182,18 +186,33 @@ func (x *TryStmt) Simplify() {
 	for _, st := range x.Catch {
 		st.Simplify()
 	}
+	for _, st := range x.Finally {
+		st.Simplify()
+	}
 }
 
-func (s *TryStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
-	*lid++
-	lend := *lid
-	*lid++
-	li := *lid
+// HasFinally - у попытки есть раздел Окончательно
+func (x *TryStmt) HasFinally() bool {
+	return x.Finally != nil || x.NoCatch
+}
+
+func (s *TryStmt) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
+	if s.HasFinally() {
+		s.binFinallyTo(bins, reg, cc, maxreg)
+		return
+	}
+	cc.Labels++
+	lend := cc.Labels
+	cc.Labels++
+	li := cc.Labels
 	// эта инструкция сообщает, в каком регистре будет отслеживаться ошибка выполнения кода до блока CATCH
 	// по-умолчанию, ошибка в регистрах не отслеживается, а передается по уровням исполнения вирт. машины
-	bins.Append(binstmt.NewBinTRY(reg, li, s))
+	try := binstmt.NewBinTRY(reg, li, s)
+	bins.Append(try)
 
-	s.Try.BinTo(bins, reg+1, lid, maxreg) // чтобы не затереть регистр с ошибкой, увеличиваем номер
+	inBlock(cc, try, func() {
+		s.Try.BinTo(bins, reg+1, cc, maxreg) // чтобы не затереть регистр с ошибкой, увеличиваем номер
+	})
 
 	// сюда переходим, если в блоке выше возникла ошибка
 	bins.Append(binstmt.NewBinLABEL(li, s))
@@ -202,7 +221,7 @@ func (s *TryStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int)
 	bins.Append(binstmt.NewBinCATCH(reg, lend, s))
 
 	// тело обработки ошибки
-	s.Catch.BinTo(bins, reg, lid, maxreg) // регистр с ошибкой больше не нужен, текст определен функцией
+	s.Catch.BinTo(bins, reg, cc, maxreg) // регистр с ошибкой больше не нужен, текст определен функцией
 
 	bins.Append(binstmt.NewBinLABEL(lend, s))
 	// КонецПопытки
@@ -218,7 +237,216 @@ func (s *TryStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int)
 	}
 }
 
+// binFinallyTo компилирует попытку с разделом Окончательно. Раздел выполняется после тела попытки и обработки ошибки,
+// а также при выходе из них операторами Прервать, Продолжить и Возврат - такой выход откладывается до конца раздела
+func (s *TryStmt) binFinallyTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
+	regact, regval := reg+1, reg+2
+	cc.Labels++
+	lfin := cc.Labels
+	cc.Labels++
+	li := cc.Labels
+
+	fin := binstmt.NewBinFINALLY(regact, regval, lfin, s)
+	bins.Append(fin)
+	inBlock(cc, fin, func() {
+		try := binstmt.NewBinTRY(reg, li, s)
+		bins.Append(try)
+		inBlock(cc, try, func() { s.Try.BinTo(bins, reg+3, cc, maxreg) })
+		bins.Append(binstmt.NewBinLABEL(li, s))
+		bins.Append(binstmt.NewBinPOPTRY(li, s))
+
+		if !s.NoCatch {
+			// без ошибки сразу переходим к разделу Окончательно
+			bins.Append(binstmt.NewBinCATCH(reg, lfin, s))
+			// ошибка при ее обработке тоже попадает в reg и выбрасывается после раздела Окончательно
+			cc.Labels++
+			lc := cc.Labels
+			try := binstmt.NewBinTRY(reg, lc, s)
+			bins.Append(try)
+			inBlock(cc, try, func() { s.Catch.BinTo(bins, reg+3, cc, maxreg) })
+			bins.Append(binstmt.NewBinLABEL(lc, s))
+			bins.Append(binstmt.NewBinPOPTRY(lc, s))
+		}
+	})
+
+	bins.Append(binstmt.NewBinLABEL(lfin, s))
+	s.Finally.BinTo(bins, reg+3, cc, maxreg)
+	bins.Append(binstmt.NewBinRETHROW(reg, s))
+
+	// выполняем отложенный выход, если он возможен в этом месте кода
+	for _, act := range []int{binstmt.FinallyBreak, binstmt.FinallyContinue, binstmt.FinallyReturn} {
+		if act == binstmt.FinallyReturn && !inFunc(cc) || act != binstmt.FinallyReturn && !inLoop(cc) {
+			continue
+		}
+		cc.Labels++
+		lnext := cc.Labels
+		bins.Append(binstmt.NewBinLOAD(reg+3, core.VMInt(act), false, s))
+		bins.Append(binstmt.NewBinEQUAL(reg+3, regact, reg+3, s))
+		bins.Append(binstmt.NewBinJFALSE(reg+3, lnext, s))
+		switch act {
+		case binstmt.FinallyBreak:
+			st := &BreakStmt{}
+			st.SetPosition(s.Position())
+			st.BinTo(bins, reg+3, cc, maxreg)
+		case binstmt.FinallyContinue:
+			st := &ContinueStmt{}
+			st.SetPosition(s.Position())
+			st.BinTo(bins, reg+3, cc, maxreg)
+		case binstmt.FinallyReturn:
+			binReturnTo(bins, cc, regval, s, maxreg)
+		}
+		bins.Append(binstmt.NewBinLABEL(lnext, s))
+	}
+
+	if reg+3 > *maxreg {
+		*maxreg = reg + 3
+	}
+}
+
+// WithStmt блок "Используя", по выходу из которого у ресурса вызывается метод Закрыть:
+// используя ф = Открыть(путь) ... конециспользования
+type WithStmt struct {
+	StmtImpl
+	Var   int //string
+	Expr  Expr
+	Stmts Stmts
+}
+
+func (x *WithStmt) Simplify() {
+	x.Expr = x.Expr.Simplify()
+	for _, st := range x.Stmts {
+		st.Simplify()
+	}
+}
+
+func (s *WithStmt) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
+	cc.Labels++
+	li := cc.Labels
+
+	regres := reg + 1
+	s.Expr.BinTo(bins, regres, cc, false, maxreg)
+	bins.Append(binstmt.NewBinSET(regres, s.Var, s))
+
+	// ошибка в блоке попадет в reg, а выполнение продолжится с метки li
+	with := binstmt.NewBinWITH(reg, regres, li, s)
+	bins.Append(with)
+
+	inBlock(cc, with, func() { s.Stmts.BinTo(bins, reg+2, cc, maxreg) })
+
+	bins.Append(binstmt.NewBinLABEL(li, s))
+	bins.Append(binstmt.NewBinPOPTRY(li, s))
+
+	// ресурс закрывается и при нормальном выходе, и при ошибке
+	binCloseTo(bins, regres, reg+2, s, maxreg)
+
+	// если была ошибка, выбрасываем ее повторно
+	bins.Append(binstmt.NewBinRETHROW(reg, s))
+
+	if reg+2 > *maxreg {
+		*maxreg = reg + 2
+	}
+}
+
+// binCloseTo вызывает метод Закрыть у ресурса из регистра regres, используя регистр reg
+func binCloseTo(bins *binstmt.BinStmts, regres, reg int, e pos.Pos, maxreg *int) {
+	bins.Append(binstmt.NewBinMV(regres, reg, e))
+	bins.Append(binstmt.NewBinGETMEMBER(reg, names.UniqueNames.Set("закрыть"), e))
+	bins.Append(binstmt.NewBinCALL(0, 0, reg, reg, false, false, e))
+	if reg > *maxreg {
+		*maxreg = reg
+	}
+}
+
+// CompileCtx - состояние одной компиляции, которое передается по всей цепочке BinTo:
+// счетчик меток и блоки, внутри которых компилируется текущий код.
+// Вместо просмотра уже сформированного кода ведутся счетчики функций и циклов и стек блоков
+type CompileCtx struct {
+	Labels int // число выданных меток, номер последней из них
+
+	funcs  int // незакрытые функции
+	loops  int // незакрытые циклы внутри текущей функции
+	blocks []openBlock
+}
+
+// openBlock - блок функции, цикла, Используя или Попытка (BinFUNC, BinFOREACH, BinFORNUM, BinWHILE,
+// BinWITH, BinTRY или BinFINALLY); для функции запоминается число циклов вокруг нее
+type openBlock struct {
+	b     binstmt.BinStmt
+	loops int
+}
+
+// inBlock компилирует код f внутри блока b, инструкция которого уже добавлена в формируемый код
+func inBlock(cc *CompileCtx, b binstmt.BinStmt, f func()) {
+	ob := openBlock{b: b}
+	switch b.(type) {
+	case *binstmt.BinFUNC:
+		ob.loops = cc.loops
+		cc.funcs++
+		cc.loops = 0
+	case *binstmt.BinFOREACH, *binstmt.BinFORNUM, *binstmt.BinWHILE:
+		cc.loops++
+	}
+	cc.blocks = append(cc.blocks, ob)
+
+	// блок закрывается и при ошибке компиляции, чтобы не оставить состояние следующему коду
+	defer func() {
+		cc.blocks = cc.blocks[:len(cc.blocks)-1]
+		switch b.(type) {
+		case *binstmt.BinFUNC:
+			cc.funcs--
+			cc.loops = ob.loops
+		case *binstmt.BinFOREACH, *binstmt.BinFORNUM, *binstmt.BinWHILE:
+			cc.loops--
+		}
+	}()
+	f()
+}
+
+// openBlocks возвращает незакрытые блоки Используя и Попытка (BinWITH, BinTRY и BinFINALLY),
+// внутри которых компилируется текущий код, начиная с внутреннего.
+// Перебор останавливается на границе функции, а если toLoop - то и на первом незакрытом цикле
+func openBlocks(cc *CompileCtx, toLoop bool) (bs []binstmt.BinStmt) {
+	for i := len(cc.blocks) - 1; i >= 0; i-- {
+		switch s := cc.blocks[i].b.(type) {
+		case *binstmt.BinWITH, *binstmt.BinTRY, *binstmt.BinFINALLY:
+			bs = append(bs, s)
+		case *binstmt.BinFOREACH, *binstmt.BinFORNUM, *binstmt.BinWHILE:
+			if toLoop {
+				return
+			}
+		case *binstmt.BinFUNC:
+			return
+		}
+	}
+	return
+}
+
+// binLeaveTo выходит из блоков Используя и Попытка операторами Прервать, Продолжить и Возврат (действие act):
+// закрывает ресурсы и снимает обработчики ошибок, используя регистр reg. Если на пути есть раздел Окончательно,
+// то выполнение передается ему с кодом отложенного действия (для Возврат - со значением из regval),
+// и возвращается true - само действие выполнится после раздела
+func binLeaveTo(bins *binstmt.BinStmts, cc *CompileCtx, act, regval, reg int, e pos.Pos, maxreg *int) bool {
+	for _, b := range openBlocks(cc, act != binstmt.FinallyReturn) {
+		switch s := b.(type) {
+		case *binstmt.BinWITH:
+			binCloseTo(bins, s.RegRes, reg, e, maxreg)
+			bins.Append(binstmt.NewBinPOPTRY(s.JumpTo, e))
+		case *binstmt.BinTRY:
+			bins.Append(binstmt.NewBinPOPTRY(s.JumpTo, e))
+		case *binstmt.BinFINALLY:
+			if act == binstmt.FinallyReturn {
+				bins.Append(binstmt.NewBinMV(regval, s.RegValue, e))
+			}
+			bins.Append(binstmt.NewBinLOAD(s.RegAction, core.VMInt(act), false, e))
+			bins.Append(binstmt.NewBinJMP(s.JumpTo, e))
+			return true
+		}
+	}
+	return false
+}
+
 // ForStmt provide "for in" expression statement.
+// Обходит элементы коллекции, а для канала - получаемые из него значения, пока канал не будет закрыт
 type ForStmt struct {
 	StmtImpl
 	Var   int //string
@@ -233,20 +461,21 @@ func (x *ForStmt) Simplify() {
 	}
 }
 
-func (s *ForStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
+func (s *ForStmt) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
 	// для каждого
-	s.Value.BinTo(bins, reg, lid, false, maxreg)
+	s.Value.BinTo(bins, reg, cc, false, maxreg)
 
-	*lid++
-	lend := *lid
-	*lid++
-	li := *lid
+	cc.Labels++
+	lend := cc.Labels
+	cc.Labels++
+	li := cc.Labels
 
 	regiter := reg + 1
 	regval := reg + 2
 	regsub := reg + 3
 	// инициализируем итератор, параметры цикла и цикл в стеке циклов
-	bins.Append(binstmt.NewBinFOREACH(reg, regiter, lend, li, s))
+	loop := binstmt.NewBinFOREACH(reg, regiter, lend, li, s)
+	bins.Append(loop)
 
 	// очередная итерация
 	// сюда же переходим по Продолжить
@@ -257,7 +486,7 @@ func (s *ForStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int)
 	// устанавливаем переменную-итератор
 	bins.Append(binstmt.NewBinSET(regval, s.Var, s))
 
-	s.Stmts.BinTo(bins, regsub, lid, maxreg)
+	inBlock(cc, loop, func() { s.Stmts.BinTo(bins, regsub, cc, maxreg) })
 
 	// повторяем итерацию
 	bins.Append(binstmt.NewBinJMP(li, s))
@@ -293,22 +522,23 @@ func (x *NumForStmt) Simplify() {
 	}
 }
 
-func (s *NumForStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
+func (s *NumForStmt) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
 	// для .. по ..
 	regfrom := reg + 1
 	regto := reg + 2
 	regsub := reg + 3
 
-	s.Expr1.BinTo(bins, regfrom, lid, false, maxreg)
-	s.Expr2.BinTo(bins, regto, lid, false, maxreg)
+	s.Expr1.BinTo(bins, regfrom, cc, false, maxreg)
+	s.Expr2.BinTo(bins, regto, cc, false, maxreg)
 
-	*lid++
-	lend := *lid
-	*lid++
-	li := *lid
+	cc.Labels++
+	lend := cc.Labels
+	cc.Labels++
+	li := cc.Labels
 
 	// инициализируем итератор, параметры цикла и цикл в стеке циклов
-	bins.Append(binstmt.NewBinFORNUM(reg, regfrom, regto, lend, li, s))
+	loop := binstmt.NewBinFORNUM(reg, regfrom, regto, lend, li, s)
+	bins.Append(loop)
 
 	// очередная итерация
 	// сюда же переходим по Продолжить
@@ -335,7 +565,7 @@ func (s *NumForStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *in
 	// устанавливаем переменную-итератор
 	bins.Append(binstmt.NewBinSET(reg, s.Name, s))
 
-	s.Stmts.BinTo(bins, regsub, lid, maxreg)
+	inBlock(cc, loop, func() { s.Stmts.BinTo(bins, regsub, cc, maxreg) })
 	// повторяем итерацию
 	bins.Append(binstmt.NewBinJMP(li, s))
 
@@ -377,23 +607,24 @@ func (x *LoopStmt) Simplify() {
 	}
 }
 
-func (s *LoopStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
-	*lid++
-	lend := *lid
-	*lid++
-	li := *lid
-	bins.Append(binstmt.NewBinWHILE(lend, li, s))
+func (s *LoopStmt) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
+	cc.Labels++
+	lend := cc.Labels
+	cc.Labels++
+	li := cc.Labels
+	loop := binstmt.NewBinWHILE(lend, li, s)
+	bins.Append(loop)
 
 	// очередная итерация
 	// сюда же переходим по Продолжить
 	bins.Append(binstmt.NewBinLABEL(li, s))
 
-	s.Expr.BinTo(bins, reg, lid, false, maxreg)
+	s.Expr.BinTo(bins, reg, cc, false, maxreg)
 
 	bins.Append(binstmt.NewBinJFALSE(reg, lend, s))
 
 	// тело цикла
-	s.Stmts.BinTo(bins, reg+1, lid, maxreg)
+	inBlock(cc, loop, func() { s.Stmts.BinTo(bins, reg+1, cc, maxreg) })
 
 	// повторяем итерацию
 	bins.Append(binstmt.NewBinJMP(li, s))
@@ -420,8 +651,13 @@ type BreakStmt struct {
 
 func (x *BreakStmt) Simplify() {}
 
-func (s *BreakStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
-	bins.Append(binstmt.NewBinBREAK(s))
+func (s *BreakStmt) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
+	if !inLoop(cc) {
+		panic(binstmt.NewStringError(s, "Оператор Прервать может использоваться только внутри цикла"))
+	}
+	if !binLeaveTo(bins, cc, binstmt.FinallyBreak, reg, reg, s, maxreg) {
+		bins.Append(binstmt.NewBinBREAK(s))
+	}
 	if reg > *maxreg {
 		*maxreg = reg
 	}
@@ -434,13 +670,28 @@ type ContinueStmt struct {
 
 func (x *ContinueStmt) Simplify() {}
 
-func (s *ContinueStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
-	bins.Append(binstmt.NewBinCONTINUE(s))
+func (s *ContinueStmt) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
+	if !inLoop(cc) {
+		panic(binstmt.NewStringError(s, "Оператор Продолжить может использоваться только внутри цикла"))
+	}
+	if !binLeaveTo(bins, cc, binstmt.FinallyContinue, reg, reg, s, maxreg) {
+		bins.Append(binstmt.NewBinCONTINUE(s))
+	}
 	if reg > *maxreg {
 		*maxreg = reg
 	}
 }
 
+// inLoop определяет, компилируется ли сейчас тело цикла. Циклы снаружи функции в ней недоступны
+func inLoop(cc *CompileCtx) bool {
+	return cc.loops > 0
+}
+
+// inFunc определяет, компилируется ли сейчас тело функции
+func inFunc(cc *CompileCtx) bool {
+	return cc.funcs > 0
+}
+
 // ForStmt provide "return" expression statement.
 type ReturnStmt struct {
 	StmtImpl
@@ -453,31 +704,41 @@ func (x *ReturnStmt) Simplify() {
 	}
 }
 
-func (s *ReturnStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
+func (s *ReturnStmt) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
+	if !inFunc(cc) {
+		panic(binstmt.NewStringError(s, "Оператор Возврат может использоваться только внутри функции"))
+	}
 
 	if len(s.Exprs) == 0 {
 		bins.Append(binstmt.NewBinLOAD(reg, core.VMNil, false, s))
 	}
 	if len(s.Exprs) == 1 {
 		// одиночное значение в reg
-		s.Exprs[0].BinTo(bins, reg, lid, false, maxreg)
+		s.Exprs[0].BinTo(bins, reg, cc, false, maxreg)
 	} else {
 		// создание слайса в reg
 		bins.Append(binstmt.NewBinMAKESLICE(reg, len(s.Exprs), len(s.Exprs), s))
 
 		for i, ee := range s.Exprs {
-			ee.BinTo(bins, reg+1, lid, false, maxreg)
+			ee.BinTo(bins, reg+1, cc, false, maxreg)
 			bins.Append(binstmt.NewBinSETIDX(reg, i, reg+1, ee))
 		}
 	}
 	// в reg имеем значение или структуру возврата
 	// bins.Append(binstmt.NewBinFREE(reg+1, s))
-	bins.Append(binstmt.NewBinRET(reg, s))
 
+	binReturnTo(bins, cc, reg, s, maxreg)
+}
+
+// binReturnTo возвращает из функции значение из регистра reg, перед этим закрывая ресурсы блоков Используя
+// и выполняя разделы Окончательно
+func binReturnTo(bins *binstmt.BinStmts, cc *CompileCtx, reg int, e pos.Pos, maxreg *int) {
+	if !binLeaveTo(bins, cc, binstmt.FinallyReturn, reg, reg+1, e, maxreg) {
+		bins.Append(binstmt.NewBinRET(reg, e))
+	}
 	if reg+1 > *maxreg {
 		*maxreg = reg + 1
 	}
-
 }
 
 // ThrowStmt provide "throw" expression statement.
@@ -490,14 +751,30 @@ func (x *ThrowStmt) Simplify() {
 	x.Expr = x.Expr.Simplify()
 }
 
-func (s *ThrowStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
-	s.Expr.BinTo(bins, reg, lid, false, maxreg)
+func (s *ThrowStmt) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
+	s.Expr.BinTo(bins, reg, cc, false, maxreg)
 	bins.Append(binstmt.NewBinTHROW(reg, s))
 	if reg > *maxreg {
 		*maxreg = reg
 	}
 }
 
+// GlobalStmt объявление "глобальная имя1, имя2" в теле функции: присваивания этим переменным
+// изменяют глобальные переменные, а не создают локальные. Само объявление кода не порождает,
+// присваивания отмечаются при компиляции функции (FuncExpr.markGlobals)
+type GlobalStmt struct {
+	StmtImpl
+	Names []int //string
+}
+
+func (x *GlobalStmt) Simplify() {}
+
+func (s *GlobalStmt) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
+	if !inFunc(cc) {
+		panic(binstmt.NewStringError(s, "Объявление Глобальная допустимо только в теле функции"))
+	}
+}
+
 // ModuleStmt provide "module" expression statement.
 type ModuleStmt struct {
 	StmtImpl
@@ -526,12 +803,12 @@ func (x *ModuleStmt) Simplify() {
 
 }
 
-func (s *ModuleStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
+func (s *ModuleStmt) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
 	if s.Name == names.UniqueNames.Set("_") {
 		// добавляем все операторы в текущий контекст
-		s.Stmts.BinTo(bins, reg, lid, maxreg)
+		s.Stmts.BinTo(bins, reg, cc, maxreg)
 	} else {
-		bins.Append(binstmt.NewBinMODULE(s.Name, s.Stmts.BinaryCode(0, lid), s))
+		bins.Append(binstmt.NewBinMODULE(s.Name, s.Stmts.BinaryCode(0, cc), s))
 	}
 	if reg > *maxreg {
 		*maxreg = reg
@@ -552,29 +829,40 @@ func (x *SwitchStmt) Simplify() {
 	}
 }
 
-func (s *SwitchStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
-	s.Expr.BinTo(bins, reg, lid, true, maxreg)
+func (s *SwitchStmt) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
+	s.Expr.BinTo(bins, reg, cc, true, maxreg)
 	// сравниваем с каждым case
-	*lid++
-	lend := *lid
+	cc.Labels++
+	lend := cc.Labels
 	var default_stmt *DefaultStmt
 	for _, ss := range s.Cases {
 		if ssd, ok := ss.(*DefaultStmt); ok {
 			default_stmt = ssd
 			continue
 		}
-		*lid++
-		li := *lid
+		cc.Labels++
+		li := cc.Labels
 		case_stmt := ss.(*CaseStmt)
-		case_stmt.Expr.BinTo(bins, reg+1, lid, false, maxreg)
-		bins.Append(binstmt.NewBinEQUAL(reg+2, reg, reg+1, case_stmt))
+		if case_stmt.To != nil {
+			// диапазон: значение >= начала и <= конца
+			case_stmt.Expr.BinTo(bins, reg+1, cc, false, maxreg)
+			bins.Append(binstmt.NewBinMV(reg, reg+2, case_stmt))
+			bins.Append(binstmt.NewBinOPER(reg+2, reg+1, core.GEQ, case_stmt))
+			bins.Append(binstmt.NewBinJFALSE(reg+2, li, case_stmt))
+			case_stmt.To.BinTo(bins, reg+1, cc, false, maxreg)
+			bins.Append(binstmt.NewBinMV(reg, reg+2, case_stmt))
+			bins.Append(binstmt.NewBinOPER(reg+2, reg+1, core.LEQ, case_stmt))
+		} else {
+			case_stmt.Expr.BinTo(bins, reg+1, cc, false, maxreg)
+			bins.Append(binstmt.NewBinEQUAL(reg+2, reg, reg+1, case_stmt))
+		}
 		bins.Append(binstmt.NewBinJFALSE(reg+2, li, case_stmt))
-		case_stmt.Stmts.BinTo(bins, reg, lid, maxreg)
+		case_stmt.Stmts.BinTo(bins, reg, cc, maxreg)
 		bins.Append(binstmt.NewBinJMP(lend, case_stmt))
 		bins.Append(binstmt.NewBinLABEL(li, case_stmt))
 	}
 	if default_stmt != nil {
-		default_stmt.Stmts.BinTo(bins, reg, lid, maxreg)
+		default_stmt.Stmts.BinTo(bins, reg, cc, maxreg)
 	}
 	bins.Append(binstmt.NewBinLABEL(lend, s))
 	// освобождаем память
@@ -584,6 +872,80 @@ func (s *SwitchStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *in
 	}
 }
 
+// TypeSwitchStmt выбор по типу значения:
+// выбор по типу ч = значение:
+// когда Строка, ЦелоеЧисло: ...
+// другое: ...
+// конецвыбора
+type TypeSwitchStmt struct {
+	StmtImpl
+	Expr  Expr
+	Var   int //string, необязательная переменная, которой присваивается значение
+	Cases Stmts
+}
+
+func (x *TypeSwitchStmt) Simplify() {
+	x.Expr = x.Expr.Simplify()
+	for _, st := range x.Cases {
+		st.Simplify()
+	}
+}
+
+func (s *TypeSwitchStmt) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
+	s.Expr.BinTo(bins, reg, cc, false, maxreg)
+	if s.Var != 0 {
+		bins.Append(binstmt.NewBinSET(reg, s.Var, s))
+	}
+	cc.Labels++
+	lend := cc.Labels
+	var default_stmt *DefaultStmt
+	for _, ss := range s.Cases {
+		if ssd, ok := ss.(*DefaultStmt); ok {
+			default_stmt = ssd
+			continue
+		}
+		cc.Labels++
+		lbody := cc.Labels
+		cc.Labels++
+		lnext := cc.Labels
+		case_stmt := ss.(*TypeCaseStmt)
+		// подходит любой из перечисленных типов
+		for _, t := range case_stmt.Types {
+			bins.Append(binstmt.NewBinISTYPE(reg, reg+1, t, case_stmt))
+			bins.Append(binstmt.NewBinJTRUE(reg+1, lbody, case_stmt))
+		}
+		bins.Append(binstmt.NewBinJMP(lnext, case_stmt))
+		bins.Append(binstmt.NewBinLABEL(lbody, case_stmt))
+		case_stmt.Stmts.BinTo(bins, reg, cc, maxreg)
+		bins.Append(binstmt.NewBinJMP(lend, case_stmt))
+		bins.Append(binstmt.NewBinLABEL(lnext, case_stmt))
+	}
+	if default_stmt != nil {
+		default_stmt.Stmts.BinTo(bins, reg, cc, maxreg)
+	}
+	bins.Append(binstmt.NewBinLABEL(lend, s))
+	if reg+1 > *maxreg {
+		*maxreg = reg + 1
+	}
+}
+
+// TypeCaseStmt вариант выбора по типу
+type TypeCaseStmt struct {
+	StmtImpl
+	Types []int //string
+	Stmts Stmts
+}
+
+func (x *TypeCaseStmt) Simplify() {
+	for _, st := range x.Stmts {
+		st.Simplify()
+	}
+}
+
+func (s *TypeCaseStmt) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
+	//ничего не делаем, эти блоки обрабатываются в родительских контекстах
+}
+
 // SelectStmt provide switch statement.
 type SelectStmt struct {
 	StmtImpl
@@ -596,28 +958,28 @@ func (x *SelectStmt) Simplify() {
 	}
 }
 
-func (s *SelectStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
-	*lid++
-	lstart := *lid
+func (s *SelectStmt) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
+	cc.Labels++
+	lstart := cc.Labels
 	bins.Append(binstmt.NewBinLABEL(lstart, s))
 
-	*lid++
-	lend := *lid
+	cc.Labels++
+	lend := cc.Labels
 	var default_stmt *DefaultStmt
 	for _, ss := range s.Cases {
 		if ssd, ok := ss.(*DefaultStmt); ok {
 			default_stmt = ssd
 			continue
 		}
-		*lid++
-		li := *lid
+		cc.Labels++
+		li := cc.Labels
 		case_stmt := ss.(*CaseStmt)
 		e, ok := case_stmt.Expr.(*ChanExpr)
-		if !ok {
+		if !ok || case_stmt.To != nil {
 			panic(binstmt.NewStringError(case_stmt, "При выборе вариантов из каналов допустимы только выражения с каналами"))
 		}
 		// определяем значение справа
-		e.Rhs.BinTo(bins, reg, lid, false, maxreg)
+		e.Rhs.BinTo(bins, reg, cc, false, maxreg)
 		if e.Lhs == nil {
 			// слева нет значения - это временное чтение из канала без сохранения значения в переменной
 			bins.Append(binstmt.NewBinTRYRECV(reg, reg+1, reg+2, reg+3, e.Rhs))
@@ -625,22 +987,22 @@ func (s *SelectStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *in
 			bins.Append(binstmt.NewBinJFALSE(reg+2, li, s))
 		} else {
 			// значение слева
-			e.Lhs.BinTo(bins, reg+1, lid, false, maxreg)
+			e.Lhs.BinTo(bins, reg+1, cc, false, maxreg)
 
 			// проверяем: слева канал?
 			bins.Append(binstmt.NewBinMV(reg+1, reg+3, e))
 			bins.Append(binstmt.NewBinISKIND(reg+3, reflect.Chan, e))
 
-			*lid++
-			li3 := *lid
+			cc.Labels++
+			li3 := cc.Labels
 
 			bins.Append(binstmt.NewBinJFALSE(reg+3, li3, e))
 
 			// слева канал - пишем в него правое
 			bins.Append(binstmt.NewBinTRYSEND(reg+1, reg, reg+2, e.Lhs))
 
-			*lid++
-			li2 := *lid
+			cc.Labels++
+			li2 := cc.Labels
 
 			// если отправлено значение - выполняем код блока
 			bins.Append(binstmt.NewBinJTRUE(reg+2, li2, s))
@@ -658,12 +1020,12 @@ func (s *SelectStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *in
 			bins.Append(binstmt.NewBinJFALSE(reg+2, li, s))
 
 			// устанавливаем переменную прочитанным значением
-			e.Lhs.(CanLetExpr).BinLetTo(bins, reg+1, lid, maxreg)
+			e.Lhs.(CanLetExpr).BinLetTo(bins, reg+1, cc, maxreg)
 
 			bins.Append(binstmt.NewBinLABEL(li2, s))
 		}
 		// отправили или прочитали - выполняем ветку кода и выходим из цикла
-		case_stmt.Stmts.BinTo(bins, reg, lid, maxreg)
+		case_stmt.Stmts.BinTo(bins, reg, cc, maxreg)
 
 		// выходим из цикла
 		bins.Append(binstmt.NewBinJMP(lend, case_stmt))
@@ -673,7 +1035,7 @@ func (s *SelectStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *in
 	}
 	// если ни одна из веток не сработала - проверяем default
 	if default_stmt != nil {
-		default_stmt.Stmts.BinTo(bins, reg, lid, maxreg)
+		default_stmt.Stmts.BinTo(bins, reg, cc, maxreg)
 	} else {
 		// допускаем обработку других горутин
 		bins.Append(binstmt.NewBinGOSHED(s))
@@ -689,20 +1051,25 @@ func (s *SelectStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *in
 }
 
 // CaseStmt provide switch/case statement.
+// Если задано To, вариант подходит для значений из диапазона включительно: когда 1 по 10:
 type CaseStmt struct {
 	StmtImpl
 	Expr  Expr
+	To    Expr
 	Stmts Stmts
 }
 
 func (x *CaseStmt) Simplify() {
 	x.Expr = x.Expr.Simplify()
+	if x.To != nil {
+		x.To = x.To.Simplify()
+	}
 	for _, st := range x.Stmts {
 		st.Simplify()
 	}
 }
 
-func (s *CaseStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
+func (s *CaseStmt) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
 	//ничего не делаем, эти блоки обрабатываются в родительских контекстах
 }
 
@@ -718,7 +1085,7 @@ func (x *DefaultStmt) Simplify() {
 	}
 }
 
-func (s *DefaultStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
+func (s *DefaultStmt) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
 	//ничего не делаем, эти блоки обрабатываются в родительских контекстах
 }
 
@@ -728,6 +1095,23 @@ type LetsStmt struct {
 	Lhss     []Expr
 	Operator string
 	Rhss     []Expr
+	// Declare - объявление переменных оператором :=
+	// переменные всегда создаются в текущем окружении, скрывая одноименные внешние,
+	// в том числе объявленные в функции как Глобальная,
+	// повторное объявление в том же окружении допустимо (например, в теле цикла)
+	Declare bool
+}
+
+// letTo присваивает значение регистра левой части, при объявлении := переменная создается в текущем окружении
+func (s *LetsStmt) letTo(e Expr, bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
+	if s.Declare {
+		bins.Append(binstmt.NewBinDEFINE(reg, e.(*IdentExpr).Id, e))
+		if reg > *maxreg {
+			*maxreg = reg
+		}
+		return
+	}
+	e.(CanLetExpr).BinLetTo(bins, reg, cc, maxreg)
 }
 
 func (x *LetsStmt) Simplify() {
@@ -739,17 +1123,24 @@ func (x *LetsStmt) Simplify() {
 	}
 }
 
-func (s *LetsStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
+func (s *LetsStmt) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
+	if s.Declare {
+		for _, e := range s.Lhss {
+			if _, ok := e.(*IdentExpr); !ok {
+				panic(binstmt.NewStringError(s, "Оператором := можно объявлять только переменные"))
+			}
+		}
+	}
 	// если справа одно выражение - присваиваем его всем левым
 	// и если там массив, то по очереди элементы, начиная с 0-го
 	// иначе с обеих сторон должно быть одинаковое число выражений, они попарно присваиваются
 	if len(s.Rhss) == 1 && len(s.Lhss) > 1 {
-		s.Rhss[0].BinTo(bins, reg, lid, false, maxreg)
+		s.Rhss[0].BinTo(bins, reg, cc, false, maxreg)
 		// проверяем на массив
-		*lid++
-		lend := *lid
-		*lid++
-		li := *lid
+		cc.Labels++
+		lend := cc.Labels
+		cc.Labels++
+		li := cc.Labels
 		bins.Append(binstmt.NewBinISSLICE(reg, reg+1, s))
 		bins.Append(binstmt.NewBinJFALSE(reg+1, li, s))
 
@@ -760,7 +1151,7 @@ func (s *LetsStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int)
 			bins.Append(binstmt.NewBinMV(reg, reg+1, e))
 			bins.Append(binstmt.NewBinLOAD(reg+2, core.VMInt(i), false, e))
 			bins.Append(binstmt.NewBinGETIDX(reg+1, reg+2, e))
-			e.(CanLetExpr).BinLetTo(bins, reg+1, lid, maxreg)
+			s.letTo(e, bins, reg+1, cc, maxreg)
 			i++
 		}
 		bins.Append(binstmt.NewBinJMP(lend, s))
@@ -768,7 +1159,7 @@ func (s *LetsStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int)
 		// присваиваем одно и то же значение
 		bins.Append(binstmt.NewBinLABEL(li, s))
 		for _, e := range s.Lhss {
-			e.(CanLetExpr).BinLetTo(bins, reg, lid, maxreg)
+			s.letTo(e, bins, reg, cc, maxreg)
 		}
 		bins.Append(binstmt.NewBinLABEL(lend, s))
 
@@ -781,13 +1172,13 @@ func (s *LetsStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int)
 			// сначала все вычисляем в разные регистры, затем все присваиваем
 			// так обеспечиваем взаимный обмен
 			for i := range s.Rhss {
-				s.Rhss[i].BinTo(bins, reg+i, lid, false, maxreg)
+				s.Rhss[i].BinTo(bins, reg+i, cc, false, maxreg)
 				if reg+i > *maxreg {
 					*maxreg = reg + i
 				}
 			}
 			for i, e := range s.Lhss {
-				e.(CanLetExpr).BinLetTo(bins, reg+i, lid, maxreg)
+				s.letTo(e, bins, reg+i, cc, maxreg)
 				if reg+i > *maxreg {
 					*maxreg = reg + i
 				}
@@ -812,18 +1203,18 @@ func (x *VarStmt) Simplify() {
 	}
 }
 
-func (s *VarStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
+func (s *VarStmt) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
 	// если справа одно выражение - присваиваем его всем левым
 	// иначе с обеих сторон должно быть одинаковое число выражений, они попарно присваиваются
 	if len(s.Exprs) == 1 {
-		s.Exprs[0].BinTo(bins, reg, lid, false, maxreg)
+		s.Exprs[0].BinTo(bins, reg, cc, false, maxreg)
 		for _, e := range s.Names {
 			bins.Append(binstmt.NewBinSET(reg, e, s))
 		}
 	} else {
 		if len(s.Exprs) == len(s.Names) {
 			for i, e := range s.Exprs {
-				e.BinTo(bins, reg, lid, false, maxreg)
+				e.BinTo(bins, reg, cc, false, maxreg)
 				bins.Append(binstmt.NewBinSET(reg, s.Names[i], s))
 			}
 		} else {
@@ -835,3 +1226,23 @@ func (s *VarStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int)
 		*maxreg = reg
 	}
 }
+
+// StructStmt объявление пользовательской структуры: структура Точка { Х, У }
+type StructStmt struct {
+	StmtImpl
+	Name   int   //string
+	Fields []int //string
+}
+
+func (x *StructStmt) Simplify() {}
+
+func (s *StructStmt) BinTo(bins *binstmt.BinStmts, reg int, cc *CompileCtx, maxreg *int) {
+	seen := make(map[int]bool, len(s.Fields))
+	for _, f := range s.Fields {
+		if seen[f] {
+			panic(binstmt.NewStringError(s, "Поле '"+names.UniqueNames.Get(f)+"' объявлено в структуре повторно"))
+		}
+		seen[f] = true
+	}
+	bins.Append(binstmt.NewBinSTRUCT(s.Name, s.Fields, s))
+}
@@ -0,0 +1,36 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/shinanca/gonec/bincode/binstmt"
+	"github.com/shinanca/gonec/core"
+)
+
+func TestBlocksClosedOnCompileError(t *testing.T) {
+	var cc CompileCtx
+	binTo := func(bins *binstmt.BinStmts, st Stmt) (err error) {
+		defer func() {
+			if ex := recover(); ex != nil {
+				err = ex.(error)
+			}
+		}()
+		maxreg := 0
+		st.BinTo(bins, 0, &cc, &maxreg)
+		return nil
+	}
+
+	var bins binstmt.BinStmts
+	// Возврат внутри цикла вне функции - ошибка компиляции посреди открытого блока цикла
+	loop := &LoopStmt{Expr: &NativeExpr{Value: core.VMBool(true)}, Stmts: Stmts{&ReturnStmt{}}}
+	if err := binTo(&bins, loop); err == nil {
+		t.Fatal("ожидалась ошибка компиляции Возврат вне функции")
+	}
+	if len(cc.blocks) != 0 || cc.loops != 0 || cc.funcs != 0 {
+		t.Fatalf("после ошибки остались открытые блоки: %+v", cc)
+	}
+	// цикл закрыт, поэтому Прервать в том же коде снова недопустим
+	if err := binTo(&bins, &BreakStmt{}); err == nil {
+		t.Fatal("ожидалась ошибка компиляции Прервать вне цикла")
+	}
+}
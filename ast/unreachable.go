@@ -0,0 +1,136 @@
+package ast
+
+import (
+	"reflect"
+
+	"github.com/shinanca/gonec/bincode/binstmt"
+)
+
+var (
+	reflectStmt  = reflect.TypeOf((*Stmt)(nil)).Elem()
+	reflectExpr  = reflect.TypeOf((*Expr)(nil)).Elem()
+	reflectStmts = reflect.TypeOf(Stmts(nil))
+)
+
+// Unreachable находит недостижимый код - операторы, следующие в том же блоке за безусловными
+// Возврат, ВызватьИсключение, Прервать, Продолжить, или за конструкцией, все ветки которой ими заканчиваются.
+// Для каждого блока (в том числе в телах функций) возвращается ошибка с позицией первого недостижимого оператора.
+// Анализ необязательный, компиляция его не выполняет - вызывающий код сам решает,
+// выводить ли найденное как предупреждения или считать ошибками
+func (x Stmts) Unreachable() (errs []error) {
+	unreachableIn(x, &errs)
+	return
+}
+
+// unreachableIn проверяет блок операторов и рекурсивно все вложенные в него блоки
+func unreachableIn(x Stmts, errs *[]error) {
+	for i, st := range x {
+		unreachableWalk(reflect.ValueOf(st), errs)
+		if terminates(st) && i+1 < len(x) {
+			*errs = append(*errs, binstmt.NewStringError(x[i+1], "Недостижимый код"))
+			return
+		}
+	}
+}
+
+// unreachableWalk обходит поля операторов и выражений в поисках вложенных блоков
+func unreachableWalk(v reflect.Value, errs *[]error) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			unreachableWalk(v.Elem(), errs)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Field(i)
+			switch {
+			case f.Type() == reflectStmts:
+				unreachableIn(f.Interface().(Stmts), errs)
+			case isNode(f.Type()):
+				unreachableWalk(f, errs)
+			case isNodeSlice(f.Type()):
+				unreachableWalk(f, errs)
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			unreachableWalk(v.Index(i), errs)
+		}
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			unreachableWalk(v.MapIndex(k), errs)
+		}
+	}
+}
+
+// isNode - тип является оператором или выражением дерева
+func isNode(t reflect.Type) bool {
+	return t.Implements(reflectStmt) || t.Implements(reflectExpr)
+}
+
+// isNodeSlice - тип является срезом или картой операторов или выражений
+func isNodeSlice(t reflect.Type) bool {
+	return (t.Kind() == reflect.Slice || t.Kind() == reflect.Map) && isNode(t.Elem())
+}
+
+// terminates определяет, что после оператора выполнение блока не продолжится
+func terminates(st Stmt) bool {
+	switch s := st.(type) {
+	case *ReturnStmt, *ThrowStmt, *BreakStmt, *ContinueStmt:
+		return true
+	case *IfStmt:
+		if len(s.Else) == 0 || !blockTerminates(s.Then) || !blockTerminates(s.Else) {
+			return false
+		}
+		for _, elif := range s.ElseIf {
+			if !blockTerminates(elif.(*IfStmt).Then) {
+				return false
+			}
+		}
+		return true
+	case *SwitchStmt:
+		return casesTerminate(s.Cases)
+	case *TypeSwitchStmt:
+		return casesTerminate(s.Cases)
+	case *TryStmt:
+		if blockTerminates(s.Finally) {
+			return true
+		}
+		return blockTerminates(s.Try) && (s.NoCatch || blockTerminates(s.Catch))
+	case *WithStmt:
+		return blockTerminates(s.Stmts)
+	}
+	return false
+}
+
+func blockTerminates(x Stmts) bool {
+	for _, st := range x {
+		if terminates(st) {
+			return true
+		}
+	}
+	return false
+}
+
+// casesTerminate - все варианты выбора, включая обязательный вариант Другое, заканчиваются выходом из блока
+func casesTerminate(cases Stmts) bool {
+	hasDefault := false
+	for _, c := range cases {
+		switch cs := c.(type) {
+		case *CaseStmt:
+			if !blockTerminates(cs.Stmts) {
+				return false
+			}
+		case *TypeCaseStmt:
+			if !blockTerminates(cs.Stmts) {
+				return false
+			}
+		case *DefaultStmt:
+			if !blockTerminates(cs.Stmts) {
+				return false
+			}
+			hasDefault = true
+		}
+	}
+	return hasDefault
+}
@@ -9,11 +9,14 @@ import (
 type VMRegs struct {
 	Env *core.Env
 	// Reg          []core.VMValuer // регистры значений
-	Labels       []int // [label]=index в BinCode
-	TryLabel     []int // последний элемент - это метка на текущий обработчик CATCH
-	TryRegErr    []int // последний элемент - это регистр с ошибкой текущего обработчика
-	ForBreaks    []int // последний элемент - это метка для break
-	ForContinues []int // последний элемент - это метка для continue
+	Labels       []int    // [label]=index в BinCode
+	TryLabel     []int    // последний элемент - это метка на текущий обработчик CATCH
+	TryRegErr    []int    // последний элемент - это регистр с ошибкой текущего обработчика
+	TryGens      []int    // последний элемент - это число циклов по генераторам на входе в текущий обработчик
+	ForBreaks    []int    // последний элемент - это метка для break
+	ForContinues []int    // последний элемент - это метка для continue
+	CaughtErr    error    // последняя перехваченная ошибка, для повторного выброса в RETHROW
+	ForGens      []forGen // незавершенные циклы Для каждого по каналам генераторов
 	// ReturnTo     []int           // стек возвратов по RET
 }
 
@@ -33,6 +36,7 @@ type VMRegs struct {
 func (v *VMRegs) PushTry(reg, label int) {
 	v.TryRegErr = append(v.TryRegErr, reg)
 	v.TryLabel = append(v.TryLabel, label)
+	v.TryGens = append(v.TryGens, len(v.ForGens))
 }
 
 func (v *VMRegs) TopTryLabel() int {
@@ -52,6 +56,7 @@ func (v *VMRegs) PopTry() (reg int, label int) {
 	v.TryRegErr = v.TryRegErr[0 : l-1]
 	label = v.TryLabel[l-1]
 	v.TryLabel = v.TryLabel[0 : l-1]
+	v.TryGens = v.TryGens[0 : l-1]
 	return
 }
 
@@ -95,6 +100,52 @@ func (v *VMRegs) PopContinue() (label int) {
 		return -1
 	}
 	label = v.ForContinues[l-1]
-	v.ForBreaks = v.ForContinues[0 : l-1]
+	v.ForContinues = v.ForContinues[0 : l-1]
 	return
 }
+
+// forGen - цикл Для каждого по каналу генератора, который закрывается при выходе из цикла
+type forGen struct {
+	label int // метка continue цикла
+	ch    core.VMChan
+}
+
+func (v *VMRegs) PushGen(label int, ch core.VMChan) {
+	// цикл начат заново, значит предыдущий его проход был прерван ошибкой и его генератор не нужен
+	v.CloseGens(label)
+	v.ForGens = append(v.ForGens, forGen{label: label, ch: ch})
+}
+
+// CloseGens закрывает канал генератора цикла с меткой label, а также генераторы вложенных в него циклов,
+// из которых вышли по ошибке. Канал уже завершившегося генератора закрыт им самим, повторное закрытие не мешает
+func (v *VMRegs) CloseGens(label int) {
+	for i := len(v.ForGens) - 1; i >= 0; i-- {
+		if v.ForGens[i].label == label {
+			for _, g := range v.ForGens[i:] {
+				g.ch.CloseErr()
+			}
+			v.ForGens = v.ForGens[:i]
+			return
+		}
+	}
+}
+
+// CloseTryGens закрывает генераторы циклов, начатых внутри текущего обработчика ошибок, при переходе в него
+func (v *VMRegs) CloseTryGens() {
+	l := len(v.TryGens)
+	if l == 0 || v.TryGens[l-1] >= len(v.ForGens) {
+		return
+	}
+	for _, g := range v.ForGens[v.TryGens[l-1]:] {
+		g.ch.CloseErr()
+	}
+	v.ForGens = v.ForGens[:v.TryGens[l-1]]
+}
+
+// CloseAllGens закрывает каналы генераторов всех незавершенных циклов при выходе из функции
+func (v *VMRegs) CloseAllGens() {
+	for _, g := range v.ForGens {
+		g.ch.CloseErr()
+	}
+	v.ForGens = nil
+}
@@ -36,7 +36,8 @@ func (x *BinStmts) Append(bs BinStmt) {
 type BinCode struct {
 	Code   BinStmts
 	MaxReg int
-	Labels []int //индекс - это номер метки, значение = индекс stmt в Code
+	Labels []int        //индекс - это номер метки, значение = индекс stmt в Code
+	Consts core.VMSlice // пул констант, на которые ссылаются команды LOAD
 }
 
 func (v BinCode) String() string {
@@ -57,6 +58,68 @@ func (v *BinCode) MapLabels(lastlabel int) {
 	}
 }
 
+// PoolConsts собирает одинаковые константы команд LOAD (строки, числа, булево) в пул Consts.
+// Команды ссылаются на элемент пула по номеру и используют одно общее значение,
+// а при сохранении в файл значение записывается только один раз - в пуле
+func (v *BinCode) PoolConsts() {
+	idx := make(map[core.VMValuer]int)
+	for _, stmt := range v.Code {
+		s, ok := stmt.(*BinLOAD)
+		if !ok || s.IsId {
+			continue
+		}
+		switch s.Val.(type) {
+		case core.VMString, core.VMInt, core.VMDecNum, core.VMBool:
+		default:
+			continue
+		}
+		i, ok := idx[s.Val]
+		if !ok {
+			v.Consts = append(v.Consts, s.Val)
+			i = len(v.Consts)
+			idx[s.Val] = i
+		}
+		s.Const = i
+		s.Val = v.Consts[i-1]
+	}
+}
+
+// withoutConsts возвращает копию кода, в которой команды LOAD, ссылающиеся на пул, не содержат значений
+func (v BinCode) withoutConsts() BinCode {
+	code := make(BinStmts, len(v.Code))
+	for i, stmt := range v.Code {
+		switch s := stmt.(type) {
+		case *BinLOAD:
+			if s.Const > 0 {
+				ss := *s
+				ss.Val = nil
+				stmt = &ss
+			}
+		case *BinMODULE:
+			ss := *s
+			ss.Code = s.Code.withoutConsts()
+			stmt = &ss
+		}
+		code[i] = stmt
+	}
+	v.Code = code
+	return v
+}
+
+// restoreConsts заполняет значения команд LOAD из пула после загрузки из файла
+func (v *BinCode) restoreConsts() {
+	for _, stmt := range v.Code {
+		switch s := stmt.(type) {
+		case *BinLOAD:
+			if s.Const > 0 && s.Val == nil {
+				s.Val = v.Consts[s.Const-1]
+			}
+		case *BinMODULE:
+			s.Code.restoreConsts()
+		}
+	}
+}
+
 func WriteBinCode(w io.Writer, v BinCode) error {
 	zw := gzip.NewWriter(w)
 	zw.Name = "Gonec binary code"
@@ -70,7 +133,7 @@ func WriteBinCode(w io.Writer, v BinCode) error {
 		return err
 	}
 
-	if err := enc.Encode(v); err != nil {
+	if err := enc.Encode(v.withoutConsts()); err != nil {
 		return err
 	}
 
@@ -99,6 +162,7 @@ func ReadBinCode(r io.Reader) (res BinCode, err error) {
 	if err := zr.Close(); err != nil {
 		return res, err
 	}
+	res.restoreConsts()
 
 	// переносим загруженные имена в текущий контекст
 	// и заменяем идентификаторы в загружаемом коде в случае конфликта
@@ -165,6 +229,8 @@ func init() {
 	gob.Register(&BinSETKEY{})
 	gob.Register(&BinGET{})
 	gob.Register(&BinSET{})
+	gob.Register(&BinSETGLOBAL{})
+	gob.Register(&BinDEFINE{})
 	gob.Register(&BinSETMEMBER{})
 	gob.Register(&BinSETNAME{})
 	gob.Register(&BinSETITEM{})
@@ -179,20 +245,26 @@ func init() {
 	gob.Register(&BinJTRUE{})
 	gob.Register(&BinJFALSE{})
 	gob.Register(&BinOPER{})
+	gob.Register(&BinCONCAT{})
 	gob.Register(&BinCALL{})
 	gob.Register(&BinGETMEMBER{})
 	gob.Register(&BinGETIDX{})
 	gob.Register(&BinGETSUBSLICE{})
+	gob.Register(&BinGETREST{})
 	gob.Register(&BinFUNC{})
 	gob.Register(&BinCASTTYPE{})
 	gob.Register(&BinMAKE{})
+	gob.Register(&BinSTRUCT{})
 	gob.Register(&BinMAKECHAN{})
 	gob.Register(&BinMAKEARR{})
 	gob.Register(&BinCHANRECV{})
 	gob.Register(&BinCHANSEND{})
 	gob.Register(&BinISKIND{})
+	gob.Register(&BinISTYPE{})
 	gob.Register(&BinISSLICE{})
 	gob.Register(&BinTRY{})
+	gob.Register(&BinWITH{})
+	gob.Register(&BinFINALLY{})
 	gob.Register(&BinCATCH{})
 	gob.Register(&BinPOPTRY{})
 	gob.Register(&BinFOREACH{})
@@ -205,6 +277,7 @@ func init() {
 	gob.Register(&BinCONTINUE{})
 	gob.Register(&BinRET{})
 	gob.Register(&BinTHROW{})
+	gob.Register(&BinRETHROW{})
 	gob.Register(&BinMODULE{})
 	gob.Register(&BinERROR{})
 	gob.Register(&BinTRYRECV{})
@@ -223,9 +296,10 @@ func init() {
 type BinLOAD struct {
 	BinStmtImpl
 
-	Reg  int
-	Val  core.VMValuer
-	IsId bool
+	Reg   int
+	Val   core.VMValuer
+	IsId  bool
+	Const int // номер значения в пуле констант BinCode.Consts, начиная с 1, или 0, если значение не в пуле
 }
 
 func (v *BinLOAD) SwapId(m map[int]int) {
@@ -453,6 +527,61 @@ func NewBinSET(reg, id int, e pos.Pos) *BinSET {
 	return v
 }
 
+// BinSETGLOBAL сохраняет значение в глобальном окружении - для переменных, объявленных в функции как Глобальная
+type BinSETGLOBAL struct {
+	BinStmtImpl
+
+	Id  int // id переменной
+	Reg int // регистр со значением
+}
+
+func (v *BinSETGLOBAL) SwapId(m map[int]int) {
+	if newid, ok := m[v.Id]; ok {
+		v.Id = newid
+	}
+}
+
+func (v BinSETGLOBAL) String() string {
+	return fmt.Sprintf("SETGLOBAL %q, r%d", names.UniqueNames.Get(v.Id), v.Reg)
+}
+
+func NewBinSETGLOBAL(reg, id int, e pos.Pos) *BinSETGLOBAL {
+	v := &BinSETGLOBAL{
+		Reg: reg,
+		Id:  id,
+	}
+	v.SetPosition(e.Position())
+	return v
+}
+
+// BinDEFINE создает переменную в текущем окружении - для объявления оператором :=,
+// в том числе когда имя объявлено в функции как Глобальная
+type BinDEFINE struct {
+	BinStmtImpl
+
+	Id  int // id переменной
+	Reg int // регистр со значением
+}
+
+func (v *BinDEFINE) SwapId(m map[int]int) {
+	if newid, ok := m[v.Id]; ok {
+		v.Id = newid
+	}
+}
+
+func (v BinDEFINE) String() string {
+	return fmt.Sprintf("DEFINE %q, r%d", names.UniqueNames.Get(v.Id), v.Reg)
+}
+
+func NewBinDEFINE(reg, id int, e pos.Pos) *BinDEFINE {
+	v := &BinDEFINE{
+		Reg: reg,
+		Id:  id,
+	}
+	v.SetPosition(e.Position())
+	return v
+}
+
 type BinSETMEMBER struct {
 	BinStmtImpl
 
@@ -772,6 +901,26 @@ func NewBinOPER(regl, regr int, op core.VMOperation, e pos.Pos) *BinOPER {
 	return v
 }
 
+type BinCONCAT struct {
+	BinStmtImpl
+
+	Reg   int // первое слагаемое, сюда же помещается результат
+	Count int // число слагаемых в регистрах, начиная с Reg
+}
+
+func (v BinCONCAT) String() string {
+	return fmt.Sprintf("CONCAT r%d, COUNT %d", v.Reg, v.Count)
+}
+
+func NewBinCONCAT(reg, count int, e pos.Pos) *BinCONCAT {
+	v := &BinCONCAT{
+		Reg:   reg,
+		Count: count,
+	}
+	v.SetPosition(e.Position())
+	return v
+}
+
 type BinCALL struct {
 	BinStmtImpl
 
@@ -788,6 +937,8 @@ type BinCALL struct {
 	VarArg bool
 
 	Go bool // признак необходимости запуска в новой горутине
+
+	Detached bool // горутина запущена отдельным оператором, ее результат никто не получит
 }
 
 func (v *BinCALL) SwapId(m map[int]int) {
@@ -889,6 +1040,27 @@ func NewBinGETSUBSLICE(reg, regbegin, regend int, e pos.Pos) *BinGETSUBSLICE {
 	return v
 }
 
+// BinGETREST получает в Reg остаток слайса из Reg, начиная с элемента From (пустой, если элементов не больше From)
+type BinGETREST struct {
+	BinStmtImpl
+
+	Reg  int
+	From int
+}
+
+func (v BinGETREST) String() string {
+	return fmt.Sprintf("REST r%d[%d:]", v.Reg, v.From)
+}
+
+func NewBinGETREST(reg, from int, e pos.Pos) *BinGETREST {
+	v := &BinGETREST{
+		Reg:  reg,
+		From: from,
+	}
+	v.SetPosition(e.Position())
+	return v
+}
+
 type BinFUNC struct {
 	BinStmtImpl
 
@@ -901,6 +1073,8 @@ type BinFUNC struct {
 	VarArg     bool
 	// ReturnTo int //метка инструкции возврата из функции
 	MaxReg int // максимальный регистр, достигаемый внутри функции, без учета вызова вложенных функций
+	// RecvType - id типа структуры, если это метод; получатель передается первым аргументом
+	RecvType int
 }
 
 func (v *BinFUNC) SwapId(m map[int]int) {
@@ -908,6 +1082,9 @@ func (v *BinFUNC) SwapId(m map[int]int) {
 		v.Name = newid
 		// log.Printf("Замена в %#v %v\n",v, v)
 	}
+	if newid, ok := m[v.RecvType]; ok && v.RecvType != 0 {
+		v.RecvType = newid
+	}
 	for i := range v.Args {
 		if newid, ok := m[v.Args[i]]; ok && v.Args[i] != 0 {
 			v.Args[i] = newid
@@ -927,6 +1104,9 @@ func (v BinFUNC) String() string {
 	if v.VarArg {
 		vrg = "..."
 	}
+	if v.RecvType != 0 {
+		return fmt.Sprintf("FUNC r%d, %s.%q (%s%s) BEGIN L%d END L%d", v.Reg, names.UniqueNames.Get(v.RecvType), names.UniqueNames.Get(v.Name), s, vrg, v.LabelStart, v.LabelEnd)
+	}
 	return fmt.Sprintf("FUNC r%d, %q (%s%s) BEGIN L%d END L%d", v.Reg, names.UniqueNames.Get(v.Name), s, vrg, v.LabelStart, v.LabelEnd)
 }
 
@@ -981,6 +1161,44 @@ func NewBinMAKE(reg int, e pos.Pos) *BinMAKE {
 	return v
 }
 
+type BinSTRUCT struct {
+	BinStmtImpl
+
+	Name   int   // id имени типа структуры
+	Fields []int // id полей структуры
+}
+
+func (v *BinSTRUCT) SwapId(m map[int]int) {
+	if newid, ok := m[v.Name]; ok {
+		v.Name = newid
+	}
+	for i := range v.Fields {
+		if newid, ok := m[v.Fields[i]]; ok {
+			v.Fields[i] = newid
+		}
+	}
+}
+
+func (v BinSTRUCT) String() string {
+	s := ""
+	for _, f := range v.Fields {
+		if s != "" {
+			s += ", "
+		}
+		s += names.UniqueNames.Get(f)
+	}
+	return fmt.Sprintf("STRUCT %s {%s}", names.UniqueNames.Get(v.Name), s)
+}
+
+func NewBinSTRUCT(name int, fields []int, e pos.Pos) *BinSTRUCT {
+	v := &BinSTRUCT{
+		Name:   name,
+		Fields: fields,
+	}
+	v.SetPosition(e.Position())
+	return v
+}
+
 type BinMAKECHAN struct {
 	BinStmtImpl
 
@@ -1079,6 +1297,34 @@ func NewBinISKIND(reg int, reflkind reflect.Kind, e pos.Pos) *BinISKIND {
 	return v
 }
 
+type BinISTYPE struct {
+	BinStmtImpl
+
+	Reg     int // значение для проверки
+	RegBool int // сюда возвращается bool
+	Type    int // id имени типа
+}
+
+func (v *BinISTYPE) SwapId(m map[int]int) {
+	if newid, ok := m[v.Type]; ok {
+		v.Type = newid
+	}
+}
+
+func (v BinISTYPE) String() string {
+	return fmt.Sprintf("ISTYPE r%d, r%d, %q", v.Reg, v.RegBool, names.UniqueNames.Get(v.Type))
+}
+
+func NewBinISTYPE(reg, regbool, typ int, e pos.Pos) *BinISTYPE {
+	v := &BinISTYPE{
+		Reg:     reg,
+		RegBool: regbool,
+		Type:    typ,
+	}
+	v.SetPosition(e.Position())
+	return v
+}
+
 type BinISSLICE struct {
 	BinStmtImpl
 
@@ -1119,6 +1365,63 @@ func NewBinTRY(reg, lb int, e pos.Pos) *BinTRY {
 	return v
 }
 
+// BinWITH начинает блок Используя: как и BinTRY, отслеживает ошибки до метки JumpTo,
+// дополнительно хранит регистр ресурса, который закрывается при любом выходе из блока
+type BinWITH struct {
+	BinStmtImpl
+
+	Reg    int // регистр, куда будет помещаться error во время выполнения блока
+	RegRes int // регистр с ресурсом
+	JumpTo int // метка окончания блока, на которой ресурс закрывается
+}
+
+func (v BinWITH) String() string {
+	return fmt.Sprintf("WITH r%d, RES r%d, CLOSE L%d", v.Reg, v.RegRes, v.JumpTo)
+}
+
+func NewBinWITH(reg, regres, lb int, e pos.Pos) *BinWITH {
+	v := &BinWITH{
+		Reg:    reg,
+		RegRes: regres,
+		JumpTo: lb,
+	}
+	v.SetPosition(e.Position())
+	return v
+}
+
+// BinFINALLY начинает блок Попытка с разделом Окончательно, который начинается с метки JumpTo.
+// Прервать, Продолжить и Возврат внутри блока сначала переходят к разделу Окончательно, запомнив в RegAction
+// код отложенного действия (а Возврат - значение в RegValue), и действие выполняется после раздела
+type BinFINALLY struct {
+	BinStmtImpl
+
+	RegAction int
+	RegValue  int
+	JumpTo    int
+}
+
+func (v BinFINALLY) String() string {
+	return fmt.Sprintf("FINALLY r%d, VAL r%d, L%d", v.RegAction, v.RegValue, v.JumpTo)
+}
+
+func NewBinFINALLY(regaction, regvalue, lb int, e pos.Pos) *BinFINALLY {
+	v := &BinFINALLY{
+		RegAction: regaction,
+		RegValue:  regvalue,
+		JumpTo:    lb,
+	}
+	v.SetPosition(e.Position())
+	return v
+}
+
+// Коды отложенных действий в регистре RegAction блока BinFINALLY
+const (
+	FinallyNone = iota
+	FinallyBreak
+	FinallyContinue
+	FinallyReturn
+)
+
 type BinCATCH struct {
 	BinStmtImpl
 
@@ -1360,6 +1663,25 @@ func NewBinTHROW(reg int, e pos.Pos) *BinTHROW {
 	return v
 }
 
+// BinRETHROW повторно выбрасывает перехваченную ошибку, если она есть в регистре Reg
+type BinRETHROW struct {
+	BinStmtImpl
+
+	Reg int
+}
+
+func (v BinRETHROW) String() string {
+	return fmt.Sprintf("RETHROW r%d", v.Reg)
+}
+
+func NewBinRETHROW(reg int, e pos.Pos) *BinRETHROW {
+	v := &BinRETHROW{
+		Reg: reg,
+	}
+	v.SetPosition(e.Position())
+	return v
+}
+
 type BinMODULE struct {
 	BinStmtImpl
 
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/shinanca/gonec/core"
 	posit "github.com/shinanca/gonec/pos"
 )
 
@@ -12,6 +13,7 @@ import (
 type Error struct {
 	Message string
 	Pos     posit.Position
+	Thrown  core.VMValuer // значение из ВызватьИсключение, nil для ошибок времени исполнения
 }
 
 var (
@@ -19,6 +21,10 @@ var (
 	ContinueError  = errors.New("Неверное применение оператора Продолжить")
 	ReturnError    = errors.New("Неверное применение оператора Возврат")
 	InterruptError = errors.New("Выполнение прервано")
+	// CancelError возвращается при отмене контекста исполнения, перехватить ее в Попытка нельзя
+	CancelError = errors.New("Выполнение отменено")
+	// BudgetError возвращается при исчерпании лимита инструкций окружения, перехватить ее в Попытка нельзя
+	BudgetError = errors.New("Превышен лимит исполняемых инструкций")
 )
 
 // NewStringError makes error interface with message.
@@ -40,7 +46,7 @@ func NewError(pos posit.Pos, err error) error {
 	if err == nil {
 		return nil
 	}
-	if err == BreakError || err == ContinueError || err == ReturnError {
+	if err == BreakError || err == ContinueError || err == ReturnError || err == BudgetError || errors.Is(err, CancelError) {
 		return err
 	}
 	// if pe, ok := err.(*parser.Error); ok {
@@ -62,3 +68,30 @@ func (e *Error) String() string {
 	// учитываем вставку модуля _ по умолчанию - вычитаем 1 из номера строки
 	return e.Message
 }
+
+// Info возвращает значение, доступное в блоке Исключение через ЗначениеОшибки().
+// Значение, переданное в ВызватьИсключение, возвращается как есть,
+// для остальных ошибок - структура с описанием, как в Details
+func (e *Error) Info() core.VMValuer {
+	if e.Thrown != nil {
+		return e.Thrown
+	}
+	return e.Details()
+}
+
+// Details возвращает структуру с описанием ошибки, строкой и колонкой, где она возникла, и типом ошибки,
+// доступную в блоке Исключение через ИнформацияОбОшибке().
+// Для исключений из ВызватьИсключение это место вызова, а исходное значение хранится в поле Значение
+func (e *Error) Details() core.VMStringMap {
+	m := core.VMStringMap{
+		"Сообщение": core.VMString(e.Message),
+		"Строка":    core.VMInt(e.Pos.Line - 1),
+		"Колонка":   core.VMInt(e.Pos.Column),
+		"Тип":       core.VMString("ОшибкаВыполнения"),
+	}
+	if e.Thrown != nil {
+		m["Тип"] = core.VMString("Исключение")
+		m["Значение"] = e.Thrown
+	}
+	return m
+}
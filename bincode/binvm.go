@@ -3,27 +3,43 @@ package bincode
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"reflect"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/shinanca/gonec/ast"
 	"github.com/shinanca/gonec/bincode/binstmt"
 	"github.com/shinanca/gonec/core"
 	"github.com/shinanca/gonec/names"
 	"github.com/shinanca/gonec/parser"
+	posit "github.com/shinanca/gonec/pos"
 )
 
 func Interrupt(env *core.Env) {
 	env.Interrupt()
 }
 
+// CompileOptions параметры компиляции исходного кода
+type CompileOptions struct {
+	// NoFolding отключает оптимизацию дерева (свертку констант), чтобы отлаживать байткод,
+	// в точности соответствующий исходному тексту
+	NoFolding bool
+}
+
 // ParseSrc provides way to parse the code from source.
 func ParseSrc(src string) (prs ast.Stmts, bin binstmt.BinCode, err error) {
+	return ParseSrcWithOptions(src, CompileOptions{})
+}
+
+// ParseSrcWithOptions компилирует исходный код с заданными параметрами
+func ParseSrcWithOptions(src string, opts CompileOptions) (prs ast.Stmts, bin binstmt.BinCode, err error) {
 	defer func() {
 		// если это не паника из кода языка
 		// if os.Getenv("GONEC_DEBUG") == "" {
@@ -38,6 +54,15 @@ func ParseSrc(src string) (prs ast.Stmts, bin binstmt.BinCode, err error) {
 		// }
 	}()
 
+	prs, err = parseSrc(src)
+	if err != nil {
+		panic(err)
+	}
+	return CompileStmts(prs, opts)
+}
+
+// parseSrc разбирает исходный код без компиляции
+func parseSrc(src string) (ast.Stmts, error) {
 	// По умолчанию добавляем глобальный модуль "_" в начало, чтобы код без заголовка "модуль" мог успешно исполниться
 	// Если будет объявлен модуль в коде, он скроет данное объявление
 	src = "Модуль _\n" + src
@@ -45,18 +70,119 @@ func ParseSrc(src string) (prs ast.Stmts, bin binstmt.BinCode, err error) {
 	scanner := &parser.Scanner{}
 	scanner.Init(src)
 
-	prs, err = parser.Parse(scanner)
-	if err != nil {
-		panic(err)
+	prs, err := parser.Parse(scanner)
+	if e, ok := err.(*parser.Error); ok {
+		// учитываем вставку модуля _ по умолчанию
+		e.Pos.Line--
 	}
+	return prs, err
+}
+
+// CompileStmts компилирует уже разобранное дерево, например, полученное построчным разбором в интерактивном режиме
+func CompileStmts(prs ast.Stmts, opts CompileOptions) (_ ast.Stmts, bin binstmt.BinCode, err error) {
+	defer func() {
+		if ex := recover(); ex != nil {
+			if e, ok := ex.(error); ok {
+				err = e
+			} else {
+				err = errors.New(fmt.Sprint(ex))
+			}
+		}
+	}()
+
 	// оптимизируем дерево AST
 	// свертка констант и нативные значения
-	prs = parser.ConstFolding(prs)
+	if !opts.NoFolding {
+		prs = parser.ConstFolding(prs)
+	}
 	// компиляция в бинарный код
-	lid := 0
-	bin = prs.BinaryCode(0, &lid)
+	var cc ast.CompileCtx
+	bin = prs.BinaryCode(0, &cc)
 
-	return prs, bin, err
+	return prs, bin, nil
+}
+
+// Check разбирает и компилирует исходный код без исполнения и возвращает все найденные ошибки,
+// например, для проверки кода в редакторе. Синтаксическая ошибка прекращает разбор,
+// а ошибки компиляции ищутся в каждом операторе верхнего уровня отдельно, поэтому их может быть несколько.
+// В список также попадает недостижимый код. Ошибки упорядочены по позиции в исходном коде
+func Check(src string, opts CompileOptions) (errs []error) {
+	prs, err := parseSrc(src)
+	if err != nil {
+		return []error{err}
+	}
+	for _, st := range prs {
+		if mod, ok := st.(*ast.ModuleStmt); ok {
+			for _, mst := range mod.Stmts {
+				if err := checkStmt(mst, opts); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		} else if err := checkStmt(st, opts); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	errs = append(errs, prs.Unreachable()...)
+	sort.SliceStable(errs, func(i, j int) bool {
+		pi, pj := errorPos(errs[i]), errorPos(errs[j])
+		return pi.Line < pj.Line || pi.Line == pj.Line && pi.Column < pj.Column
+	})
+	return
+}
+
+// checkStmt компилирует один оператор, перехватывая ошибку компиляции
+func checkStmt(st ast.Stmt, opts CompileOptions) (err error) {
+	defer func() {
+		if ex := recover(); ex != nil {
+			if e, ok := ex.(error); ok {
+				err = e
+			} else {
+				err = errors.New(fmt.Sprint(ex))
+			}
+		}
+	}()
+
+	if !opts.NoFolding {
+		st.Simplify()
+	}
+	var bins binstmt.BinStmts
+	var cc ast.CompileCtx
+	maxreg := 0
+	st.BinTo(&bins, 0, &cc, &maxreg)
+	return nil
+}
+
+// errorPos возвращает позицию ошибки компиляции, ошибки без позиции считаются стоящими в начале
+func errorPos(err error) posit.Position {
+	if e, ok := err.(*binstmt.Error); ok {
+		return e.Pos
+	}
+	return posit.Position{}
+}
+
+// errorInfo возвращает значение перехваченной ошибки для функции ЗначениеОшибки()
+// и структуру с ее описанием и позицией для функции ИнформацияОбОшибке()
+func errorInfo(err error) (info core.VMValuer, details core.VMStringMap) {
+	if e, ok := err.(*binstmt.Error); ok {
+		return e.Info(), e.Details()
+	}
+	details = core.VMStringMap{
+		"Сообщение": core.VMString(err.Error()),
+		"Тип":       core.VMString("ОшибкаВыполнения"),
+	}
+	return details, details
+}
+
+// defineErrorFunc определяет в окружении функцию без параметров, возвращающую сведения о перехваченной ошибке
+func defineErrorFunc(env *core.Env, name string, v core.VMValuer) {
+	env.DefineS(name, core.VMFunc(func(args core.VMSlice, rets *core.VMSlice, envout *(*core.Env)) error {
+		*envout = env
+		if len(args) != 0 {
+			return errors.New("Данная функция не требует параметров")
+		}
+		rets.Append(v)
+		return nil
+	}))
 }
 
 var binRegsPool = sync.Pool{}
@@ -144,6 +270,66 @@ func Run(stmts binstmt.BinCode, env *core.Env) (retval core.VMValuer, reterr err
 			return errors.New("Должен быть параметр-строка")
 		}))
 
+		env.DefineS("выполнить", core.VMFuncMustParams(1, func(args core.VMSlice, rets *core.VMSlice, envout *(*core.Env)) error {
+			// фрагмент исполняется в окружении вызывающего кода, если оно передано
+			eenv := env
+			if *envout != nil {
+				eenv = *envout
+			}
+			*envout = env
+			s, ok := args[0].(core.VMString)
+			if !ok {
+				return core.VMErrorNeedString
+			}
+			rv, err := Eval(string(s), eenv)
+			if err != nil {
+				return err
+			}
+			rets.Append(rv)
+			return nil
+		}))
+
+		env.DefineS("вычислитьизолированно", core.VMFunc(func(args core.VMSlice, rets *core.VMSlice, envout *(*core.Env)) error {
+			// ограничения исполнения берутся из окружения вызывающего кода, если оно передано
+			cenv := env
+			if *envout != nil {
+				cenv = *envout
+			}
+			*envout = env
+			if len(args) < 2 || len(args) > 3 {
+				return errors.New("Должны быть код, структура входных переменных и, при необходимости, массив разрешенных функций")
+			}
+			s, ok := args[0].(core.VMString)
+			if !ok {
+				return core.VMErrorNeedString
+			}
+			inputs, ok := args[1].(core.VMStringMap)
+			if !ok {
+				return core.VMErrorNeedMap
+			}
+			allowed := SandboxBuiltins
+			if len(args) == 3 {
+				sl, ok := args[2].(core.VMSlice)
+				if !ok {
+					return core.VMErrorNeedSlice
+				}
+				allowed = make([]string, len(sl))
+				for i := range sl {
+					name, ok := sl[i].(core.VMString)
+					if !ok {
+						return core.VMErrorNeedString
+					}
+					allowed[i] = string(name)
+				}
+			}
+			rv, err := SandboxEval(cenv, string(s), inputs, allowed)
+			if err != nil {
+				return err
+			}
+			rets.Append(rv)
+			return nil
+		}))
+
 		core.LoadAllBuiltins(env)
 	}
 
@@ -152,6 +338,59 @@ func Run(stmts binstmt.BinCode, env *core.Env) (retval core.VMValuer, reterr err
 	return
 }
 
+// RunContext исполняет код так же, как Run, но прекращает исполнение при отмене ctx.
+// Отмена проверяется периодически между инструкциями, в том числе в вызванных функциях и горутинах,
+// и возвращает ошибку binstmt.CancelError с причиной отмены; код на языке Гонец перехватить ее не может.
+// Ожидание внутри встроенных функций (Пауза, чтение из канала) отменой не прерывается.
+// После исполнения в окружении восстанавливается прежний контекст, и следующий запуск в нем ctx не наследует
+func RunContext(ctx context.Context, stmts binstmt.BinCode, env *core.Env) (core.VMValuer, error) {
+	prev := env.SetContext(ctx)
+	defer env.SetContext(prev)
+	return Run(stmts, env)
+}
+
+// DefaultMaxCallDepth ограничивает вложенность вызовов функций в одной горутине, если в окружении
+// не установлено другое ограничение (Env.SetMaxCallDepth), чтобы бесконечная рекурсия вызывала
+// перехватываемое исключение, а не переполнение стека Go
+const DefaultMaxCallDepth = 10000
+
+// MaxEvalDepth ограничивает вложенность вызовов Выполнить в одном окружении, чтобы фрагмент, выполняющий сам себя,
+// не исчерпал стек. Вложенность через вызовы функций ограничивается глубиной вызовов
+const MaxEvalDepth = 100
+
+// Eval компилирует и исполняет фрагмент кода в окружении env, в котором уже загружена стандартная библиотека.
+// Переменные фрагмента и окружения общие. Возвращается значение последнего оператора фрагмента,
+// если он является выражением, иначе Неопределено
+func Eval(src string, env *core.Env) (core.VMValuer, error) {
+	if env.EnterEval() > MaxEvalDepth {
+		env.LeaveEval()
+		return nil, core.VMErrorEvalDepth
+	}
+	defer env.LeaveEval()
+
+	prs, bins, err := ParseSrc(src)
+	if err != nil {
+		return nil, err
+	}
+	// значение последнего выражения остается в нулевом регистре, возвращаем его
+	if mod, ok := prs[len(prs)-1].(*ast.ModuleStmt); ok && len(mod.Stmts) > 0 {
+		if st, ok := mod.Stmts[len(mod.Stmts)-1].(*ast.ExprStmt); ok {
+			// сравнение на уровне оператора - это присваивание, оно значения не возвращает
+			if be, ok := st.Expr.(*ast.BinOpExpr); !ok || be.Operator != "==" {
+				bins.Code.Append(binstmt.NewBinRET(0, st))
+			}
+		}
+	}
+	rv, err := Run(bins, env)
+	if err == binstmt.ReturnError {
+		err = nil
+	}
+	if rv == nil {
+		rv = core.VMNil
+	}
+	return rv, err
+}
+
 // RunWorker исполняет кусок кода, начиная с инструкции idx
 func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.Env, idx int) (retval core.VMValuer, reterr error) {
 	defer func() {
@@ -181,15 +420,24 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 		ForBreaks:    make([]int, 0, 8),
 		ForContinues: make([]int, 0, 8),
 	}
+	// генераторы циклов, из которых вышли через Возврат или ошибку, больше не будут прочитаны
+	defer regs.CloseAllGens()
 
 	var (
 		catcherr error
 	)
 
 	cntInterrupt := 0
+	ctx := env.Context()
+	done := ctx.Done()
+	budget := env.InstructionBudget()
 
 	for idx < len(stmts) {
 
+		if budget != nil && atomic.AddInt64(budget, -1) < 0 {
+			return nil, binstmt.BudgetError
+		}
+
 		// проверка прерывания каждые 10 команд
 		cntInterrupt++
 		if cntInterrupt == 10 {
@@ -198,6 +446,13 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 				// проверяем, был ли прерван интерпретатор
 				return nil, binstmt.InterruptError
 			}
+			if done != nil {
+				select {
+				case <-done:
+					return nil, fmt.Errorf("%w: %v", binstmt.CancelError, ctx.Err())
+				default:
+				}
+			}
 		}
 
 		stmt := stmts[idx]
@@ -250,12 +505,20 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 			// всегда сохраняются локальные переменные, глобальные и из внешнего окружения можно только читать
 			env.Define(s.Id, registers[s.Reg])
 
+		case *binstmt.BinSETGLOBAL:
+			// переменная объявлена в функции как Глобальная и относится к модулю, в котором объявлена функция
+			env.DefineModule(s.Id, registers[s.Reg])
+
+		case *binstmt.BinDEFINE:
+			// объявление оператором := всегда создает переменную в текущем окружении
+			env.Define(s.Id, registers[s.Reg])
+
 		case *binstmt.BinOPER:
 			v1 := registers[s.RegL]
 			v2 := registers[s.RegR]
 			if vv1, ok := v1.(core.VMOperationer); ok {
 				if vv2, ok := v2.(core.VMOperationer); ok {
-					if rv, err := vv1.EvalBinOp(s.Op, vv2); err == nil {
+					if rv, err := core.EvalBinOpEnv(env, s.Op, vv1, vv2); err == nil {
 						registers[s.RegL] = rv
 					} else {
 						catcherr = binstmt.NewError(stmt, err)
@@ -270,12 +533,40 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 				goto catching
 			}
 
+		case *binstmt.BinCONCAT:
+			args := registers[s.Reg : s.Reg+s.Count]
+			if str, ok := core.ConcatVMStrings(args); ok {
+				registers[s.Reg] = str
+				break
+			}
+			// не все значения строки - складываем по очереди
+			v := args[0]
+			for _, v2 := range args[1:] {
+				vv1, ok := v.(core.VMOperationer)
+				if !ok {
+					catcherr = binstmt.NewStringError(stmt, "Значение нельзя использовать в выражении")
+					goto catching
+				}
+				vv2, ok := v2.(core.VMOperationer)
+				if !ok {
+					catcherr = binstmt.NewStringError(stmt, "Значение нельзя использовать в выражении")
+					goto catching
+				}
+				rv, err := core.EvalBinOpEnv(env, core.ADD, vv1, vv2)
+				if err != nil {
+					catcherr = binstmt.NewError(stmt, err)
+					goto catching
+				}
+				v = rv
+			}
+			registers[s.Reg] = v
+
 		case *binstmt.BinEQUAL:
 			v1 := registers[s.Reg1]
 			v2 := registers[s.Reg2]
 			if vv1, ok := v1.(core.VMOperationer); ok {
 				if vv2, ok := v2.(core.VMOperationer); ok {
-					if rv, err := vv1.EvalBinOp(core.EQL, vv2); err == nil {
+					if rv, err := core.EvalBinOpEnv(env, core.EQL, vv1, vv2); err == nil {
 						registers[s.Reg] = rv
 					} else {
 						catcherr = binstmt.NewError(stmt, err)
@@ -292,9 +583,9 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 
 		case *binstmt.BinCASTNUM:
 			// ошибки обрабатываем в попытке
-			var num core.VMNumberer
+			var num core.VMNumberInvoker
 			var ok bool
-			if num, ok = registers[s.Reg].(core.VMNumberer); !ok {
+			if num, ok = registers[s.Reg].(core.VMNumberInvoker); !ok {
 				registers[s.Reg] = nil
 				catcherr = binstmt.NewStringError(stmt, "Литерал должен быть числом")
 				break
@@ -336,6 +627,16 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 				mm.VMSetField(s.Id, mv.(core.VMInterfacer))
 			case core.VMStringMap:
 				mm[names.UniqueNames.Get(s.Id)] = mv
+			case *core.VMOrderedMap:
+				mm.Set(names.UniqueNames.Get(s.Id), mv)
+			case *core.VMFrozen:
+				catcherr = binstmt.NewError(stmt, core.VMErrorFrozen)
+				goto catching
+			case *core.VMStruct:
+				if err := mm.SetField(s.Id, mv); err != nil {
+					catcherr = binstmt.NewError(stmt, err)
+					goto catching
+				}
 			default:
 				catcherr = binstmt.NewStringError(stmt, "Невозможно установить поле у значения")
 				goto catching
@@ -363,25 +664,39 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 				// если ее надо вызвать в горутине - вызываем
 				if s.Go {
 					// env.SetGoRunned(true)
-					rets := core.GetGlobalVMSlice()   // для каждой горутины отдельный массив возвратов, который потом не используется
+					rets := core.GetGlobalVMSlice()   // для каждой горутины отдельный массив возвратов
 					goargs := core.GetGlobalVMSlice() // для горутин аргументы надо скопировать!
 					goargs = append(goargs, argsl...)
-					go func(a, r core.VMSlice) {
+					fut := core.NewVMFuture()
+					go func(a, r core.VMSlice, detached bool) {
 						var e *core.Env
 						err := fnc(a, &r, &e)
+						var rv core.VMValuer = core.VMNil
+						switch len(r) {
+						case 0:
+						case 1:
+							rv = r[0]
+						default:
+							rv = append(core.VMSlice(nil), r...)
+						}
 						core.PutGlobalVMSlice(a) // всегда возвращаем в пул
 						core.PutGlobalVMSlice(r) // всегда возвращаем в пул
-						if err != nil && e.Valid {
+						// ошибка передается будущему и вызывается при получении результата,
+						// а если результат никто не получит, то выводится, чтобы не потеряться
+						if err != nil && detached && e != nil && e.Valid {
 							e.Println(err)
 						}
-					}(goargs, rets)
-					registers[s.RegRets] = core.VMSlice{} // для такого вызова - всегда пустой массив возвратов
+						fut.Resolve(rv, err)
+					}(goargs, rets, s.Detached)
+					// результат функции можно получить через Результат() или Ждать()
+					registers[s.RegRets] = fut
 					break
 				}
 
 				rets := core.GetGlobalVMSlice()
 				// не в горутине
-				var fenv *core.Env
+				// функция получает окружение вызывающего кода и заменяет его своим
+				fenv := env
 				err = fnc(argsl, &rets, &fenv)
 
 				// TODO: проверить, если был передан слайс, и он изменен внутри функции, то что происходит в исходном слайсе?
@@ -419,14 +734,29 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 							return binstmt.NewStringError(expr, "Неверное количество аргументов")
 						}
 					}
+					// глубина считается от окружения вызывающего кода, вызов из горутины или из Go начинает отсчет заново
+					depth, cenv := 1, fenv
+					if *envout != nil {
+						depth, cenv = (*envout).CallDepth()+1, *envout
+					}
+					maxDepth := cenv.MaxCallDepth()
+					if maxDepth == 0 {
+						maxDepth = DefaultMaxCallDepth
+					}
+					if depth > maxDepth {
+						return binstmt.NewError(expr, core.VMErrorCallDepth)
+					}
 					var newenv *core.Env
 					if expr.Name == 0 {
 						// наследуем от окружения текущей функции
 						newenv = fenv.NewSubEnv()
 					} else {
 						// наследуем от модуля или глобального окружения
-						newenv = fenv.NewEnv()
+						newenv = fenv.NewModuleEnv()
 					}
+					newenv.SetCallDepth(depth)
+					// ограничение переходит в вызванную функцию, даже если ее окружение не вложено в окружение вызова
+					newenv.SetMaxCallDepth(maxDepth)
 
 					// переменное число аргументов передается как один параметр-слайс
 					if expr.VarArg {
@@ -456,7 +786,17 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 				}
 			}(s, stmts, labels, env)
 
-			env.Define(s.Name, f)
+			if s.RecvType != 0 {
+				// метод регистрируется в типе структуры, а не в окружении
+				st, ok := env.StructType(s.RecvType)
+				if !ok {
+					catcherr = binstmt.NewStringError(stmt, "Структура '"+names.UniqueNames.Get(s.RecvType)+"' не объявлена")
+					break
+				}
+				st.DefineMethod(s.Name, f)
+			} else {
+				env.Define(s.Name, f)
+			}
 			registers[s.Reg] = f
 			idx = regs.Labels[s.LabelEnd]
 
@@ -485,6 +825,13 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 				}
 				registers[s.Reg] = m
 				goto catching
+			case *core.VMStruct:
+				rv, err := vv.Member(s.Name)
+				if err != nil {
+					catcherr = binstmt.NewError(stmt, err)
+					goto catching
+				}
+				registers[s.Reg] = rv
 			case core.VMStringMap:
 				// Сначала ищем поле, в нем может быть переопределен метод как функция
 				if rv, ok := vv[names.UniqueNames.Get(s.Name)]; ok {
@@ -496,6 +843,23 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 						registers[s.Reg] = core.VMNil
 					}
 				}
+			case *core.VMOrderedMap:
+				if rv, ok := vv.Get(names.UniqueNames.Get(s.Name)); ok {
+					registers[s.Reg] = rv
+				} else {
+					if ff, ok := vv.MethodMember(s.Name); ok {
+						registers[s.Reg] = ff
+					} else {
+						registers[s.Reg] = core.VMNil
+					}
+				}
+			case *core.VMFrozen:
+				rv, err := vv.Member(s.Name)
+				if err != nil {
+					catcherr = binstmt.NewError(stmt, err)
+					goto catching
+				}
+				registers[s.Reg] = rv
 			case core.VMMetaObject:
 				if vv.VMIsField(s.Name) {
 					registers[s.Reg] = vv.VMGetField(s.Name)
@@ -556,11 +920,30 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 				}
 			case core.VMStringMap:
 				if k, ok := i.(core.VMString); ok {
-					registers[s.Reg] = vv[string(k)]
+					// отсутствующий ключ дает Неопределено
+					if iv, ok := vv[string(k)]; ok {
+						registers[s.Reg] = iv
+					} else {
+						registers[s.Reg] = core.VMNil
+					}
 				} else {
 					catcherr = binstmt.NewStringError(stmt, "Ключ должен быть строкой")
 					goto catching
 				}
+			case *core.VMOrderedMap:
+				if k, ok := i.(core.VMString); ok {
+					registers[s.Reg] = vv.IndexVal(k)
+				} else {
+					catcherr = binstmt.NewStringError(stmt, "Ключ должен быть строкой")
+					goto catching
+				}
+			case *core.VMFrozen:
+				rv, err := vv.Index(i)
+				if err != nil {
+					catcherr = binstmt.NewError(stmt, err)
+					goto catching
+				}
+				registers[s.Reg] = rv
 			case core.VMIndexer:
 				if iv, ok := i.(core.VMInt); ok {
 					ii := int(iv)
@@ -609,12 +992,23 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 				if s, ok := i.(core.VMString); ok {
 					vv[string(s)] = rv
 				}
+			case *core.VMOrderedMap:
+				if s, ok := i.(core.VMString); ok {
+					vv.Set(string(s), rv)
+				}
+			case *core.VMFrozen:
+				catcherr = binstmt.NewError(stmt, core.VMErrorFrozen)
+				goto catching
 			default:
 				catcherr = binstmt.NewStringError(stmt, "Неверная операция")
 				goto catching
 			}
 
 		case *binstmt.BinSETSLICE:
+			if _, ok := registers[s.Reg].(*core.VMFrozen); ok {
+				catcherr = binstmt.NewError(stmt, core.VMErrorFrozen)
+				goto catching
+			}
 			if vv, ok := registers[s.Reg].(core.VMSlice); ok {
 				if rv, ok := registers[s.RegVal].(core.VMSlice); ok {
 
@@ -756,6 +1150,18 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 		// 	}
 		// 	regs.Set(s.Reg, m.Elem().Interface())
 
+		case *binstmt.BinGETREST:
+			vv, ok := registers[s.Reg].(core.VMSlice)
+			if !ok {
+				catcherr = binstmt.NewError(stmt, core.VMErrorNeedSlice)
+				goto catching
+			}
+			if s.From < len(vv) {
+				registers[s.Reg] = vv[s.From:]
+			} else {
+				registers[s.Reg] = make(core.VMSlice, 0)
+			}
+
 		case *binstmt.BinGETSUBSLICE:
 
 			var rb int
@@ -851,6 +1257,11 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 				catcherr = binstmt.NewStringError(stmt, "Неизвестный тип")
 				break
 			}
+			if st, ok := env.StructType(int(eType)); ok {
+				// пользовательская структура, объявленная в коде
+				registers[s.Reg] = st.New()
+				break
+			}
 			rt, err := env.Type(int(eType))
 			if err != nil {
 				catcherr = binstmt.NewError(stmt, err)
@@ -878,6 +1289,12 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 				break
 			}
 
+		case *binstmt.BinSTRUCT:
+			if err := env.DefineStructType(core.NewVMStructType(s.Name, s.Fields)); err != nil {
+				catcherr = binstmt.NewError(stmt, err)
+				break
+			}
+
 		case *binstmt.BinMAKECHAN:
 			size, ok := registers[s.Reg].(core.VMInt)
 			if !ok {
@@ -909,6 +1326,10 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 				break
 			}
 			v, ok := ch.Recv()
+			if err := core.SenderError(v); err != nil {
+				catcherr = binstmt.NewError(stmt, err)
+				break
+			}
 			if !ok {
 				// если закрыт, то пишем nil
 				registers[s.RegVal] = core.VMNil
@@ -922,13 +1343,23 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 				catcherr = binstmt.NewStringError(stmt, "Не является каналом")
 				break
 			}
-			v := registers[s.RegVal]
-			ch.Send(v)
+			if err := ch.SendErr(registers[s.RegVal]); err != nil {
+				catcherr = binstmt.NewError(stmt, err)
+				break
+			}
 
 		case *binstmt.BinISKIND:
 			v := reflect.ValueOf(registers).Index(s.Reg).Elem()
 			registers[s.Reg] = core.VMBool(v.Kind() == s.Kind)
 
+		case *binstmt.BinISTYPE:
+			ok, err := env.IsType(registers[s.Reg], s.Type)
+			if err != nil {
+				catcherr = binstmt.NewError(stmt, err)
+				break
+			}
+			registers[s.RegBool] = core.VMBool(ok)
+
 		case *binstmt.BinISSLICE:
 			_, ok := registers[s.Reg].(core.VMSlice)
 			registers[s.RegBool] = core.VMBool(ok)
@@ -937,7 +1368,11 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 			v := registers[s.Reg]
 			var x core.VMValuer
 			if vv, ok := v.(core.VMInt); ok {
-				x = core.VMInt(int64(vv) + 1)
+				var err error
+				if x, err = vv.EvalBinOp(core.ADD, core.VMInt(1)); err != nil {
+					catcherr = binstmt.NewError(stmt, err)
+					break
+				}
 			} else if vv, ok := v.(core.VMDecNum); ok {
 				x = vv.Add(core.VMDecNumOne)
 			}
@@ -947,7 +1382,11 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 			v := registers[s.Reg]
 			var x core.VMValuer
 			if vv, ok := v.(core.VMInt); ok {
-				x = core.VMInt(int64(vv) - 1)
+				var err error
+				if x, err = vv.EvalBinOp(core.SUB, core.VMInt(1)); err != nil {
+					catcherr = binstmt.NewError(stmt, err)
+					break
+				}
 			} else if vv, ok := v.(core.VMDecNum); ok {
 				x = vv.Add(core.VMDecNumNegOne)
 			}
@@ -957,6 +1396,15 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 			regs.PushTry(s.Reg, s.JumpTo)
 			registers[s.Reg] = nil // изначально ошибки нет
 
+		case *binstmt.BinWITH:
+			// ошибка в блоке перехватывается, чтобы закрыть ресурс, после чего выбрасывается повторно
+			regs.PushTry(s.Reg, s.JumpTo)
+			registers[s.Reg] = nil
+
+		case *binstmt.BinFINALLY:
+			// пока из блока не выходили досрочно
+			registers[s.RegAction] = core.VMInt(binstmt.FinallyNone)
+
 		case *binstmt.BinCATCH:
 			// получаем ошибку, и если ее нет, переходим на метку, иначе, выполняем дальше
 			nerr := registers[s.Reg]
@@ -980,6 +1428,9 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 				registers[s.Reg] = vv.Slice()
 			case core.VMChan:
 				registers[s.RegIter] = nil
+				if core.IsGenerator(vv) {
+					regs.PushGen(s.ContinueLabel, vv)
+				}
 			default:
 				catcherr = binstmt.NewStringError(stmt, "Не является коллекцией или каналом")
 				goto catching
@@ -1003,12 +1454,17 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 					continue
 				}
 			case core.VMChan:
+				// значения читаются из канала, пока он не будет закрыт
 				iv, ok := vv.Recv()
+				if err := core.SenderError(iv); err != nil {
+					catcherr = binstmt.NewError(stmt, err)
+					goto catching
+				}
 				if !ok {
-					registers[s.RegVal] = core.VMNil
-				} else {
-					registers[s.RegVal] = iv
+					idx = regs.Labels[s.JumpTo]
+					continue
 				}
+				registers[s.RegVal] = iv
 
 			default:
 				catcherr = binstmt.NewStringError(stmt, "Не является коллекцией или каналом")
@@ -1020,6 +1476,10 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 				regs.PopContinue()
 				regs.PopBreak()
 			}
+			// при выходе из цикла по Прервать генератор еще работает и ждет получателя
+			if len(regs.ForGens) > 0 {
+				regs.CloseGens(s.ContinueLabel)
+			}
 
 		case *binstmt.BinFORNUM:
 			if _, ok := registers[s.RegFrom].(core.VMInt); ok {
@@ -1067,9 +1527,19 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 			regs.PushContinue(s.ContinueLabel)
 
 		case *binstmt.BinTHROW:
-			catcherr = binstmt.NewStringError(stmt, fmt.Sprint(registers[s.Reg]))
+			catcherr = &binstmt.Error{Message: fmt.Sprint(registers[s.Reg]), Pos: stmt.Position(), Thrown: registers[s.Reg]}
 			break
 
+		case *binstmt.BinRETHROW:
+			if registers[s.Reg] != nil {
+				// ошибка выбрасывается как есть, с исходной позицией
+				if regs.CaughtErr != nil {
+					catcherr = regs.CaughtErr
+				} else {
+					catcherr = binstmt.NewStringError(stmt, fmt.Sprint(registers[s.Reg]))
+				}
+			}
+
 		case *binstmt.BinMODULE:
 			// модуль регистрируется в глобальном контексте
 			newenv := env.NewModule(names.UniqueNames.Get(s.Name))
@@ -1093,9 +1563,9 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 			return nil, binstmt.BreakError
 
 		case *binstmt.BinCONTINUE:
-			label := regs.PopContinue()
+			// цикл продолжается, поэтому его метки остаются в стеке
+			label := regs.TopContinue()
 			if label != -1 {
-				regs.PopBreak()
 				idx = regs.Labels[label]
 				continue
 			}
@@ -1109,6 +1579,10 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 				break
 			}
 			v, ok, notready := ch.TryRecv()
+			if err := core.SenderError(v); err != nil {
+				catcherr = binstmt.NewError(stmt, err)
+				break
+			}
 			if !ok {
 				registers[s.RegVal] = core.VMNil
 				registers[s.RegOk] = core.VMBool(ok)
@@ -1142,23 +1616,25 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 		if catcherr != nil {
 			nerr := binstmt.NewError(stmt, catcherr)
 			catcherr = nil
+			// отмена исполнения и исчерпание лимита инструкций не перехватываются в Попытка
+			if nerr == binstmt.BudgetError || errors.Is(nerr, binstmt.CancelError) {
+				return nil, nerr
+			}
 			// учитываем стек обработки ошибок
 			if regs.TopTryLabel() == -1 {
 				return nil, nerr
 			} else {
-				env.DefineS("описаниеошибки", func(s string) core.VMFunc {
-					return func(args core.VMSlice, rets *core.VMSlice, envout *(*core.Env)) error {
-						*envout = env
-						if len(args) != 0 {
-							return errors.New("Данная функция не требует параметров")
-						}
-						rets.Append(core.VMString(s))
-						return nil
-					}
-				}(nerr.Error()))
-
+				// функции, доступные в блоке Исключение
+				defineErrorFunc(env, "описаниеошибки", core.VMString(nerr.Error()))
+				info, details := errorInfo(nerr)
+				defineErrorFunc(env, "значениеошибки", info)
+				defineErrorFunc(env, "информацияобошибке", details)
+
+				// циклы внутри попытки прерваны ошибкой
+				regs.CloseTryGens()
 				r, idxl := regs.PopTry()
 				registers[r] = core.VMString(nerr.Error())
+				regs.CaughtErr = nerr
 				idx = regs.Labels[idxl] // переходим в catch блок, функция с описанием ошибки определена
 				continue
 			}
@@ -0,0 +1,4078 @@
+package bincode
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shinanca/gonec/bincode/binstmt"
+	"github.com/shinanca/gonec/core"
+	"github.com/shinanca/gonec/names"
+)
+
+// runScript компилирует и исполняет код, возвращая все, что было выведено через Сообщить
+func runScript(src string) (string, error) {
+	return runScriptEnv(src, core.NewEnv())
+}
+
+func runScriptEnv(src string, env *core.Env) (string, error) {
+	_, bins, err := ParseSrc(src)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	env.SetStdOut(&out)
+	_, err = Run(bins, env)
+	return out.String(), err
+}
+
+type scriptTest struct {
+	name    string
+	src     string
+	want    string
+	wantErr string
+}
+
+func runScriptTests(t *testing.T, tests []scriptTest) {
+	runScriptTestsEnv(t, core.NewEnv, tests)
+}
+
+// runScriptTestsEnv исполняет каждый тест в новом окружении, созданном newEnv
+func runScriptTestsEnv(t *testing.T, newEnv func() *core.Env, tests []scriptTest) {
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := runScriptEnv(tt.src, newEnv())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ошибка = %v, ожидалась %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ошибка исполнения: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("вывод = %q, ожидался %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStructType(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "создание и поля",
+			src: `структура Точка { Х, У }
+т = новый Точка
+т.Х = 1
+т.у = 2
+сообщить(т.х, т.У)
+сообщить(т)`,
+			want: "1 2\nТочка{Х: 1, У: 2}\n",
+		},
+		{
+			name: "поля не заданы",
+			src: `структура Точка {
+	Х,
+	У
+}
+т = новый Точка
+сообщить(т.Х)`,
+			want: "Неопределено\n",
+		},
+		{
+			name: "ссылочная семантика",
+			src: `структура Точка { Х, У }
+а = новый Точка
+б = а
+б.Х = 5
+сообщить(а.Х)`,
+			want: "5\n",
+		},
+		{
+			name: "чтение необъявленного поля",
+			src: `структура Точка { Х, У }
+т = новый Точка
+сообщить(т.Я)`,
+			wantErr: "Поле или метод 'Я' не объявлены в структуре 'Точка'",
+		},
+		{
+			name: "запись необъявленного поля",
+			src: `структура Точка { Х, У }
+т = новый Точка
+т.З = 1`,
+			wantErr: "Поле 'З' не объявлено в структуре 'Точка'",
+		},
+		{
+			name:    "повторное поле",
+			src:     `структура Точка { Х, х }`,
+			wantErr: "объявлено в структуре повторно",
+		},
+	})
+}
+
+func TestStructMethods(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "метод читает поля получателя",
+			src: `структура Точка { Х, У }
+функция (т Точка) Показать()
+	сообщить(т.Х, т.У)
+конецфункции
+т = новый Точка
+т.Х = 3
+т.У = 4
+т.Показать()`,
+			want: "3 4\n",
+		},
+		{
+			name: "метод возвращает значение",
+			src: `структура Точка { Х, У }
+функция (т Точка) Модуль()
+	возврат т.Х * т.Х + т.У * т.У
+конецфункции
+функция (т Точка) Сумма(к)
+	возврат (т.Х + т.У) * к
+конецфункции
+т = новый Точка
+т.Х = 3
+т.У = 4
+сообщить(т.Модуль(), т.Сумма(2))`,
+			want: "25 14\n",
+		},
+		{
+			name: "метод изменяет получателя",
+			src: `структура Счетчик { Н }
+функция (с Счетчик) Увеличить()
+	с.Н = с.Н + 1
+конецфункции
+с = новый Счетчик
+с.Н = 0
+с.Увеличить()
+с.Увеличить()
+сообщить(с.Н)`,
+			want: "2\n",
+		},
+		{
+			name: "обычные функции не затронуты",
+			src: `ф = функция(а, б)
+	возврат а + б
+конецфункции
+функция Модуль2(т)
+	возврат т
+конецфункции
+сообщить(ф(1, 2), Модуль2(3))`,
+			want: "3 3\n",
+		},
+		{
+			name: "метод не объявлен",
+			src: `структура Точка { Х, У }
+т = новый Точка
+т.Модуль()`,
+			wantErr: "Поле или метод 'Модуль' не объявлены в структуре 'Точка'",
+		},
+		{
+			name: "метод необъявленной структуры",
+			src: `функция (т Нет) Модуль()
+конецфункции`,
+			wantErr: "Структура 'Нет' не объявлена",
+		},
+	})
+}
+
+func TestXMLBuiltins(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "разбор и сериализация",
+			src: `д = ИзXML("<а к=\"1\"><б>текст</б></а>")
+сообщить(д.Имя, д.Атрибуты.к, д.Дочерние[0].Текст)
+сообщить(ВXML(д))`,
+			want: "а 1 текст\n<а к=\"1\"><б>текст</б></а>\n",
+		},
+		{
+			name: "ошибка разбора перехватывается",
+			src: `попытка
+	ИзXML("<а>")
+исключение
+	сообщить("ошибка")
+конецпопытки`,
+			want: "ошибка\n",
+		},
+	})
+}
+
+func TestHTTPRequestBuiltin(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+		w.Write(b)
+	}))
+	addr := srv.URL
+	srv.Close() // для проверки ошибки соединения
+
+	srv = httptest.NewServer(srv.Config.Handler)
+	defer srv.Close()
+
+	runScriptTests(t, []scriptTest{
+		{
+			name: "запрос с телом",
+			src: `о = HTTPЗапрос("POST", "` + srv.URL + `", {"Content-Type": "text/plain"}, "данные", 5)
+сообщить(о.КодСтатуса, о.Тело)`,
+			want: "201 данные\n",
+		},
+		{
+			name: "ошибка соединения перехватывается",
+			src: `попытка
+	HTTPЗапрос("GET", "` + addr + `", неопределено, "", ДлительностьСекунды)
+исключение
+	сообщить("ошибка")
+конецпопытки`,
+			want: "ошибка\n",
+		},
+	})
+}
+
+func TestEnvironmentBuiltins(t *testing.T) {
+	os.Setenv("GONEC_TEST_VAR", "значение")
+	defer os.Unsetenv("GONEC_TEST_VAR")
+	os.Unsetenv("GONEC_TEST_UNSET")
+
+	runScriptTests(t, []scriptTest{
+		{
+			name: "переменные среды",
+			src:  `сообщить(ПеременнаяСреды("GONEC_TEST_VAR"), ПеременнаяСреды("GONEC_TEST_UNSET") = null)`,
+			want: "значение true\n",
+		},
+		{
+			name: "аргументы не переданы",
+			src:  `сообщить(Длина(АргументыКоманднойСтроки()))`,
+			want: "0\n",
+		},
+	})
+
+	env := core.NewEnv()
+	env.DefineS("аргументызапуска", core.NewVMSliceFromStrings([]string{"-к", "файл.txt"}))
+	got, err := runScriptEnv(`а = АргументыКоманднойСтроки()
+сообщить(Длина(а), а[0], а[1])`, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "2 -к файл.txt\n" {
+		t.Errorf("вывод = %q", got)
+	}
+}
+
+func TestTypeSwitch(t *testing.T) {
+	src := `структура Точка { Х }
+функция Тип(з)
+	выбор по типу ч = з:
+	когда Строка:
+		возврат "строка " + ч
+	когда ЦелоеЧисло, Число:
+		возврат "число " + Строка(ч * 2)
+	когда Длительность:
+		возврат "длительность"
+	когда Массив:
+		возврат "массив " + Строка(Длина(ч))
+	когда Точка:
+		возврат "точка " + Строка(ч.Х)
+	когда Неопределено:
+		возврат "неопределено"
+	другое:
+		возврат "другое"
+	конецвыбора
+конецфункции
+т = новый Точка
+т.Х = 7
+сообщить(Тип("а"))
+сообщить(Тип(2))
+сообщить(Тип(1.5))
+сообщить(Тип(ДлительностьСекунды))
+сообщить(Тип([1, 2]))
+сообщить(Тип(т))
+сообщить(Тип(неопределено))
+сообщить(Тип(истина))
+выбор по типу {"а": 1}:
+когда Структура:
+	сообщить("структура")
+конецвыбора`
+	runScriptTests(t, []scriptTest{
+		{
+			name: "выбор по типу",
+			src:  src,
+			want: "строка а\nчисло 4\nчисло 3.0\nдлительность\nмассив 2\nточка 7\nнеопределено\nдругое\nструктура\n",
+		},
+		{
+			name:    "неизвестный тип",
+			src:     "выбор по типу 1:\nкогда Нет:\nконецвыбора",
+			wantErr: "Тип неопределен",
+		},
+	})
+}
+
+func TestShortDeclaration(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "объявление",
+			src: `а := 5
+б, в := 1, "два"
+сообщить(а, б, в)`,
+			want: "5 1 два\n",
+		},
+		{
+			name: "скрытие внешней переменной",
+			src: `а := 1
+ф = функция()
+	сообщить(а)
+	а := 2
+	сообщить(а)
+конецфункции
+ф()
+сообщить(а)`,
+			want: "1\n2\n1\n",
+		},
+		{
+			name: "скрытие переменной, объявленной глобальной",
+			src: `а = 1
+б = 1
+функция Ф()
+	глобальная а, б
+	а := 2
+	б = 2
+	сообщить(а, б)
+конецфункции
+Ф()
+сообщить(а, б)`,
+			want: "2 2\n1 2\n",
+		},
+		{
+			name: "повторное объявление допустимо",
+			src: `для н = 1 по 2 цикл
+	а := н
+конеццикла
+а := а * 10
+сообщить(а)`,
+			want: "20\n",
+		},
+		{
+			name:    "объявление элемента",
+			src:     `м := {}` + "\n" + `м["к"] := 1`,
+			wantErr: "Оператором := можно объявлять только переменные",
+		},
+	})
+}
+
+func TestIncDecStatements(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "постфиксная форма",
+			src: `н = 1
+н++
+н++
+н--
+сообщить(н)`,
+			want: "2\n",
+		},
+		{
+			name: "префиксная форма",
+			src: `н = 1
+++н
+++н
+--н
+сообщить(н)`,
+			want: "2\n",
+		},
+		{
+			name: "элемент структуры",
+			src: `м = {"к": 1}
+м["к"]++
+++м["к"]
+м["к"]--
+сообщить(м["к"])`,
+			want: "2\n",
+		},
+		{
+			name: "значение выражения",
+			src: `н = 1
+а = н++
+б = ++н
+сообщить(а, б, н)`,
+			want: "1 3 3\n",
+		},
+		{
+			name:    "инкремент константы",
+			src:     `1++`,
+			wantErr: "Инкремент применим только к переменным",
+		},
+	})
+}
+
+func TestCompoundAssignElements(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "индекс вычисляется однократно",
+			src: `вызовы = [0]
+функция Ключ()
+	вызовы[0] = вызовы[0] + 1
+	возврат "к"
+конецфункции
+м = {"к": 1}
+м[Ключ()] += 1
+м[Ключ()]++
+сообщить(м["к"], вызовы[0])`,
+			want: "3 2\n",
+		},
+		{
+			name: "элемент массива",
+			src: `а = [1, 2, 3]
+а[1] *= 10
+а[-1]--
+сообщить(а)`,
+			want: "[1,20,2]\n",
+		},
+		{
+			name: "поле объекта",
+			src: `структура Счетчик { Н }
+о = новый Счетчик
+о.Н = 1
+о.Н++
+о.Н += 5
+сообщить(о.Н, о.Н++, о.Н)`,
+			want: "7 7 8\n",
+		},
+	})
+}
+
+func TestBreakOutsideLoop(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "внутри цикла",
+			src: `для н = 1 по 5 цикл
+	если н = 2 тогда
+		продолжить
+	конецесли
+	если н = 4 тогда
+		прервать
+	конецесли
+	сообщить(н)
+конеццикла
+для каждого з из [1, 2] цикл
+	ф = функция()
+		возврат з
+	конецфункции
+	прервать
+конеццикла
+сообщить(з)`,
+			want: "1\n3\n1\n",
+		},
+		{
+			name:    "вне цикла",
+			src:     `прервать`,
+			wantErr: "Оператор Прервать может использоваться только внутри цикла",
+		},
+		{
+			name: "после цикла",
+			src: `пока ложь цикл
+конеццикла
+продолжить`,
+			wantErr: "Оператор Продолжить может использоваться только внутри цикла",
+		},
+		{
+			name: "в функции внутри цикла",
+			src: `пока истина цикл
+	ф = функция()
+		прервать
+	конецфункции
+конеццикла`,
+			wantErr: "Оператор Прервать может использоваться только внутри цикла",
+		},
+	})
+}
+
+func TestReturnOutsideFunction(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "внутри функции",
+			src: `функция Знак(а)
+	если а < 0 тогда
+		возврат -1
+	конецесли
+	возврат 1
+конецфункции
+сообщить(Знак(-5), Знак(5))`,
+			want: "-1 1\n",
+		},
+		{
+			name:    "на верхнем уровне",
+			src:     `возврат 1`,
+			wantErr: "Оператор Возврат может использоваться только внутри функции",
+		},
+		{
+			name: "после функции",
+			src: `функция Ф()
+	возврат 1
+конецфункции
+если истина тогда
+	возврат
+конецесли`,
+			wantErr: "Оператор Возврат может использоваться только внутри функции",
+		},
+	})
+}
+
+func TestMapFilterBuiltins(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "квадраты",
+			src: `сообщить(Преобразовать([1, 2, 3], функция(х)
+	возврат х * х
+конецфункции))`,
+			want: "[1,4,9]\n",
+		},
+		{
+			name: "четные",
+			src: `функция Четное(х)
+	возврат х % 2 = 0
+конецфункции
+сообщить(Отфильтровать([1, 2, 3, 4], Четное))`,
+			want: "[2,4]\n",
+		},
+		{
+			name: "пустой массив",
+			src: `функция Ф(х)
+	возврат истина
+конецфункции
+сообщить(Длина(Преобразовать([], Ф)), Длина(Отфильтровать([], Ф)))`,
+			want: "0 0\n",
+		},
+		{
+			name:    "не функция",
+			src:     `Преобразовать([1], 1)`,
+			wantErr: "Требуется значение типа Функция",
+		},
+	})
+}
+
+func TestFoldBuiltin(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "сумма",
+			src: `функция Сложить(а, х)
+	возврат а + х
+конецфункции
+сообщить(Свернуть([1, 2, 3, 4], 0, Сложить))`,
+			want: "10\n",
+		},
+		{
+			name: "строки",
+			src: `сообщить(Свернуть(["а", "б", "в"], ">", функция(а, х)
+	возврат а + х
+конецфункции))`,
+			want: ">абв\n",
+		},
+		{
+			name: "пустой массив",
+			src: `функция Ф(а, х)
+	возврат а + х
+конецфункции
+сообщить(Свернуть([], 5, Ф))`,
+			want: "5\n",
+		},
+		{
+			name: "один элемент",
+			src: `функция Ф(а, х)
+	возврат а * 10 + х
+конецфункции
+сообщить(Свернуть([7], 1, Ф))`,
+			want: "17\n",
+		},
+	})
+}
+
+func TestGroupByBuiltin(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "четность",
+			src: `функция Четность(ч)
+	если ч % 2 = 0 тогда
+		возврат "чет"
+	конецесли
+	возврат "нечет"
+конецфункции
+сообщить(Сгруппировать([1, 2, 3, 4, 5], Четность))`,
+			want: `{"нечет":[1,3,5],"чет":[2,4]}` + "\n",
+		},
+		{
+			name: "ключ - строка",
+			src: `г = Сгруппировать(["арбуз", "банан", "апельсин", "вишня", "брусника"], функция(с)
+	если Длина(с) > 5 тогда
+		возврат "длинные"
+	конецесли
+	возврат "короткие"
+конецфункции)
+сообщить(г["короткие"], г["длинные"], Длина(г))`,
+			want: `["арбуз","банан","вишня"] ["апельсин","брусника"] 2` + "\n",
+		},
+		{
+			name: "ключ приводится к строке",
+			src:  `сообщить(Сгруппировать([1, 2, 3, 4], функция(ч) возврат ч % 2 конецфункции))`,
+			want: `{"0":[2,4],"1":[1,3]}` + "\n",
+		},
+		{
+			name: "пустой массив",
+			src:  `сообщить(Сгруппировать([], функция(ч) возврат ч конецфункции))`,
+			want: "{}\n",
+		},
+		{
+			name:    "не функция",
+			src:     `Сгруппировать([1], 1)`,
+			wantErr: "Требуется значение типа Функция",
+		},
+	})
+}
+
+func TestTakeDropBuiltins(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "в пределах массива",
+			src: `а = [1, 2, 3, 4, 5]
+сообщить(Взять(а, 2), Отбросить(а, 2), Взять(а, 0), Отбросить(а, 0), а)`,
+			want: "[1,2] [3,4,5] [] [1,2,3,4,5] [1,2,3,4,5]\n",
+		},
+		{
+			name: "за пределами массива",
+			src:  `сообщить(Взять([1, 2], 5), Отбросить([1, 2], 5), Взять([], 1))`,
+			want: "[1,2] [] []\n",
+		},
+		{
+			name: "по предикату до первой лжи",
+			src: `функция Меньше3(х)
+	возврат х < 3
+конецфункции
+а = [1, 2, 3, 1, 2]
+сообщить(ВзятьПока(а, Меньше3), ОтброситьПока(а, Меньше3))`,
+			want: "[1,2] [3,1,2]\n",
+		},
+		{
+			name: "предикат всегда истинен или ложен",
+			src: `функция Да(х)
+	возврат истина
+конецфункции
+функция Нет(х)
+	возврат ложь
+конецфункции
+сообщить(ВзятьПока([1, 2], Да), ОтброситьПока([1, 2], Да), ВзятьПока([1, 2], Нет), ОтброситьПока([1, 2], Нет))`,
+			want: "[1,2] [] [] [1,2]\n",
+		},
+		{
+			name:    "отрицательное количество",
+			src:     `Взять([1], -1)`,
+			wantErr: "Количество не может быть отрицательным",
+		},
+		{
+			name:    "предикат не булев",
+			src:     `ВзятьПока([1], функция(х) возврат {"х": х} конецфункции)`,
+			wantErr: "Требуется значение типа Булево",
+		},
+	})
+}
+
+func TestRetryBuiltin(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "успех с первой попытки",
+			src: `с = {"попытки": 0}
+р = Повторять(3, функция()
+	с.попытки = с.попытки + 1
+	возврат "готово"
+конецфункции)
+сообщить(р, с.попытки)`,
+			want: "готово 1\n",
+		},
+		{
+			name: "успех после сбоя",
+			src: `с = {"попытки": 0}
+р = Повторять(5, функция()
+	с.попытки = с.попытки + 1
+	если с.попытки < 3 тогда
+		вызватьисключение("сбой")
+	конецесли
+	возврат с.попытки * 10
+конецфункции, ДлительностьМиллисекунды)
+сообщить(р, с.попытки)`,
+			want: "30 3\n",
+		},
+		{
+			name: "исчерпание попыток",
+			src: `с = {"попытки": 0}
+попытка
+	Повторять(3, функция()
+		с.попытки = с.попытки + 1
+		вызватьисключение("сбой " + Строка(с.попытки))
+	конецфункции)
+исключение
+	сообщить(с.попытки, СтрСодержит(ОписаниеОшибки(), "сбой 3"))
+конецпопытки`,
+			want: "3 true\n",
+		},
+		{
+			name:    "неположительное количество",
+			src:     `Повторять(0, функция() возврат 1 конецфункции)`,
+			wantErr: "Количество должно быть больше нуля",
+		},
+	})
+}
+
+func TestMemoizeBuiltin(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "один вызов на аргумент",
+			src: `вызовы = {"н": 0}
+функция Квадрат(х)
+	вызовы["н"] = вызовы["н"] + 1
+	возврат х * х
+конецфункции
+к = Запомнить(Квадрат)
+сообщить(к(3), к(4), к(3), к(3), к(4), вызовы["н"])`,
+			want: "9 16 9 9 16 2\n",
+		},
+		{
+			name: "несколько аргументов",
+			src: `вызовы = {"н": 0}
+с = Запомнить(функция(а, б)
+	вызовы["н"] = вызовы["н"] + 1
+	возврат а + б
+конецфункции)
+сообщить(с(1, 2), с(2, 1), с(1, 2), с("а", "б"), с("а", "б"), вызовы["н"])`,
+			want: "3 3 3 аб аб 3\n",
+		},
+		{
+			name: "массивы и структуры как аргументы",
+			src: `вызовы = {"н": 0}
+д = Запомнить(функция(м)
+	вызовы["н"] = вызовы["н"] + 1
+	возврат Длина(м)
+конецфункции)
+сообщить(д({"а": 1, "б": [2]}), д({"б": [2], "а": 1}), д([1, 2]), д([2, 1]), вызовы["н"])`,
+			want: "2 2 2 2 3\n",
+		},
+		{
+			name: "ошибки не запоминаются",
+			src: `вызовы = {"н": 0}
+ф = Запомнить(функция(х)
+	вызовы["н"] = вызовы["н"] + 1
+	если вызовы["н"] = 1 тогда
+		вызватьисключение("сбой")
+	конецесли
+	возврат х
+конецфункции)
+попытка
+	ф(1)
+исключение
+	сообщить("исключение")
+конецпопытки
+сообщить(ф(1), ф(1), вызовы["н"])`,
+			want: "исключение\n1 1 2\n",
+		},
+		{
+			name: "изменение результата не меняет запомненный",
+			src: `п = Запомнить(функция(н)
+	возврат {"н": н, "м": [н]}
+конецфункции)
+а = п(1)
+а["н"] = 2
+а["м"][0] = 2
+б = п(1)
+б["м"] += [3]
+сообщить(п(1))`,
+			want: `{"м":[1],"н":1}` + "\n",
+		},
+	})
+}
+
+func TestPartialBuiltin(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "один аргумент",
+			src: `функция Сложить(а, б)
+	возврат а + б
+конецфункции
+добавить5 = ЧастичноеПрименение(Сложить, 5)
+сообщить(добавить5(1), добавить5(10), Преобразовать([1, 2, 3], добавить5))`,
+			want: "6 15 [6,7,8]\n",
+		},
+		{
+			name: "несколько аргументов",
+			src: `функция Строка3(а, б, в)
+	возврат а + "-" + б + "-" + в
+конецфункции
+ф = ЧастичноеПрименение(Строка3, "x", "y")
+сообщить(ф("z"), ЧастичноеПрименение(Строка3, "a", "b", "c")())`,
+			want: "x-y-z a-b-c\n",
+		},
+		{
+			name: "применение к результату применения",
+			src: `функция Объем(а, б, в)
+	возврат а * б * в
+конецфункции
+ф = ЧастичноеПрименение(ЧастичноеПрименение(Объем, 2), 3)
+сообщить(ф(4), ЧастичноеПрименение(Макс)(3, 8))`,
+			want: "24 8\n",
+		},
+		{
+			name:    "неверное количество аргументов",
+			src:     `ЧастичноеПрименение(функция(а, б) возврат а конецфункции, 1, 2)(3)`,
+			wantErr: "Неверное количество аргументов",
+		},
+		{
+			name:    "не функция",
+			src:     `ЧастичноеПрименение(1, 2)`,
+			wantErr: "Требуется значение типа Функция",
+		},
+	})
+}
+
+func TestComposeBuiltin(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "две функции",
+			src: `функция Удвоить(х)
+	возврат х * 2
+конецфункции
+функция Прибавить1(х)
+	возврат х + 1
+конецфункции
+ф = Композиция(Удвоить, Прибавить1)
+сообщить(ф(3), Преобразовать([1, 2], ф))`,
+			want: "8 [4,6]\n",
+		},
+		{
+			name: "порядок применения справа налево",
+			src: `функция А(с)
+	возврат с + "а"
+конецфункции
+функция Б(с)
+	возврат с + "б"
+конецфункции
+функция В(с)
+	возврат с + "в"
+конецфункции
+сообщить(Композиция(А, Б, В)(">"), Композиция(В, Б, А)(">"))`,
+			want: ">вба >абв\n",
+		},
+		{
+			name: "одна функция",
+			src: `функция Ф(х)
+	возврат х * 10
+конецфункции
+сообщить(Композиция(Ф)(5), Композиция(Макс)(2, 7))`,
+			want: "50 7\n",
+		},
+		{
+			name: "правая функция получает все аргументы",
+			src:  `сообщить(Композиция(функция(х) возврат х * х конецфункции, Макс)(3, 4))`,
+			want: "16\n",
+		},
+		{
+			name:    "без функций",
+			src:     `Композиция()`,
+			wantErr: "Отсутствуют аргументы",
+		},
+	})
+}
+
+func TestThrottleBuiltin(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "частые вызовы отбрасываются",
+			src: `с = {"вызовы": 0}
+ф = ОграничитьЧастоту(функция(х)
+	с["вызовы"] = с["вызовы"] + 1
+	возврат х
+конецфункции, ДлительностьЧаса)
+р = []
+для н = 1 по 10 цикл
+	р += [ф(н)]
+конеццикла
+сообщить(с["вызовы"], р[0], ТипЗнч(р[1]) = ТипЗнч(Неопределено), ТипЗнч(р[9]) = ТипЗнч(Неопределено))`,
+			want: "1 1 true true\n",
+		},
+		{
+			name:    "не длительность",
+			src:     `ОграничитьЧастоту(функция() конецфункции, "а")`,
+			wantErr: "Требуется значение типа Длительность",
+		},
+	})
+}
+
+func TestWithStatement(t *testing.T) {
+	res := `структура Ресурс { Имя }
+функция (р Ресурс) Закрыть()
+	сообщить("закрыт", р.Имя)
+конецфункции
+функция Открыть(имя)
+	р = новый Ресурс
+	р.Имя = имя
+	возврат р
+конецфункции
+`
+	runScriptTests(t, []scriptTest{
+		{
+			name: "нормальный выход",
+			src: res + `используя р = Открыть("а")
+	сообщить("работа", р.Имя)
+конециспользования
+сообщить("после")`,
+			want: "работа а\nзакрыт а\nпосле\n",
+		},
+		{
+			name: "исключение",
+			src: res + `попытка
+	используя р = Открыть("б")
+		вызватьисключение "сбой"
+		сообщить("не выполняется")
+	конециспользования
+исключение
+	сообщить("перехвачено", СтрСодержит(ОписаниеОшибки(), "сбой"))
+конецпопытки`,
+			want: "закрыт б\nперехвачено true\n",
+		},
+		{
+			name: "возврат из блока",
+			src: res + `функция Прочитать()
+	используя р = Открыть("в")
+		используя р2 = Открыть("г")
+			возврат р.Имя + р2.Имя
+		конециспользования
+	конециспользования
+	возврат "не выполняется"
+конецфункции
+сообщить(Прочитать())`,
+			want: "закрыт г\nзакрыт в\nвг\n",
+		},
+		{
+			name: "прерывание цикла",
+			src: res + `для н = 1 по 3 цикл
+	используя р = Открыть(Строка(н))
+		если н = 2 тогда
+			прервать
+		конецесли
+	конециспользования
+конеццикла`,
+			want: "закрыт 1\nзакрыт 2\n",
+		},
+		{
+			name:    "нет метода Закрыть",
+			src:     "используя р = [1]\nконециспользования",
+			wantErr: "Нет метода с таким именем",
+		},
+	})
+}
+
+func TestDecNumScaleBuiltins(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "деление с масштабом",
+			src:  `сообщить(Разделить(1, 3, 4), Разделить(10.0, 4, 0), Масштаб(Разделить(1, 3, 4)))`,
+			want: "0.3333 3 4\n",
+		},
+		{
+			name: "масштаб операций",
+			src:  `сообщить(Масштаб(1.25 * 1.5), Масштаб(1.5 + 2.25), Масштаб(7))`,
+			want: "3 2 0\n",
+		},
+	})
+}
+
+func TestIntOverflow(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "граница",
+			src:  `сообщить(9223372036854775806 + 1)`,
+			want: "9223372036854775807\n",
+		},
+		{
+			name:    "переполнение константы",
+			src:     `а = 9223372036854775807 + 1`,
+			wantErr: "Переполнение целого числа",
+		},
+		{
+			name: "переполнение перехватывается",
+			src: `а = 9223372036854775807
+попытка
+	а++
+исключение
+	сообщить("переполнение")
+конецпопытки
+сообщить(а)`,
+			want: "переполнение\n9223372036854775807\n",
+		},
+	})
+}
+
+func TestBigIntBuiltin(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "факториал 100",
+			src: `ф = БольшоеЦелое(1)
+для н = 1 по 100 цикл
+	ф = ф * н
+конеццикла
+сообщить(ф)`,
+			want: "93326215443944152681699238856266700490715968264381621468592963895217599993229915608941463976156518286253697920827223758251185210916864000000000000000000000000\n",
+		},
+		{
+			name: "смешанная арифметика",
+			src: `б = БольшоеЦелое("9223372036854775807")
+сообщить(б + 1, 1 - б, (б + б) % 10, б / 2)`,
+			want: "9223372036854775808 -9223372036854775806 4 4611686018427387903\n",
+		},
+		{
+			name: "сравнение",
+			src:  `сообщить(БольшоеЦелое("42") = 42, 42 = БольшоеЦелое(42), БольшоеЦелое("43") > 42, БольшоеЦелое(5) <> 5)`,
+			want: "true true true false\n",
+		},
+	})
+}
+
+func TestFormatDecNum(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "точное значение",
+			src:  `сообщить(Формат("%.2f", 0.1 + 0.2), Формат("%.2f", 98765432109876543210.005), Формат("[%9.3f]", -12.5))`,
+			want: "0.30 98765432109876543210.01 [  -12.500]\n",
+		},
+	})
+}
+
+func TestEqualFoldBuiltin(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "разный регистр",
+			src:  `сообщить(РавноБезРегистра("ПривЕт, Ёжик", "привет, ёЖИК"), РавноБезРегистра("Hello", "hELLO"))`,
+			want: "true true\n",
+		},
+		{
+			name: "разные строки",
+			src:  `сообщить(РавноБезРегистра("мир", "мир!"), РавноБезРегистра("е", "ё"), РавноБезРегистра("", ""))`,
+			want: "false false true\n",
+		},
+	})
+}
+
+func TestStrPrefixSuffixBuiltins(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "совпадения",
+			src:  `сообщить(НачинаетсяС("Ёжик в тумане", "Ёжик"), ЗаканчиваетсяНа("Ёжик в тумане", "тумане"), СодержитСтр("Ёжик в тумане", "к в т"))`,
+			want: "true true true\n",
+		},
+		{
+			name: "несовпадения",
+			src:  `сообщить(НачинаетсяС("Ёжик", "ёжик"), ЗаканчиваетсяНа("Ёжик", "Ёж"), СодержитСтр("Ёжик", "жук"))`,
+			want: "false false false\n",
+		},
+		{
+			name: "пустые строки",
+			src:  `сообщить(НачинаетсяС("а", ""), ЗаканчиваетсяНа("", ""), СодержитСтр("", "а"))`,
+			want: "true true false\n",
+		},
+	})
+}
+
+func TestPadBuiltins(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "слева и справа",
+			src:  `сообщить("[" + ДополнитьСлева("ёж", 4, "*") + "|" + ДополнитьСправа("ёж", 4, "—") + "]")`,
+			want: "[**ёж|ёж——]\n",
+		},
+		{
+			name: "строка длиннее",
+			src:  `сообщить(ДополнитьСлева("Привет", 3, " "))`,
+			want: "Привет\n",
+		},
+		{
+			name:    "символ заполнения не один",
+			src:     `ДополнитьСправа("а", 3, "ab")`,
+			wantErr: "Требуется строка из одного символа",
+		},
+	})
+}
+
+func TestRepeatBuiltin(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "строка",
+			src:  `сообщить(Повторить("ой", 3))`,
+			want: "ойойой\n",
+		},
+		{
+			name: "массив",
+			src:  `а = [1, "б"]; б = Повторить(а, 2); сообщить(б, а)`,
+			want: "[1,\"б\",1,\"б\"] [1,\"б\"]\n",
+		},
+		{
+			name: "ноль повторений",
+			src:  `сообщить("[" + Повторить("ой", 0) + "]", Длина(Повторить([1, 2], 0)))`,
+			want: "[] 0\n",
+		},
+		{
+			name:    "отрицательное количество",
+			src:     `Повторить("ой", -1)`,
+			wantErr: "Количество не может быть отрицательным",
+		},
+		{
+			name: "слишком большой результат перехватывается",
+			src: `попытка
+	Повторить([1, 2], 9223372036854775807)
+исключение
+	сообщить(ОписаниеОшибки())
+конецпопытки
+попытка
+	Повторить("ой", 100000000)
+исключение
+	сообщить(ОписаниеОшибки())
+конецпопытки`,
+			want: "[2:2] Слишком большой размер результата\n[7:2] Слишком большой размер результата\n",
+		},
+	})
+}
+
+func TestUnreachableCode(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{
+			name: "после возврата",
+			src: `функция Ф()
+	возврат 1
+	сообщить("никогда")
+	сообщить("и это тоже")
+конецфункции`,
+			want: []string{"[3:2] Недостижимый код"},
+		},
+		{
+			name: "обе ветки возвращают",
+			src: `функция Знак(а)
+	если а < 0 тогда
+		возврат -1
+	иначе
+		вызватьисключение "ноль или больше"
+	конецесли
+	возврат 0
+конецфункции`,
+			want: []string{"[7:2] Недостижимый код"},
+		},
+		{
+			name: "после прерывания цикла",
+			src: `для н = 1 по 3 цикл
+	прервать
+	сообщить(н)
+конеццикла`,
+			want: []string{"[3:2] Недостижимый код"},
+		},
+		{
+			name: "достижимый код",
+			src: `функция Знак(а)
+	если а < 0 тогда
+		возврат -1
+	конецесли
+	возврат 1
+конецфункции
+для н = 1 по 3 цикл
+	если н = 2 тогда
+		продолжить
+	конецесли
+	сообщить(Знак(н))
+конеццикла`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prs, _, err := ParseSrc(tt.src)
+			if err != nil {
+				t.Fatalf("ошибка компиляции: %v", err)
+			}
+			errs := prs.Unreachable()
+			if len(errs) != len(tt.want) {
+				t.Fatalf("найдено %v, ожидалось %v", errs, tt.want)
+			}
+			for i, e := range errs {
+				if !strings.Contains(e.Error(), tt.want[i]) {
+					t.Errorf("ошибка %q, ожидалось %q", e, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCatchErrorValue(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "ошибка выполнения",
+			src: `попытка
+	м = [1, 2]
+	сообщить(м[5])
+исключение
+	о = ЗначениеОшибки()
+	сообщить(о["Тип"], о["Строка"], о["Колонка"])
+	сообщить(о["Сообщение"])
+конецпопытки`,
+			want: "ОшибкаВыполнения 3 11\nИндекс за пределами границ\n",
+		},
+		{
+			name: "исключение со структурой",
+			src: `попытка
+	вызватьисключение {"код": 42, "текст": "нет данных"}
+исключение
+	о = ЗначениеОшибки()
+	сообщить(о["код"], о["текст"], Длина(о))
+конецпопытки`,
+			want: "42 нет данных 2\n",
+		},
+		{
+			name: "исключение из функции",
+			src: `функция Ф()
+	вызватьисключение "ошибка в функции"
+конецфункции
+попытка
+	Ф()
+исключение
+	сообщить(ЗначениеОшибки(), ОписаниеОшибки())
+конецпопытки`,
+			want: "ошибка в функции [2:2] ошибка в функции\n",
+		},
+	})
+}
+
+func TestPowAssociativity(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "степень справа налево",
+			src:  `сообщить(2 ** 3 ** 2, 2 ** 9, (2 ** 3) ** 2)`,
+			want: "512 512 64\n",
+		},
+		{
+			name: "вычитание слева направо",
+			src:  `сообщить(2 - 3 - 1, 2 * 3 ** 2)`,
+			want: "-2 18\n",
+		},
+	})
+}
+
+func TestUnaryPlus(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "строка в число",
+			src:  `с = "5"; сообщить(+"5" + 1, +с * 2, +"2.5")`,
+			want: "6 10 2.5\n",
+		},
+		{
+			name: "число без изменений",
+			src:  `ч = 3.0; сообщить(+3.0, +ч, +-4)`,
+			want: "3.0 3.0 -4\n",
+		},
+		{
+			name: "не число",
+			src: `попытка
+	с = "абв"
+	сообщить(+с)
+исключение
+	сообщить("ошибка")
+конецпопытки`,
+			want: "ошибка\n",
+		},
+	})
+}
+
+func TestAssertBuiltin(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "условие истинно",
+			src:  `Утверждать(1 + 1 = 2, "арифметика"); сообщить("дальше")`,
+			want: "дальше\n",
+		},
+		{
+			name: "условие ложно",
+			src: `а = 1
+Утверждать(а > 1, "а должно быть больше 1")
+сообщить("не выполняется")`,
+			wantErr: "[2:1] а должно быть больше 1",
+		},
+		{
+			name: "перехват",
+			src: `попытка
+	Утверждать(ложь, "проверка не прошла")
+исключение
+	сообщить(ОписаниеОшибки())
+конецпопытки`,
+			want: "[2:2] проверка не прошла\n",
+		},
+	})
+}
+
+// kinds возвращает типы инструкций байткода по порядку
+func kinds(bin binstmt.BinCode) (ks []string) {
+	for _, s := range bin.Code {
+		ks = append(ks, strings.TrimPrefix(fmt.Sprintf("%T", s), "*binstmt.Bin"))
+	}
+	return
+}
+
+// count возвращает количество инструкций типа k
+func count(ks []string, k string) (n int) {
+	for _, s := range ks {
+		if s == k {
+			n++
+		}
+	}
+	return
+}
+
+func TestCompileNoFolding(t *testing.T) {
+	_, folded, err := ParseSrc(`а = 1 + 2`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks := kinds(folded)
+	if count(ks, "LOAD") != 1 || count(ks, "OPER") != 0 {
+		t.Errorf("со сверткой констант получено %v, ожидалась одна загрузка без операции", ks)
+	}
+
+	_, raw, err := ParseSrcWithOptions(`а = 1 + 2`, CompileOptions{NoFolding: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks = kinds(raw)
+	if count(ks, "LOAD") != 2 || count(ks, "OPER") != 1 {
+		t.Errorf("без свертки констант получено %v, ожидались две загрузки и операция", ks)
+	}
+
+	// код без свертки исполняется так же
+	var out bytes.Buffer
+	env := core.NewEnv()
+	env.SetStdOut(&out)
+	_, raw, err = ParseSrcWithOptions(`сообщить(1 + 2 * 3, -(4), "а" + "б")`, CompileOptions{NoFolding: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Run(raw, env); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "7 -4 аб\n" {
+		t.Errorf("вывод %q", out.String())
+	}
+}
+
+func TestThrowPosition(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "не перехваченное исключение",
+			src: `сообщить("начало")
+  вызватьисключение "ошибка"`,
+			wantErr: "[2:3] ошибка",
+		},
+		{
+			name: "исключение из функции",
+			src: `функция Ф()
+	вызватьисключение {"код": 1}
+конецфункции
+Ф()`,
+			wantErr: "[2:2] ",
+		},
+		{
+			name: "позиция и значение в обработчике",
+			src: `попытка
+	вызватьисключение {"код": 7}
+исключение
+	о = ИнформацияОбОшибке()
+	сообщить(о["Тип"], о["Строка"], о["Колонка"], о["Значение"]["код"], ЗначениеОшибки()["код"])
+конецпопытки`,
+			want: "Исключение 2 2 7 7\n",
+		},
+	})
+}
+
+func TestTimerBuiltin(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "истекло время ожидания",
+			src: `к = новый канал
+з = неопределено
+т = Таймер(ДлительностьМиллисекунды * 20)
+выбор:
+когда з <- к:
+	сообщить("значение", з)
+когда <-т:
+	сообщить("таймаут")
+конецвыбора`,
+			want: "таймаут\n",
+		},
+		{
+			name: "канал раньше таймера",
+			src: `к = новый канал(1)
+к <- 5
+з = неопределено
+т = Таймер(10)
+выбор:
+когда з <- к:
+	сообщить("значение", з)
+когда <-т:
+	сообщить("таймаут")
+конецвыбора`,
+			want: "значение 5\n",
+		},
+		{
+			name: "число секунд",
+			src: `т = Таймер(0.01)
+сообщить(ТипЗнч(<-т))`,
+			want: "дата\n",
+		},
+	})
+}
+
+func TestForEachChannel(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "канал заполняется в другой горутине",
+			src: `функция Заполнить(к, н)
+	для ч = 1 по н цикл
+		к <- ч * ч
+	конеццикла
+	к.Закрыть()
+конецфункции
+к = новый канал
+старт Заполнить(к, 4)
+сумма = 0
+для каждого з из к цикл
+	сумма = сумма + з
+	сообщить(з)
+конеццикла
+сообщить("сумма", сумма)`,
+			want: "1\n4\n9\n16\nсумма 30\n",
+		},
+		{
+			name: "закрытый пустой канал",
+			src: `к = новый канал(1)
+к.Закрыть()
+н = 0
+для каждого з из к цикл
+	н = н + 1
+конеццикла
+сообщить(н)`,
+			want: "0\n",
+		},
+		{
+			name: "прервать чтение",
+			src: `к = новый канал(3)
+к <- 1
+к <- 2
+к <- 3
+для каждого з из к цикл
+	если з = 2 тогда
+		прервать
+	конецесли
+	сообщить(з)
+конеццикла
+сообщить(<-к)`,
+			want: "1\n3\n",
+		},
+	})
+}
+
+func TestCloseChanBuiltin(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "оставшиеся значения после закрытия",
+			src: `к = новый канал(2)
+к <- 1
+к <- 2
+ЗакрытьКанал(к)
+сообщить(<-к, <-к)
+з, ок = к.Получить()
+сообщить(з, ок)
+сообщить(<-к)`,
+			want: "1 2\nНеопределено false\nНеопределено\n",
+		},
+		{
+			name: "отправка в закрытый канал",
+			src: `к = новый канал(1)
+ЗакрытьКанал(к)
+попытка
+	к <- 1
+исключение
+	сообщить(ОписаниеОшибки())
+конецпопытки`,
+			want: "[4:2] Канал закрыт\n",
+		},
+		{
+			name:    "повторное закрытие",
+			src:     "к = новый канал\nЗакрытьКанал(к)\nЗакрытьКанал(к)",
+			wantErr: "[3:1] Канал закрыт",
+		},
+		{
+			name:    "не канал",
+			src:     `ЗакрытьКанал([1])`,
+			wantErr: "Требуется значение типа Канал",
+		},
+	})
+}
+
+func TestPauseBuiltin(t *testing.T) {
+	start := time.Now()
+	out, err := runScript(`Пауза(ДлительностьМиллисекунды * 50)
+Пауза(0.02)
+сообщить("готово")`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "готово\n" {
+		t.Errorf("вывод %q", out)
+	}
+	if elapsed := time.Since(start); elapsed < 70*time.Millisecond || elapsed > 2*time.Second {
+		t.Errorf("пауза длилась %v, ожидалось около 70мс", elapsed)
+	}
+
+	runScriptTests(t, []scriptTest{
+		{
+			name: "другие горутины работают во время паузы",
+			src: `функция Работа(к)
+	для н = 1 по 3 цикл
+		к <- н
+	конеццикла
+конецфункции
+к = новый канал(3)
+старт Работа(к)
+Пауза(ДлительностьМиллисекунды * 50)
+з = неопределено
+выбор:
+когда з <- к:
+	сообщить("получено", з)
+другое:
+	сообщить("пусто")
+конецвыбора`,
+			want: "получено 1\n",
+		},
+		{
+			name:    "не длительность",
+			src:     `Пауза("долго")`,
+			wantErr: "Требуется значение типа Длительность",
+		},
+	})
+}
+
+func TestMapDestructuring(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "два ключа",
+			src: `к = {"x": 1, "y": "два"}
+{"x": а, "y": б} = к
+сообщить(а, б)`,
+			want: "1 два\n",
+		},
+		{
+			name: "отсутствующий ключ",
+			src: `а = 5
+{"нет": а} = {"x": 1}
+сообщить(а, а = неопределено)`,
+			want: "Неопределено true\n",
+		},
+		{
+			name: "вложенный шаблон",
+			src: `к = {"точка": {"x": 3, "y": 4}, "имя": "А"}
+{"имя": н, "точка": {"x": а, "y": б}} = к
+сообщить(н, а+б)`,
+			want: "А 7\n",
+		},
+		{
+			name: "элемент массива в шаблоне",
+			src: `м = [0, 0]
+{"x": м[1]} = {"x": 9}
+сообщить(м)`,
+			want: "[0,9]\n",
+		},
+		{
+			name:    "не переменная в шаблоне",
+			src:     "к = {\"x\": 9}\n{\"x\": 1, \"y\": а} = к",
+			wantErr: "В шаблоне структуры допустимы только",
+		},
+	})
+}
+
+func TestArrayDestructuring(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "голова и хвост",
+			src: `с = [1, 2, 3, 4]
+[голова, ...хвост] = с
+сообщить(голова, хвост)`,
+			want: "1 [2,3,4]\n",
+		},
+		{
+			name: "точное количество",
+			src: `[первый, второй, ...остаток] = [1, "два"]
+сообщить(первый, второй, остаток)`,
+			want: "1 два []\n",
+		},
+		{
+			name: "обмен и вложенный шаблон",
+			src: `[а, [б, в]] = [1, [2, 3]]
+[а, в] = [в, а]
+сообщить(а, б, в)`,
+			want: "3 2 1\n",
+		},
+		{
+			name:    "слайс короче шаблона",
+			src:     "[а, б, в] = [1, 2]",
+			wantErr: "Индекс за пределами границ",
+		},
+		{
+			name:    "остаток не массива",
+			src:     `[а, ...б] = "строка"`,
+			wantErr: "Требуется значение типа Массив",
+		},
+		{
+			name:    "остаток в выражении",
+			src:     "х = 1\nс = [х, ...х]",
+			wantErr: "Остаток массива допустим только в левой части присваивания",
+		},
+	})
+}
+
+func TestMaxMinBuiltins(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "числа",
+			src:  `сообщить(Макс(1, 2.5), Мин(1, 2.5), Макс(-3, -7))`,
+			want: "2.5 1 -3\n",
+		},
+		{
+			name: "строки",
+			src:  `сообщить(Макс("a", "b"), Мин("a", "b"), Мин("яблоко", "груша"))`,
+			want: "b a груша\n",
+		},
+		{
+			name: "даты",
+			src: `д1 = ТекущаяДата()
+д2 = д1 + ДлительностьЧаса
+сообщить(Макс(д1, д2) = д2, Мин(д2, д1) = д1)`,
+			want: "true true\n",
+		},
+		{
+			name: "длительности",
+			src:  `сообщить(Мин(ДлительностьЧаса, ДлительностьМинуты) = ДлительностьМинуты)`,
+			want: "true\n",
+		},
+		{
+			name:    "разные типы",
+			src:     `Макс("a", 1)`,
+			wantErr: "[1:1] Операция между значениями невозможна",
+		},
+	})
+}
+
+func TestTemplateBuiltin(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "подстановка",
+			src: `з = {"кто": "Мир", "сколько": 2}
+сообщить(Шаблон("{кто}: {сколько}, {{кто}}, {нет}", з))`,
+			want: "Мир: 2, {кто}, {нет}\n",
+		},
+		{
+			name:    "строгий режим",
+			src:     `Шаблон("{нет}", {"кто": 1}, истина)`,
+			wantErr: "[1:1] Нет значения для 'нет' в шаблоне",
+		},
+	})
+}
+
+func TestNumberParseBuiltin(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "запятая и разделители разрядов",
+			src:  `сообщить(Число("1 234,56", ",") + 0.44)`,
+			want: "1235.00\n",
+		},
+		{
+			name: "целое",
+			src:  `сообщить(Число("42"), ТипЗнч(Число("42")), ТипЗнч(Число("4,2", ",")))`,
+			want: "42 целоечисло число\n",
+		},
+		{
+			name: "неверная строка",
+			src: `попытка
+	Число("12,5 руб", ",")
+исключение
+	сообщить(ОписаниеОшибки())
+конецпопытки`,
+			want: "[2:7] Неверный формат числа\n",
+		},
+		{
+			name:    "приведение без разделителя не пропускает запятую",
+			src:     `Число("1,5")`,
+			wantErr: "Неверный формат числа",
+		},
+		{
+			name:    "приведение без разделителя не пропускает пробелы",
+			src:     `Число("1 5")`,
+			wantErr: "Неверный формат числа",
+		},
+	})
+}
+
+func TestBoolCast(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "числа",
+			src:  `сообщить(Булево(0), Булево(1), Булево(-2), Булево(0.0), Булево(0.5))`,
+			want: "false true true false true\n",
+		},
+		{
+			name: "истинность в операциях не меняется",
+			src:  `сообщить(!-1, !-0.5, Булево(-1))`,
+			want: "true true true\n",
+		},
+		{
+			name: "строки",
+			src:  `сообщить(Булево("Истина"), Булево("ложь"), Булево("TRUE"), Булево("false"))`,
+			want: "true false true false\n",
+		},
+		{
+			name: "неопределено и null",
+			src:  `сообщить(Булево(неопределено), Булево(null), Булево(истина))`,
+			want: "false false true\n",
+		},
+		{
+			name:    "неоднозначная строка",
+			src:     `Булево("да")`,
+			wantErr: "Приведение к типу невозможно",
+		},
+	})
+}
+
+func TestAsTypeBuiltin(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "совпадающий тип",
+			src:  `сообщить(КакТип("5", "Строка"), КакТип(5, "ЦелоеЧисло"), КакТип([1], "Массив"))`,
+			want: "5 5 [1]\n",
+		},
+		{
+			name:    "без приведения",
+			src:     `КакТип("5", "Число")`,
+			wantErr: "[1:1] Значение не является значением типа Число",
+		},
+		{
+			name: "пользовательские структуры",
+			src: `структура Точка {х, у}
+структура Круг {центр, радиус}
+т = новый Точка
+т.х = 1
+сообщить(КакТип(т, "Точка").х)
+попытка
+	КакТип(т, "Круг")
+исключение
+	сообщить(ОписаниеОшибки())
+конецпопытки`,
+			want: "1\n[7:2] Значение не является значением типа Круг\n",
+		},
+		{
+			name:    "неизвестный тип",
+			src:     `КакТип(1, "Нет")`,
+			wantErr: "Тип неопределен 'Нет'",
+		},
+	})
+}
+
+func TestParenFolding(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{name: "индекс массива", src: `сообщить([10, 20, 30, 40][(1+2)])`, want: "40\n"},
+		{name: "индекс строки", src: `сообщить("абв"[(4-3)*2])`, want: "в\n"},
+		{name: "ключ структуры", src: `сообщить({"аб": 1}[("а"+"б")], {"аб": 1}[("в")])`, want: "1 Неопределено\n"},
+		{name: "границы слайса", src: `сообщить([1, 2, 3, 4][(0+1):(1*3)])`, want: "[2,3]\n"},
+		{name: "аргументы функции", src: `сообщить((1+2)*(3), ("а"+"б"))`, want: "9 аб\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, bin, err := ParseSrc(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ks := kinds(bin)
+			if n := len(ks) - count(ks, "LOAD") - count(ks, "CALL"); n != 0 || count(ks, "CALL") != 1 {
+				t.Errorf("получено %v, ожидались только загрузки констант и вызов", ks)
+			}
+			out, err := runScript(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out != tt.want {
+				t.Errorf("вывод = %q, ожидался %q", out, tt.want)
+			}
+		})
+	}
+
+	// выход за границы не сворачивается и обрабатывается при исполнении
+	_, bin, err := ParseSrc(`а = [1, 2][(1+1)]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count(kinds(bin), "GETIDX") != 1 {
+		t.Errorf("получено %v, ожидалось обращение по индексу при исполнении", kinds(bin))
+	}
+	if _, err := runScript(`а = [1, 2][(1+1)]`); err == nil || !strings.Contains(err.Error(), "Индекс за пределами границ") {
+		t.Errorf("ошибка = %v", err)
+	}
+}
+
+func TestConcatChain(t *testing.T) {
+	src := `б = "Б"
+г = "3"
+сообщить("а" + б + "в" + г + "д")`
+	_, folded, err := ParseSrc(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, raw, err := ParseSrcWithOptions(src, CompileOptions{NoFolding: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks, rks := kinds(folded), kinds(raw)
+	if count(ks, "CONCAT") != 1 || count(ks, "OPER") != 0 || len(ks) >= len(rks) {
+		t.Errorf("получено %v, ожидалась одна сборка строки вместо %v", ks, rks)
+	}
+
+	runScriptTests(t, []scriptTest{
+		{
+			name: "строки",
+			src:  `б = "Б"` + "\n" + `сообщить("а" + б + "в" + б + "д")`,
+			want: "аБвБд\n",
+		},
+		{
+			name: "числовая цепочка не меняется",
+			src:  "а = 1\nсообщить(а + 2 + 3.5)",
+			want: "6.5\n",
+		},
+		{
+			name:    "не строка в цепочке",
+			src:     "г = 3\nс = \"а\" + г + \"в\"",
+			wantErr: "Операция между значениями невозможна",
+		},
+	})
+
+	_, bin, err := ParseSrc("а = 1\nб = а + 2 + 3 + 4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ks := kinds(bin); count(ks, "CONCAT") != 0 || count(ks, "OPER") != 3 {
+		t.Errorf("числовая цепочка скомпилирована в %v", ks)
+	}
+}
+
+// frameSrc - функция вызывается после выражения с глубокой вложенностью, которое занимает много регистров
+const frameSrc = `б = 1
+а = б + (б + (б + (б + (б + (б + (б + (б + (б + б))))))))
+функция Ф(х)
+	возврат х * 2 + 1
+конецфункции
+с = 0
+для н = 1 по 1000 цикл
+	с = с + Ф(н)
+конеццикла
+сообщить(а, с)`
+
+func TestFuncFrameSize(t *testing.T) {
+	_, bin, err := ParseSrc(frameSrc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fn *binstmt.BinFUNC
+	for _, s := range bin.Code {
+		if f, ok := s.(*binstmt.BinFUNC); ok {
+			fn = f
+		}
+	}
+	if fn == nil {
+		t.Fatal("не найдено объявление функции")
+	}
+	// регистры функции не зависят от регистров окружающего кода
+	if fn.MaxReg > 1 || bin.MaxReg < 9 {
+		t.Errorf("регистров в функции %d, в модуле %d", fn.MaxReg, bin.MaxReg)
+	}
+
+	runScriptTests(t, []scriptTest{
+		{
+			name: "результат не изменился",
+			src:  frameSrc,
+			want: "10 1002000\n",
+		},
+		{
+			name: "вложенные функции и рекурсия",
+			src: `функция Факториал(н)
+	если н <= 1 тогда
+		возврат 1
+	конецесли
+	возврат н * Факториал(н - 1)
+конецфункции
+функция Сумма(а, б)
+	ф = функция(х)
+		возврат х * 2
+	конецфункции
+	возврат ф(а) + ф(б) + 1
+конецфункции
+сообщить(Факториал(10), Сумма(5, 3), [1, 2, Сумма(1, 2)][2])`,
+			want: "3628800 17 7\n",
+		},
+	})
+}
+
+func BenchmarkFuncFrame(b *testing.B) {
+	_, bin, err := ParseSrc(frameSrc)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		env := core.NewEnv()
+		env.SetStdOut(ioutil.Discard)
+		if _, err := Run(bin, env); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestConstPool(t *testing.T) {
+	src := `а = "привет"
+б = "привет"
+в = 2.5
+г = а + ", мир"
+сообщить(а, б, в * 2.5, г, "привет" = б)`
+	_, bin, err := ParseSrc(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	consts := map[interface{}]int{}
+	for _, s := range bin.Code {
+		if l, ok := s.(*binstmt.BinLOAD); ok && l.Const > 0 {
+			if bin.Consts[l.Const-1] != l.Val {
+				t.Errorf("значение %v не совпадает с пулом %v", l.Val, bin.Consts[l.Const-1])
+			}
+			consts[l.Val] = l.Const
+		}
+	}
+	if len(bin.Consts) != 3 || consts[core.VMString("привет")] == 0 || len(consts) != len(bin.Consts) {
+		t.Errorf("пул констант %v", bin.Consts)
+	}
+
+	want := "привет привет 6.25 привет, мир true\n"
+	var out bytes.Buffer
+	env := core.NewEnv()
+	env.SetStdOut(&out)
+	if _, err = Run(bin, env); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != want {
+		t.Errorf("вывод %q, ожидался %q", out.String(), want)
+	}
+
+	// после сохранения и загрузки значения восстанавливаются из пула
+	var buf bytes.Buffer
+	if err := binstmt.WriteBinCode(&buf, bin); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := binstmt.ReadBinCode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	env = core.NewEnv()
+	env.SetStdOut(&out)
+	if _, err = Run(loaded, env); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != want {
+		t.Errorf("вывод загруженного кода %q, ожидался %q", out.String(), want)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	src := `а = 1
+пока а < 3 цикл
+	а = а + 1
+конеццикла
+прервать
+ф = функция()
+	возврат 1
+	а = 2
+конецфункции
+возврат а`
+	want := []string{
+		"[5:1] Оператор Прервать может использоваться только внутри цикла",
+		"[6:1] Недостижимый код",
+		"[10:1] Оператор Возврат может использоваться только внутри функции",
+	}
+	errs := Check(src, CompileOptions{})
+	if len(errs) != len(want) {
+		t.Fatalf("ожидалось %d ошибок, получено %v", len(want), errs)
+	}
+	for i, err := range errs {
+		if err.Error() != want[i] {
+			t.Errorf("ошибка %d: ожидалось %q, получено %q", i, want[i], err)
+		}
+	}
+
+	// проверка не исполняет код
+	if errs := Check(`сообщить(1/0)
+вызватьисключение "ошибка"`, CompileOptions{}); len(errs) != 0 {
+		t.Errorf("корректный код: %v", errs)
+	}
+
+	errs = Check("а = (1 + )\nпрервать", CompileOptions{})
+	if len(errs) != 1 || !strings.HasPrefix(errs[0].Error(), "[1:10] синтаксическая ошибка") {
+		t.Errorf("синтаксическая ошибка: %v", errs)
+	}
+}
+
+func TestJoinFormatBuiltin(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "целые",
+			src:  `сообщить(СоединитьФормат([1, 2, 3], "(%v)", ","))`,
+			want: "(1),(2),(3)\n",
+		},
+		{
+			name: "строки и числа",
+			src:  `сообщить(СоединитьФормат(["а", 1.5, истина], "'%v'", "; "))`,
+			want: "'а'; '1.5'; 'true'\n",
+		},
+		{
+			name: "пустой массив",
+			src:  `сообщить("[" + СоединитьФормат([], "(%v)", ",") + "]")`,
+			want: "[]\n",
+		},
+		{
+			name:    "не массив",
+			src:     `СоединитьФормат("1,2", "%v", ",")`,
+			wantErr: "Требуется значение типа Массив",
+		},
+	})
+}
+
+func TestEvalBuiltin(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "значение выражения",
+			src:  `сообщить(Выполнить("1 + 2 * 3"), Выполнить("ц = 1"))`,
+			want: "7 Неопределено\n",
+		},
+		{
+			name: "общие переменные",
+			src: `а = 2
+Выполнить("б = а * 10")
+сообщить(б)
+ф = функция(к)
+	м = 10
+	возврат Выполнить("м + к")
+конецфункции
+сообщить(ф(3))`,
+			want: "20\n13\n",
+		},
+		{
+			name: "синтаксическая ошибка перехватывается",
+			src: `попытка
+	Выполнить("а = (1 + )")
+исключение
+	сообщить(ОписаниеОшибки())
+конецпопытки`,
+			want: "[2:2] [1:10] синтаксическая ошибка: встретилось: ')', ожидается: выражение (имя, число, строка, '(' или '[')\n",
+		},
+		{
+			name:    "бесконечная рекурсия",
+			src:     `с = "Выполнить(с)"` + "\n" + `Выполнить(с)`,
+			wantErr: "Превышена глубина вложенности вызовов Выполнить",
+		},
+	})
+}
+
+func TestEvalDepthPerEnv(t *testing.T) {
+	// фрагмент на глубине 60 Выполнить запускает такой же фрагмент в другом окружении:
+	// вместе вложенность больше MaxEvalDepth, но каждое окружение считает ее отдельно
+	src := `н = 0
+с = "н = н + 1; если н < 60 тогда Выполнить(с) иначе Внутри() конецесли"
+Выполнить(с)
+сообщить(н)`
+	var inner string
+	env := core.NewEnv()
+	env.DefineS("внутри", core.VMFunc(func(args core.VMSlice, rets *core.VMSlice, envout *(*core.Env)) error {
+		var err error
+		inner, err = runScriptEnv(strings.Replace(src, "Внутри()", "н = н", 1), core.NewEnv())
+		return err
+	}))
+	got, err := runScriptEnv(src, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "60\n" || inner != "60\n" {
+		t.Errorf("вывод = %q и %q, ожидалось 60", got, inner)
+	}
+}
+
+func TestSandboxEval(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "входные и выходные переменные",
+			src: `р = ВычислитьИзолированно("итог = цена * количество; имя = врег(имя)", {"цена": 3, "количество": 4, "имя": "тов"})
+сообщить(р.итог, р.имя, р.цена)`,
+			want: "12 ТОВ 3\n",
+		},
+		{
+			name: "переменные вызывающего кода недоступны",
+			src: `секрет = 42
+ВычислитьИзолированно("х = секрет", {})`,
+			wantErr: "Невозможно получить значение",
+		},
+		{
+			name:    "функция не из списка разрешенных",
+			src:     `ВычислитьИзолированно("сообщить(1)", {})`,
+			wantErr: "Имя неопределено 'сообщить'",
+		},
+		{
+			name:    "свой список разрешенных функций",
+			src:     `ВычислитьИзолированно("х = врег('а')", {}, ["нрег"])`,
+			wantErr: "Имя неопределено 'врег'",
+		},
+		{
+			name:    "слишком большой диапазон",
+			src:     `ВычислитьИзолированно("х = диапазон(-9223372036854775807, 9223372036854775807)", {})`,
+			wantErr: "Слишком большой размер результата",
+		},
+	})
+
+	// изолированный код прерывается по контексту вызывающего
+	_, bins, err := ParseSrc(`ВычислитьИзолированно("пока истина цикл конеццикла", {})`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err = RunContext(ctx, bins, core.NewEnv()); err == nil {
+		t.Error("ожидалась ошибка отмены контекста")
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("отмена заняла %v", d)
+	}
+
+	// и расходует общий с ним счетчик инструкций
+	env := core.NewEnv()
+	env.SetInstructionBudget(1000)
+	if _, err = Run(bins, env); err == nil {
+		t.Error("ожидалась ошибка исчерпания лимита инструкций")
+	}
+	if left, _ := env.InstructionsLeft(); left != 0 {
+		t.Errorf("осталось инструкций %d", left)
+	}
+
+	// изолированный код не меняет переменные вызывающего
+	env = core.NewEnv()
+	if _, err := runScriptEnv(`х = 1
+ВычислитьИзолированно("х = 2", {})`, env); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := env.Get(names.UniqueNames.Set("х")); v != core.VMInt(1) {
+		t.Errorf("х = %v", v)
+	}
+}
+
+func TestTryFinally(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "продолжить внутри попытки",
+			src: `для н = 1 по 3 цикл
+	попытка
+		если н = 2 тогда
+			продолжить
+		конецесли
+		сообщить("тело", н)
+	окончательно
+		сообщить("окончательно", н)
+	конецпопытки
+конеццикла`,
+			want: "тело 1\nокончательно 1\nокончательно 2\nтело 3\nокончательно 3\n",
+		},
+		{
+			name: "прервать внутри попытки",
+			src: `для н = 1 по 3 цикл
+	попытка
+		если н = 2 тогда
+			прервать
+		конецесли
+	исключение
+		сообщить("не выполняется")
+	окончательно
+		сообщить("окончательно", н)
+	конецпопытки
+конеццикла
+сообщить("после")`,
+			want: "окончательно 1\nокончательно 2\nпосле\n",
+		},
+		{
+			name: "прервать из обработки исключения",
+			src: `пока истина цикл
+	попытка
+		вызватьисключение "сбой"
+	исключение
+		прервать
+	окончательно
+		сообщить("окончательно")
+	конецпопытки
+конеццикла
+сообщить("после")`,
+			want: "окончательно\nпосле\n",
+		},
+		{
+			name: "вложенные разделы",
+			src: `для каждого н из [1, 2] цикл
+	попытка
+		попытка
+			продолжить
+		окончательно
+			сообщить("внутренний", н)
+		конецпопытки
+	окончательно
+		сообщить("внешний", н)
+	конецпопытки
+конеццикла`,
+			want: "внутренний 1\nвнешний 1\nвнутренний 2\nвнешний 2\n",
+		},
+		{
+			name: "возврат из попытки",
+			src: `функция ф()
+	для н = 1 по 3 цикл
+		попытка
+			возврат н * 10
+		окончательно
+			сообщить("окончательно")
+		конецпопытки
+	конеццикла
+конецфункции
+сообщить(ф())`,
+			want: "окончательно\n10\n",
+		},
+		{
+			name: "ошибка без раздела исключение",
+			src: `попытка
+	попытка
+		вызватьисключение "сбой"
+	окончательно
+		сообщить("окончательно")
+	конецпопытки
+исключение
+	сообщить("перехвачено", СтрСодержит(ОписаниеОшибки(), "сбой"))
+конецпопытки`,
+			want: "окончательно\nперехвачено true\n",
+		},
+		{
+			name: "ошибка в обработке исключения",
+			src: `попытка
+	вызватьисключение "первая"
+исключение
+	вызватьисключение "вторая"
+окончательно
+	сообщить("окончательно")
+конецпопытки`,
+			want:    "окончательно\n",
+			wantErr: "вторая",
+		},
+		{
+			name: "прервать из попытки без окончательно",
+			src: `пока истина цикл
+	попытка
+		прервать
+	исключение
+	конецпопытки
+конеццикла
+попытка
+	вызватьисключение "сбой"
+исключение
+	сообщить("перехвачено")
+конецпопытки`,
+			want: "перехвачено\n",
+		},
+	})
+}
+
+func TestStructOperators(t *testing.T) {
+	vec := `структура Вектор { Х, У }
+функция НовыйВектор(х, у)
+	в = новый Вектор
+	в.Х = х
+	в.У = у
+	возврат в
+конецфункции
+функция (а Вектор) __Сложить__(б)
+	возврат НовыйВектор(а.Х + б.Х, а.У + б.У)
+конецфункции
+функция (а Вектор) __Равно__(б)
+	возврат а.Х * а.Х + а.У * а.У = б.Х * б.Х + б.У * б.У
+конецфункции
+функция (а Вектор) __Меньше__(б)
+	возврат а.Х * а.Х + а.У * а.У < б.Х * б.Х + б.У * б.У
+конецфункции
+`
+	runScriptTests(t, []scriptTest{
+		{
+			name: "сложение",
+			src: vec + `с = НовыйВектор(1, 2) + НовыйВектор(3, 4) + НовыйВектор(10, 20)
+сообщить(с.Х, с.У)`,
+			want: "14 26\n",
+		},
+		{
+			name: "равенство",
+			src: vec + `а = НовыйВектор(3, 4)
+б = НовыйВектор(0, 5)
+сообщить(а = б, а != б, а = НовыйВектор(1, 1))`,
+			want: "true false false\n",
+		},
+		{
+			name: "сравнение",
+			src: vec + `а = НовыйВектор(1, 1)
+б = НовыйВектор(2, 2)
+сообщить(а < б, а > б, а <= а, б >= а, б <= а)`,
+			want: "true false true true false\n",
+		},
+		{
+			name: "выбор по значению",
+			src: vec + `выбор НовыйВектор(0, 1):
+	когда НовыйВектор(1, 0):
+		сообщить("равны")
+	другое:
+		сообщить("нет")
+конецвыбора`,
+			want: "равны\n",
+		},
+		{
+			name:    "без перегрузки",
+			src:     vec + `НовыйВектор(1, 2) - НовыйВектор(1, 2)`,
+			wantErr: "Операция между значениями невозможна",
+		},
+		{
+			name: "сравнение со значением другого типа",
+			src: `структура Т { Х }
+функция (а Т) __Меньше__(б)
+	возврат а.Х < б
+конецфункции
+функция (а Т) __Равно__(б)
+	возврат а.Х = б
+конецфункции
+т = новый Т
+т.Х = 5
+сообщить(т > 3, т > 5, т <= 5, т <= 3, т >= 5, т < 6)`,
+			want: "true false true false true true\n",
+		},
+		{
+			name: "равенство по умолчанию",
+			src: `структура Точка { Х }
+а = новый Точка
+б = новый Точка
+сообщить(а = б)`,
+			want: "true\n",
+		},
+		{
+			name: "метод сравнения возвращает не булево",
+			src: `структура Точка { Х }
+функция (а Точка) __Равно__(б)
+	возврат 1
+конецфункции
+а = новый Точка
+сообщить(а = а)`,
+			wantErr: "Требуется значение типа Булево",
+		},
+	})
+}
+
+func TestJSONIndentBuiltin(t *testing.T) {
+	src := `с = Структура('{"имя": "тест", "порты": [80, 443], "опции": {"вкл": true}}')
+`
+	runScriptTests(t, []scriptTest{
+		{
+			name: "вложенная структура",
+			src:  src + `сообщить(ВЈСонКрасиво(с, "  "))`,
+			want: `{
+  "имя": "тест",
+  "опции": {
+    "вкл": true
+  },
+  "порты": [
+    80,
+    443
+  ]
+}
+`,
+		},
+		{
+			name: "совпадает с компактным после разбора",
+			src: src + `к = Строка(с)
+п = ВЈСонКрасиво(с, "  ")
+сообщить(к = п, Строка(Структура(п)) = к, Строка(Массив(ВЈСонКрасиво([1, [2]], "\t"))))`,
+			want: "false true [1,[2]]\n",
+		},
+		{
+			name: "написание с латиницей",
+			src:  `сообщить(ВJsonКрасиво([], "  "))`,
+			want: "[]\n",
+		},
+		{
+			name:    "отступ не строка",
+			src:     `ВЈСонКрасиво([1], 2)`,
+			wantErr: "Требуется значение типа Строка",
+		},
+	})
+}
+
+func TestZeroPadBuiltin(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "положительное",
+			src:  `сообщить(ДополнитьНулями(42, 5))`,
+			want: "00042\n",
+		},
+		{
+			name: "отрицательное",
+			src:  `сообщить(ДополнитьНулями(-42, 5))`,
+			want: "-0042\n",
+		},
+		{
+			name: "шире заданной ширины",
+			src:  `сообщить(ДополнитьНулями(123456, 3), ДополнитьНулями(-123, 2), ДополнитьНулями(7, 0))`,
+			want: "123456 -123 7\n",
+		},
+		{
+			name:    "не целое",
+			src:     `ДополнитьНулями(1.5, 3)`,
+			wantErr: "Требуется значение типа ЦелоеЧисло",
+		},
+		{
+			name:    "слишком большая ширина",
+			src:     `ДополнитьНулями(1, 9223372036854775807)`,
+			wantErr: "Слишком большой размер результата",
+		},
+	})
+}
+
+func TestTryExpr(t *testing.T) {
+	risky := `функция Рискованно(х)
+	если х < 0 тогда
+		вызватьисключение "отрицательное"
+	конецесли
+	возврат х * 2
+конецфункции
+`
+	runScriptTests(t, []scriptTest{
+		{
+			name: "значение",
+			src: risky + `рез = попытаться Рискованно(5)
+сообщить(рез[0], рез[1] = null)`,
+			want: "10 true\n",
+		},
+		{
+			name: "ошибка",
+			src: risky + `рез = попытаться Рискованно(-1)
+сообщить(рез[0] = null, СтрСодержит(рез[1], "отрицательное"))`,
+			want: "true true\n",
+		},
+		{
+			name: "охватывает все выражение",
+			src: risky + `[з, о] = попытаться Рискованно(2) + 1
+сообщить(з, о)`,
+			want: "5 NULL\n",
+		},
+		{
+			name: "ошибка не выходит за выражение",
+			src: risky + `для н = 1 по 3 цикл
+	рез = попытаться Рискованно(-н)
+конеццикла
+попытка
+	вызватьисключение "внешняя"
+исключение
+	сообщить("перехвачено", СтрСодержит(ОписаниеОшибки(), "внешняя"))
+конецпопытки
+сообщить("после")`,
+			want: "перехвачено true\nпосле\n",
+		},
+		{
+			name: "ошибка среды выполнения",
+			src: `м = [1]
+[з, о] = попытаться м[5]
+сообщить(з = null, Длина(о) > 0)`,
+			want: "true true\n",
+		},
+	})
+}
+
+func TestSwitchRange(t *testing.T) {
+	sw := `функция Оценка(х)
+	выбор х:
+		когда 0:
+			возврат "ноль"
+		случай 1 до 10:
+			возврат "от 1 до 10"
+		когда 11 по 20:
+			возврат "от 11 до 20"
+		другое:
+			возврат "другое"
+	конецвыбора
+конецфункции
+`
+	runScriptTests(t, []scriptTest{
+		{
+			name: "внутри диапазона",
+			src:  sw + `сообщить(Оценка(5), Оценка(15), Оценка(7.5))`,
+			want: "от 1 до 10 от 11 до 20 от 1 до 10\n",
+		},
+		{
+			name: "границы включительно",
+			src:  sw + `сообщить(Оценка(1), Оценка(10), Оценка(11), Оценка(20))`,
+			want: "от 1 до 10 от 1 до 10 от 11 до 20 от 11 до 20\n",
+		},
+		{
+			name: "вне диапазона",
+			src:  sw + `сообщить(Оценка(0), Оценка(-1), Оценка(21), Оценка(10.5))`,
+			want: "ноль другое другое другое\n",
+		},
+		{
+			name: "границы - выражения",
+			src: `н = 3
+выбор "б":
+	когда "а" по "в":
+		сообщить("буква")
+конецвыбора
+для и1 = 1 до н цикл
+	выбор и1 * 2:
+		когда н - 1 по н + 1:
+			сообщить(и1)
+	конецвыбора
+конеццикла`,
+			want: "буква\n1\n2\n",
+		},
+	})
+}
+
+func TestGlobalDecl(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "функция изменяет глобальную переменную",
+			src: `счетчик = 0
+функция Увеличить()
+	глобальная счетчик
+	счетчик = счетчик + 1
+	счетчик += 10
+	счетчик++
+конецфункции
+Увеличить()
+Увеличить()
+сообщить(счетчик)`,
+			want: "24\n",
+		},
+		{
+			name: "без объявления создается локальная",
+			src: `имя = "модуль"
+функция Тень()
+	имя = "локальная"
+	возврат имя
+конецфункции
+сообщить(Тень(), имя)`,
+			want: "локальная модуль\n",
+		},
+		{
+			name: "чтение без присваивания",
+			src: `имя = "модуль"
+функция Прочитать()
+	глобальная имя
+	возврат имя
+конецфункции
+сообщить(Прочитать())`,
+			want: "модуль\n",
+		},
+		{
+			name: "новая глобальная переменная",
+			src: `функция Установить(з)
+	глобальная а, б
+	[а, б] = [з, з * 2]
+конецфункции
+Установить(2)
+сообщить(а, б)`,
+			want: "2 4\n",
+		},
+		{
+			name: "не действует во вложенной функции",
+			src: `х = 1
+функция Внешняя()
+	глобальная х
+	ф = функция()
+		х = 5
+		возврат х
+	конецфункции
+	х = ф() + 1
+конецфункции
+Внешняя()
+сообщить(х)`,
+			want: "6\n",
+		},
+		{
+			name: "в модуле изменяется переменная модуля",
+			src: `остаток = 1
+Модуль Склад
+остаток = 10
+функция Списать()
+	глобальная остаток
+	остаток = 5
+конецфункции
+Модуль _
+Склад.Списать()
+сообщить(Склад.остаток, остаток)`,
+			want: "5 1\n",
+		},
+		{
+			name:    "вне функции",
+			src:     `глобальная х`,
+			wantErr: "Объявление Глобальная допустимо только в теле функции",
+		},
+		{
+			name: "параметр функции",
+			src: `функция ф(х)
+	глобальная х
+конецфункции`,
+			wantErr: "не может быть глобальной переменной",
+		},
+	})
+}
+
+func TestModuleNamesBuiltin(t *testing.T) {
+	mod := `Модуль Склад
+остатокнаскладе = 10
+функция принятьнасклад(к)
+	возврат к
+конецфункции
+Модуль _
+`
+	runScriptTests(t, []scriptTest{
+		{
+			name: "функции и переменные",
+			src:  mod + `сообщить(ЭлементыМодуля(Склад))`,
+			want: "[\"остатокнаскладе\",\"принятьнасклад\"]\n",
+		},
+		{
+			name: "неопределенные имена отсутствуют",
+			src: mod + `эл = ЭлементыМодуля(Склад)
+[_, есть] = эл.Найти("списатьсосклада")
+[_, встроенная] = эл.Найти("сообщить")
+сообщить(Длина(эл), есть, встроенная)`,
+			want: "2 false false\n",
+		},
+		{
+			name:    "не модуль",
+			src:     `ЭлементыМодуля(1)`,
+			wantErr: "Требуется значение типа Модуль",
+		},
+	})
+}
+
+const memberChainSrc = `к = {"уровень1": {"уровень2": {"уровень3": {"значение": 42}}}}
+с = 0
+для н = 1 по 1000 цикл
+	с = с + к.уровень1.уровень2.уровень3.значение
+конеццикла
+сообщить(с)`
+
+func TestMemberChain(t *testing.T) {
+	// чтение цепочки полей переменной - один GET и GETMEMBER в том же регистре
+	_, bin, err := ParseSrc(`к = {"а": {"б": {"в": 1}}}
+сообщить(к.а.б.в)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gets, members []int
+	for _, s := range bin.Code {
+		switch s := s.(type) {
+		case *binstmt.BinGET:
+			if names.UniqueNames.GetLowerCase(s.Id) == "к" {
+				gets = append(gets, s.Reg)
+			}
+		case *binstmt.BinGETMEMBER:
+			members = append(members, s.Reg)
+		}
+	}
+	if len(gets) != 1 || len(members) != 3 {
+		t.Fatalf("GET %v, GETMEMBER %v, ожидались 1 и 3", gets, members)
+	}
+	for _, r := range members {
+		if r != gets[0] {
+			t.Errorf("GETMEMBER в регистре %d, ожидался %d", r, gets[0])
+		}
+	}
+
+	// цепочка от константного литерала сворачивается при компиляции
+	_, bin, err = ParseSrc(`сообщить({"а": {"б": {"в": 42}}}.а.б.в, {"а": 1}.нет)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range bin.Code {
+		if _, ok := s.(*binstmt.BinGETMEMBER); ok {
+			t.Errorf("цепочка не свернута: %v", s)
+		}
+	}
+
+	runScriptTests(t, []scriptTest{
+		{
+			name: "свернутая цепочка",
+			src:  `сообщить({"а": {"б": {"в": 42}}}.а.б.в, {"а": 1}.нет, {"а": [1, 2]}.а[1])`,
+			want: "42 Неопределено 2\n",
+		},
+		{
+			name: "цепочка переменной",
+			src:  memberChainSrc,
+			want: "42000\n",
+		},
+	})
+}
+
+func BenchmarkMemberChain(b *testing.B) {
+	_, bin, err := ParseSrc(memberChainSrc)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		env := core.NewEnv()
+		env.SetStdOut(ioutil.Discard)
+		if _, err := Run(bin, env); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestSetType(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "добавление, удаление, вхождение",
+			src: `м = Множество("а", 1)
+м.Добавить("б")
+м.Удалить("а")
+м.Удалить("нет")
+сообщить(м.Содержит("б"), м.Содержит("а"), м.Содержит(1), Длина(м), НРег(ТипЗнч(м)))`,
+			want: "true false true 2 множество\n",
+		},
+		{
+			name: "повторное добавление",
+			src: `м = Множество(1, 1, 2)
+м.Добавить(2)
+сообщить(Длина(м), м)`,
+			want: "2 [1,2]\n",
+		},
+		{
+			name: "объединение и пересечение",
+			src: `а = Множество(1, 2, 3)
+б = Множество(2, 3, 4)
+сообщить(а.Объединение(б), а.Пересечение(б), а.Разность(б), а = Множество(3, 2, 1))`,
+			want: "[1,2,3,4] [2,3] [1] true\n",
+		},
+		{
+			name: "обход в цикле",
+			src: `для каждого э из Множество("в", "а", "б") цикл
+	сообщить(э)
+конеццикла`,
+			want: "а\nб\nв\n",
+		},
+		{
+			name: "массивы и структуры как элементы",
+			src: `м = Множество([1, 2], [2, 1], {"а": 1, "б": [3]})
+м.Добавить({"б": [3], "а": 1})
+а = [1, 2]
+м.Добавить(а)
+а[0] = 5
+сообщить(Длина(м), м.Содержит([1, 2]), м.Содержит([5, 2]), м.Содержит({"б": [3], "а": 1}))`,
+			want: "3 true false true\n",
+		},
+		{
+			name:    "нехэшируемый элемент",
+			src:     `Множество([1.5])`,
+			wantErr: "Элементом множества может быть только строка, целое число, булево или массив и структура из них",
+		},
+	})
+}
+
+func TestOrderedMap(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "обход в порядке добавления",
+			src: `у = УпорядоченнаяСтруктура()
+у["я"] = 1
+у.б = 2
+у["а"] = 3
+для каждого к из у цикл
+	сообщить(к, у[к])
+конеццикла
+сообщить(у.Ключи(), у.Значения(), Длина(у), у.нет, НРег(ТипЗнч(у)))`,
+			want: "я 1\nб 2\nа 3\n[\"я\",\"б\",\"а\"] [1,2,3] 3 Неопределено упорядоченнаяструктура\n",
+		},
+		{
+			name: "присваивание существующему ключу сохраняет позицию",
+			src: `у = УпорядоченнаяСтруктура()
+у["в"] = 1
+у["а"] = 2
+у["б"] = 3
+у["в"] = 10
+у.а = 20
+сообщить(у.Ключи(), у.в, у["а"])
+у.Удалить("а")
+у["а"] = 30
+сообщить(у.Ключи())`,
+			want: "[\"в\",\"а\",\"б\"] 10 20\n[\"в\",\"б\",\"а\"]\n",
+		},
+		{
+			name: "JSON в порядке добавления",
+			src: `у = УпорядоченнаяСтруктура()
+у["я"] = 1
+у["а"] = [1, 2]
+у["м"] = УпорядоченнаяСтруктура('{"ю": 1, "б": {"щ": null, "в": "т"}}')
+у["я"] = 2
+сообщить(у)
+сообщить(ВJsonКрасиво(у, " "))`,
+			want: "{\"я\":2,\"а\":[1,2],\"м\":{\"ю\":1,\"б\":{\"щ\":null,\"в\":\"т\"}}}\n" +
+				"{\n \"я\": 2,\n \"а\": [\n  1,\n  2\n ],\n \"м\": {\n  \"ю\": 1,\n  \"б\": {\n   \"щ\": null,\n   \"в\": \"т\"\n  }\n }\n}\n",
+		},
+		{
+			name:    "строка JSON не объект",
+			src:     `УпорядоченнаяСтруктура("[1]")`,
+			wantErr: "Требуется значение типа Структура",
+		},
+	})
+}
+
+func TestFreeze(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "чтение замороженного значения",
+			src: `м = Заморозить([1, [2, 3], {"а": 4}])
+сообщить(м, м[0], м[-1].а, м[1][1], Длина(м), НРег(ТипЗнч(м)))
+для каждого э из м цикл
+	сообщить(э)
+конеццикла
+[_, есть] = м.Найти(1)
+сообщить(есть)`,
+			want: "[1,[2,3],{\"а\":4}] 1 4 3 3 замороженное\n1\n[2,3]\n{\"а\":4}\ntrue\n",
+		},
+		{
+			name:    "запись по индексу",
+			src:     `м = Заморозить([1, 2]); м[0] = 5`,
+			wantErr: "Значение заморожено и не может быть изменено",
+		},
+		{
+			name:    "запись поля",
+			src:     `с = Заморозить({"а": 1}); с.а = 5`,
+			wantErr: "Значение заморожено и не может быть изменено",
+		},
+		{
+			name:    "изменяющий метод",
+			src:     `м = Заморозить([2, 1]); м.Сортировать()`,
+			wantErr: "Значение заморожено и не может быть изменено",
+		},
+		{
+			name: "ошибка перехватывается",
+			src: `м = Заморозить({"а": 1})
+попытка
+	м["а"] = 2
+исключение
+	сообщить("перехвачено")
+конецпопытки
+сообщить(м.а)`,
+			want: "перехвачено\n1\n",
+		},
+		{
+			name: "заморозка глубокая",
+			src: `м = Заморозить({"а": [1, {"б": 2}]})
+попытка
+	м.а[0] = 5
+исключение
+	сообщить(ОписаниеОшибки())
+конецпопытки
+попытка
+	м.а[1].б = 5
+исключение
+	сообщить(ОписаниеОшибки())
+конецпопытки
+для каждого з из м.Значения() цикл
+	попытка
+		з[0] = 5
+	исключение
+		сообщить("значения заморожены")
+	конецпопытки
+конеццикла
+сообщить(м)`,
+			want: "[3:2] Значение заморожено и не может быть изменено\n" +
+				"[8:2] Значение заморожено и не может быть изменено\n" +
+				"значения заморожены\n" +
+				"{\"а\":[1,{\"б\":2}]}\n",
+		},
+		{
+			name: "копия изменяема, исходное значение видно через замороженное",
+			src: `исх = [1, [2]]
+м = Заморозить(исх)
+к = м.Скопировать()
+к[1][0] = 9
+исх[0] = 7
+сообщить(к, м)`,
+			want: "[1,[9]] [7,[2]]\n",
+		},
+	})
+}
+
+func TestGenerator(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "конечная последовательность",
+			src: `г = Генератор(функция(к)
+	для н = 1 по 4 цикл
+		к <- н * н
+	конеццикла
+конецфункции)
+для каждого з из г цикл
+	сообщить(з)
+конеццикла
+[з, ок] = г.Получить()
+сообщить(з, ок)`,
+			want: "1\n4\n9\n16\nНеопределено false\n",
+		},
+		{
+			name: "ошибка генератора передается получателю",
+			src: `г = Генератор(функция(к)
+	к <- 1
+	вызватьисключение "сбой генератора"
+конецфункции)
+попытка
+	для каждого з из г цикл
+		сообщить(з)
+	конеццикла
+исключение
+	сообщить(ОписаниеОшибки())
+конецпопытки
+[з, ок] = г.Получить()
+сообщить(з, ок)`,
+			want: "1\n[3:2] сбой генератора\nНеопределено false\n",
+		},
+		{
+			name:    "требуется функция",
+			src:     `Генератор(1)`,
+			wantErr: "Требуется значение типа Функция",
+		},
+	})
+}
+
+const generatorEarlyStopSrc = `г = Генератор(функция(к)
+	н = 0
+	пока истина цикл
+		н = н + 1
+		к <- н
+	конеццикла
+конецфункции)
+для каждого з из г цикл
+	если з > 3 тогда
+		г.Закрыть()
+		прервать
+	конецесли
+	сообщить(з)
+конеццикла`
+
+// waitGoroutines ждет, пока число горутин не вернется к прежнему
+func waitGoroutines(t *testing.T, before int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("горутина генератора не завершилась: было %d, стало %d", before, runtime.NumGoroutine())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestGeneratorEarlyStop(t *testing.T) {
+	before := runtime.NumGoroutine()
+	got, err := runScript(generatorEarlyStopSrc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "1\n2\n3\n" {
+		t.Errorf("вывод = %q", got)
+	}
+	// горутина генератора должна завершиться после закрытия канала получателем
+	waitGoroutines(t, before)
+}
+
+func TestGeneratorContextCancel(t *testing.T) {
+	// получатель прерывает цикл, не закрывая канал, - генератор завершается при отмене исполнения
+	_, bins, err := ParseSrc(strings.Replace(generatorEarlyStopSrc, "\t\tг.Закрыть()\n", "", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	env := core.NewEnv()
+	var out bytes.Buffer
+	env.SetStdOut(&out)
+	if _, err = RunContext(ctx, bins, env); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != "1\n2\n3\n" {
+		t.Errorf("вывод = %q", got)
+	}
+	cancel()
+	waitGoroutines(t, before)
+}
+
+func TestGeneratorLoopExit(t *testing.T) {
+	// получатель выходит из цикла, не закрывая канал, - цикл закрывает его сам,
+	// поэтому следующее чтение сообщает о закрытом канале, а горутина генератора завершается
+	gen := `г = Генератор(функция(к)
+	н = 0
+	пока истина цикл
+		н = н + 1
+		к <- н
+	конеццикла
+конецфункции)
+`
+	check := `
+[з, ок] = г.Получить()
+сообщить(з, ок)`
+	tests := []scriptTest{
+		{
+			name: "прервать",
+			src: gen + `для каждого з из г цикл
+	если з > 2 тогда
+		прервать
+	конецесли
+	сообщить(з)
+конеццикла` + check,
+			want: "1\n2\nНеопределено false\n",
+		},
+		{
+			name: "возврат из функции",
+			src: gen + `функция Первое(г)
+	для каждого з из г цикл
+		возврат з
+	конеццикла
+конецфункции
+сообщить(Первое(г))` + check,
+			want: "1\nНеопределено false\n",
+		},
+		{
+			name: "ошибка внутри цикла",
+			src: gen + `попытка
+	для каждого з из г цикл
+		сообщить(з)
+		вызватьисключение "сбой"
+	конеццикла
+исключение
+	сообщить(ОписаниеОшибки())
+конецпопытки` + check,
+			want: "1\n[11:3] сбой\nНеопределено false\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := runtime.NumGoroutine()
+			got, err := runScript(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("вывод = %q, ожидался %q", got, tt.want)
+			}
+			waitGoroutines(t, before)
+		})
+	}
+}
+
+func TestExpLogBuiltins(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "известные значения",
+			src: `сообщить(Экспонента(0), Логарифм(1), Логарифм10(1000), Логарифм10(0.01))
+сообщить(Окр(Логарифм(Экспонента(1)), 10) = 1, Окр(Экспонента(1), 5), НРег(ТипЗнч(Экспонента(0))))`,
+			want: "1 0 3 -2\ntrue 2.71828 число\n",
+		},
+		{
+			name: "ошибка области определения перехватывается",
+			src: `попытка
+	Логарифм(0)
+исключение
+	сообщить(ОписаниеОшибки())
+конецпопытки`,
+			want: "[2:2] Логарифм определен только для положительных чисел\n",
+		},
+		{
+			name:    "отрицательный аргумент десятичного логарифма",
+			src:     `Логарифм10(-1)`,
+			wantErr: "Логарифм определен только для положительных чисел",
+		},
+		{
+			name:    "переполнение",
+			src:     `Экспонента(1000)`,
+			wantErr: "Переполнение числа",
+		},
+	})
+}
+
+func TestTrigBuiltins(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "известные значения",
+			src: `сообщить(Синус(0), Косинус(0), Тангенс(0), Арккосинус(1))
+сообщить(Окр(Синус(Радианы(30)), 10) = 0.5, Окр(Арктангенс(1) * 4, 10) = Окр(Радианы(180), 10))`,
+			want: "0 1 0 0\ntrue true\n",
+		},
+		{
+			name: "перевод углов",
+			src:  `сообщить(Радианы(180), Градусы(Радианы(90)), Градусы(0))`,
+			want: "3.141592653589793 90 0\n",
+		},
+		{
+			name: "арксинус вне области определения перехватывается",
+			src: `попытка
+	Арксинус(2)
+исключение
+	сообщить(ОписаниеОшибки())
+конецпопытки`,
+			want: "[2:2] Аргумент вне области определения функции\n",
+		},
+		{
+			name:    "требуется число",
+			src:     `Синус("0")`,
+			wantErr: "Требуется значение типа Число",
+		},
+	})
+}
+
+func TestExplicitConversions(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "к целому",
+			src:  `сообщить(Целое(2.7), Целое(-2.7), Целое("42"), Целое(" 3.9 "), Целое(Истина), Целое(7), НРег(ТипЗнч(Целое(2.7))))`,
+			want: "2 -2 42 3 1 7 целоечисло\n",
+		},
+		{
+			name: "к десятичному",
+			src:  `сообщить(Десятичное(5), Десятичное("2.50"), Десятичное(Ложь), Десятичное(5) / 2, НРег(ТипЗнч(Десятичное(5))))`,
+			want: "5 2.50 0 2.5 число\n",
+		},
+		{
+			name: "к строке",
+			src:  `сообщить(Строка(5) + Строка(2.5), Строка(Истина), Строка(Целое("12")), НРег(ТипЗнч(Строка(5))))`,
+			want: "52.5 true 12 строка\n",
+		},
+		{
+			name: "невозможное приведение перехватывается",
+			src: `попытка
+	Целое("абв")
+исключение
+	сообщить(ОписаниеОшибки())
+конецпопытки
+попытка
+	Десятичное([1])
+исключение
+	сообщить(ОписаниеОшибки())
+конецпопытки
+попытка
+	Строка(функция() возврат 1 конецфункции)
+исключение
+	сообщить("строка")
+конецпопытки`,
+			want: "[2:2] Неверный формат числа\n[7:2] Приведение к типу невозможно\nстрока\n",
+		},
+		{
+			name:    "десятичное не пропускает запятую",
+			src:     `Десятичное("2,5")`,
+			wantErr: "Неверный формат числа",
+		},
+		{
+			name:    "целое не пропускает пробелы",
+			src:     `Целое("1 5")`,
+			wantErr: "Неверный формат числа",
+		},
+		{
+			name:    "переполнение целого",
+			src:     `Целое(1e30)`,
+			wantErr: "Переполнение целого числа",
+		},
+	})
+}
+
+func TestMonotonicNanoseconds(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "показания не убывают",
+			src: `н1 = НаносекундыМоно()
+н2 = НаносекундыМоно()
+сообщить(н2 >= н1, НРег(ТипЗнч(н1)))`,
+			want: "true целоечисло\n",
+		},
+		{
+			name: "разность соответствует паузе",
+			src: `н1 = НаносекундыМоно()
+Пауза(0.05)
+прошло = НаносекундыМоно() - н1
+сообщить(прошло >= 50000000, прошло < 5000000000)`,
+			want: "true true\n",
+		},
+	})
+}
+
+func TestCallDepthLimit(t *testing.T) {
+	newEnv := func() *core.Env {
+		env := core.NewEnv()
+		env.SetMaxCallDepth(100)
+		return env
+	}
+	runScriptTestsEnv(t, newEnv, []scriptTest{
+		{
+			name: "рекурсия в пределах ограничения",
+			src: `функция ф(н)
+	если н = 0 тогда
+		возврат 0
+	конецесли
+	возврат ф(н - 1) + 1
+конецфункции
+сообщить(ф(99))`,
+			want: "99\n",
+		},
+		{
+			name: "превышение ограничения",
+			src: `функция ф(н)
+	возврат ф(н + 1)
+конецфункции
+ф(0)`,
+			wantErr: "Превышена глубина вложенности вызовов функций",
+		},
+		{
+			name: "рекурсивная перегрузка операции",
+			src: `структура В { Х }
+функция (а В) __Сложить__(б)
+	возврат а + б
+конецфункции
+а = новый В
+попытка
+	а + а
+исключение
+	сообщить(СтрСодержит(ОписаниеОшибки(), "Превышена глубина вложенности вызовов функций"))
+конецпопытки`,
+			want: "true\n",
+		},
+		{
+			name: "исключение перехватывается, глубина после выхода восстанавливается",
+			src: `функция ф(н)
+	если н = 0 тогда
+		возврат 0
+	конецесли
+	возврат ф(н - 1) + 1
+конецфункции
+попытка
+	ф(1000)
+исключение
+	сообщить(ОписаниеОшибки())
+конецпопытки
+сообщить(ф(90))`,
+			want: "[1:1] Превышена глубина вложенности вызовов функций\n90\n",
+		},
+		{
+			name: "рекурсия через встроенные функции",
+			src: `функция ф(н)
+	возврат Преобразовать([н], ф)
+конецфункции
+функция г(н)
+	возврат Отфильтровать([н], г)
+конецфункции
+функция к(н)
+	возврат Композиция(к)(н)
+конецфункции
+для каждого функ из [ф, г, к] цикл
+	попытка
+		функ(1)
+	исключение
+		сообщить(ОписаниеОшибки())
+	конецпопытки
+конеццикла`,
+			want: "[1:1] Превышена глубина вложенности вызовов функций\n[4:1] Превышена глубина вложенности вызовов функций\n[7:1] Превышена глубина вложенности вызовов функций\n",
+		},
+	})
+}
+
+func TestCallDepthLimitPerEnv(t *testing.T) {
+	// ограничение одного окружения не действует на другие, исполняющиеся одновременно
+	const src = `функция ф(н)
+	если н = 0 тогда
+		возврат 0
+	конецесли
+	возврат ф(н - 1) + 1
+конецфункции
+сообщить(ф(500))`
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i, depth := range []int{100, 1000} {
+		wg.Add(1)
+		go func(i, depth int) {
+			defer wg.Done()
+			env := core.NewEnv()
+			env.SetMaxCallDepth(depth)
+			_, errs[i] = runScriptEnv(src, env)
+		}(i, depth)
+	}
+	wg.Wait()
+	if errs[0] == nil || !strings.Contains(errs[0].Error(), "Превышена глубина вложенности вызовов функций") {
+		t.Errorf("ограничение 100: ошибка = %v", errs[0])
+	}
+	if errs[1] != nil {
+		t.Errorf("ограничение 1000: ошибка = %v", errs[1])
+	}
+}
+
+func TestRunContext(t *testing.T) {
+	run := func(src string, timeout time.Duration) (string, error) {
+		_, bins, err := ParseSrc(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		env := core.NewEnv()
+		var out bytes.Buffer
+		env.SetStdOut(&out)
+		_, err = RunContext(ctx, bins, env)
+		return out.String(), err
+	}
+
+	t.Run("короткий код завершается", func(t *testing.T) {
+		got, err := run(`сообщить(1 + 2)`, time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != "3\n" {
+			t.Errorf("вывод = %q", got)
+		}
+	})
+
+	t.Run("бесконечный цикл отменяется", func(t *testing.T) {
+		start := time.Now()
+		_, err := run(`функция ф()
+	н = 0
+	пока истина цикл
+		попытка
+			н = н + 1
+		исключение
+			сообщить("перехвачено")
+		конецпопытки
+	конеццикла
+конецфункции
+попытка
+	ф()
+исключение
+	сообщить("перехвачено")
+конецпопытки`, 50*time.Millisecond)
+		if !errors.Is(err, binstmt.CancelError) {
+			t.Fatalf("ошибка = %v, ожидалась отмена", err)
+		}
+		if !strings.Contains(err.Error(), context.DeadlineExceeded.Error()) {
+			t.Errorf("в ошибке нет причины отмены: %v", err)
+		}
+		if d := time.Since(start); d > 5*time.Second {
+			t.Errorf("отмена заняла %v", d)
+		}
+	})
+
+	t.Run("контекст не остается в окружении", func(t *testing.T) {
+		_, bins, err := ParseSrc(`сообщить(1)`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		env := core.NewEnv()
+		var out bytes.Buffer
+		env.SetStdOut(&out)
+		if _, err = RunContext(ctx, bins, env); err != nil {
+			t.Fatal(err)
+		}
+		cancel()
+		// после отмены контекста прошлого запуска окружение исполняет код, как обычно
+		if env.Context() != context.Background() {
+			t.Error("в окружении остался контекст прошлого запуска")
+		}
+		if _, err = Run(bins, env); err != nil {
+			t.Fatal(err)
+		}
+		if got := out.String(); got != "1\n1\n" {
+			t.Errorf("вывод = %q", got)
+		}
+	})
+}
+
+func TestInstructionBudget(t *testing.T) {
+	run := func(src string, budget int64) (string, int64, error) {
+		_, bins, err := ParseSrc(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env := core.NewEnv()
+		env.SetInstructionBudget(budget)
+		var out bytes.Buffer
+		env.SetStdOut(&out)
+		_, err = Run(bins, env)
+		left, _ := env.InstructionsLeft()
+		return out.String(), budget - left, err
+	}
+	const loopSrc = `функция шаг(н)
+	сообщить(н)
+конецфункции
+н = 0
+пока н < %d цикл
+	попытка
+		н = н + 1
+		шаг(н)
+	исключение
+		сообщить("перехвачено")
+	конецпопытки
+конеццикла`
+
+	t.Run("небольшой код укладывается в лимит", func(t *testing.T) {
+		got, used, err := run(`сообщить(1 + 2)`, 1000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != "3\n" || used > 100 {
+			t.Errorf("вывод = %q, израсходовано %d инструкций", got, used)
+		}
+	})
+
+	// число инструкций на одну итерацию цикла
+	_, used, err := run(fmt.Sprintf(loopSrc, 100), 1000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	perIter := used / 100
+
+	t.Run("цикл прерывается при исчерпании лимита", func(t *testing.T) {
+		const budget = 10000
+		got, used, err := run(fmt.Sprintf(loopSrc, 1000000), budget)
+		if err != binstmt.BudgetError {
+			t.Fatalf("ошибка = %v, ожидалось превышение лимита", err)
+		}
+		if used != budget {
+			t.Errorf("израсходовано %d инструкций, лимит %d", used, budget)
+		}
+		if strings.Contains(got, "перехвачено") {
+			t.Error("превышение лимита перехвачено в Попытка")
+		}
+		iters := int64(strings.Count(got, "\n"))
+		if want := budget / perIter; iters < want-1 || iters > want+1 {
+			t.Errorf("выполнено %d итераций, ожидалось около %d", iters, want)
+		}
+	})
+}
+
+func TestGoFuture(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "результат горутины",
+			src: `функция Сумма(а, б)
+	Пауза(0.01)
+	возврат а + б
+конецфункции
+б = старт Сумма(2, 3)
+сообщить(б.Результат(), б.Завершено(), б.Результат(), НРег(ТипЗнч(б)))`,
+			want: "5 true 5 будущее\n",
+		},
+		{
+			name: "ожидание нескольких горутин",
+			src: `функция Квадрат(н)
+	возврат н * н
+конецфункции
+б = []
+для н = 1 по 3 цикл
+	б += [старт Квадрат(н)]
+конеццикла
+сообщить(Ждать(б), Ждать(старт Квадрат(5)))`,
+			want: "[1,4,9] 25\n",
+		},
+		{
+			name: "исключение горутины вызывается повторно",
+			src: `функция Сбой()
+	вызватьисключение "сбой"
+конецфункции
+б = старт Сбой()
+попытка
+	б.Результат()
+исключение
+	сообщить("перехвачено", ЗначениеОшибки())
+конецпопытки`,
+			want: "перехвачено сбой\n",
+		},
+		{
+			name: "исключение горутины без получателя результата выводится",
+			src: `функция Сбой()
+	вызватьисключение "сбой"
+конецфункции
+старт Сбой()
+Пауза(0.05)
+старт функция()
+	вызватьисключение "сбой анонимной"
+конецфункции()
+Пауза(0.05)`,
+			want: "[2:2] сбой\n[7:2] сбой анонимной\n",
+		},
+		{
+			name:    "Ждать требует результат горутины",
+			src:     `Ждать(1)`,
+			wantErr: "Требуется результат функции, запущенной через Старт",
+		},
+	})
+}
+
+// linesWriter собирает вывод по строкам, как это мог бы делать встраивающий интерпретатор код
+type linesWriter struct {
+	lines []string
+	buf   string
+}
+
+func (w *linesWriter) Write(p []byte) (int, error) {
+	w.buf += string(p)
+	for {
+		i := strings.IndexByte(w.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		w.lines = append(w.lines, w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+}
+
+func TestConsoleOutput(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "Сообщить добавляет перевод строки",
+			src: `Сообщить("а", 1)
+Сообщить()
+СообщитьФ("%d", 2)
+Сообщить(3)`,
+			want: "а 1\n\n23\n",
+		},
+		{
+			name: "форматированный вывод",
+			src: `СообщитьФормат("%s = %05.2f, %d%%", "пи", 3.14159, 42)
+СообщитьФормат("без аргументов")`,
+			want: "пи = 03.14, 42%\nбез аргументов\n",
+		},
+		{
+			name:    "шаблон должен быть строкой",
+			src:     `СообщитьФормат(1, 2)`,
+			wantErr: "Требуется значение типа Строка",
+		},
+	})
+
+	t.Run("вывод в установленный поток", func(t *testing.T) {
+		_, bins, err := ParseSrc(`Сообщить("раз")
+СообщитьФормат("%d-%s", 2, "два")`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env := core.NewEnv()
+		w := &linesWriter{}
+		env.SetStdOut(w)
+		if _, err := Run(bins, env); err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Join(w.lines, "|"); got != "раз|2-два" || w.buf != "" {
+			t.Errorf("строки = %q, остаток %q", got, w.buf)
+		}
+	})
+}
+
+func TestOutputWriterPerInstance(t *testing.T) {
+	const src = `функция Вывести(имя, н)
+	СообщитьФормат("%%s %%d", имя, н)
+конецфункции
+для н = 1 по 500 цикл
+	Вывести("%s", н)
+конеццикла`
+
+	var wg sync.WaitGroup
+	outs := make([]bytes.Buffer, 2)
+	errs := make([]error, 2)
+	for i := range outs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, bins, err := ParseSrc(fmt.Sprintf(src, fmt.Sprint("вм", i)))
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			env := core.NewEnv()
+			env.SetStdOut(&outs[i])
+			_, errs[i] = Run(bins, env)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range outs {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+		lines := strings.Split(strings.TrimSuffix(outs[i].String(), "\n"), "\n")
+		if len(lines) != 500 {
+			t.Fatalf("вм%d: выведено %d строк, ожидалось 500", i, len(lines))
+		}
+		for n, l := range lines {
+			if want := fmt.Sprintf("вм%d %d", i, n+1); l != want {
+				t.Fatalf("вм%d: строка %d = %q, ожидалась %q", i, n+1, l, want)
+			}
+		}
+	}
+
+	t.Run("по умолчанию - стандартный вывод", func(t *testing.T) {
+		env := core.NewEnv()
+		if env.Stdout() != os.Stdout || env.NewEnv().Stdout() != os.Stdout {
+			t.Error("поток вывода по умолчанию не os.Stdout")
+		}
+		sub := env.NewSubEnv()
+		var out bytes.Buffer
+		env.SetStdOut(&out)
+		if sub.Stdout() != &out {
+			t.Error("вложенное окружение не выводит в поток, установленный позже")
+		}
+		env.SetStdOut(nil)
+		if sub.Stdout() != os.Stdout {
+			t.Error("после сброса потока вывод не возвращается в os.Stdout")
+		}
+	})
+}
+
+func TestReadLine(t *testing.T) {
+	run := func(t *testing.T, src, input string) string {
+		_, bins, err := ParseSrc(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env := core.NewEnv()
+		var out bytes.Buffer
+		env.SetStdOut(&out)
+		env.SetStdIn(bufio.NewReader(strings.NewReader(input)))
+		if _, err := Run(bins, env); err != nil {
+			t.Fatal(err)
+		}
+		return out.String()
+	}
+	const src = `для н = 1 по 5 цикл
+	с = ПрочитатьСтроку()
+	сообщить(НРег(ТипЗнч(с)), с, "|")
+конеццикла`
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "несколько строк и конец ввода",
+			input: "раз\nдва\n\nтри",
+			want:  "строка раз |\nстрока два |\nстрока  |\nстрока три |\nнеопределено Неопределено |\n",
+		},
+		{
+			name:  "пробелы в конце строки сохраняются",
+			input: "а  \t\nб \r\n",
+			want:  "строка а  \t |\nстрока б  |\nнеопределено Неопределено |\nнеопределено Неопределено |\nнеопределено Неопределено |\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// регистр имени типа зависит от того, где оно было зарегистрировано впервые, поэтому оно приводится к нижнему
+			if got := run(t, src, tt.input); got != tt.want {
+				t.Errorf("вывод = %q, ожидался %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeMaps(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "поверхностное слияние с заменой ключей",
+			src: `а = {"x": 1, "y": {"п": 1}}
+б = {"y": {"к": 2}, "z": 3}
+в = Слить(а, б, {"z": 4})
+сообщить(в, а, б)`,
+			want: `{"x":1,"y":{"к":2},"z":4} {"x":1,"y":{"п":1}} {"y":{"к":2},"z":3}` + "\n",
+		},
+		{
+			name: "глубокое слияние вложенных структур",
+			src: `а = {"x": 1, "y": {"п": 1, "к": {"г": 1}}}
+б = {"y": {"к": {"д": 2}, "р": 3}, "x": {"н": 5}}
+сообщить(СлитьГлубоко(а, б), а)`,
+			want: `{"x":{"н":5},"y":{"к":{"г":1,"д":2},"п":1,"р":3}} {"x":1,"y":{"к":{"г":1},"п":1}}` + "\n",
+		},
+		{
+			name: "слияние с пустой структурой",
+			src:  `сообщить(Слить({}, {"а": 1}), Слить({"а": 1}, {}), Слить(), СлитьГлубоко({}))`,
+			want: `{"а":1} {"а":1} {} {}` + "\n",
+		},
+		{
+			name:    "не структура",
+			src:     `Слить({"а": 1}, [1])`,
+			wantErr: "Требуется значение типа Структура",
+		},
+	})
+}
+
+func TestReverse(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "массив",
+			src: `а = [1, "два", [3]]
+сообщить(Перевернуть(а), а)`,
+			want: `[[3],"два",1] [1,"два",[3]]` + "\n",
+		},
+		{
+			name: "строки",
+			src:  `сообщить(Перевернуть("hello"), Перевернуть("Привет, мир"))`,
+			want: "olleh рим ,тевирП\n",
+		},
+		{
+			name: "пустые значения",
+			src:  `сообщить(Перевернуть([]), Перевернуть("") = "", Длина(Перевернуть("")))`,
+			want: "[] true 0\n",
+		},
+		{
+			name:    "не массив и не строка",
+			src:     `Перевернуть(1)`,
+			wantErr: "Значение должно иметь длину",
+		},
+	})
+}
+
+func TestUnique(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "простые значения",
+			src: `а = [1, "1", 1, истина, "а", истина, "а", 2, 1.0, 1.5, 1.50]
+сообщить(Уникальные(а), Длина(а))`,
+			want: `[1,"1",true,"а",2,"1.5"] 11` + "\n",
+		},
+		{
+			name: "порядок первых вхождений",
+			src:  `сообщить(Уникальные([3, 1, 3, 2, 1, 4]))`,
+			want: "[3,1,2,4]\n",
+		},
+		{
+			name: "вложенные массивы и структуры",
+			src:  `сообщить(Уникальные([[1, [2]], [1, [2]], [[2], 1], {"а": [1], "б": 2}, {"б": 2, "а": [1]}, [1, [2]], []]))`,
+			want: `[[1,[2]],[[2],1],{"а":[1],"б":2},[]]` + "\n",
+		},
+		{
+			name: "массивы с дробными числами",
+			src:  `сообщить(Уникальные([[1], [1.0], [1.5], [1.50], {"а": 1.0}, {"а": 1}]))`,
+			want: `[[1],["1.5"],{"а":"1.0"}]` + "\n",
+		},
+		{
+			name: "пустой массив",
+			src:  `сообщить(Уникальные([]))`,
+			want: "[]\n",
+		},
+		{
+			name:    "не массив",
+			src:     `Уникальные("аа")`,
+			wantErr: "Требуется значение типа Массив",
+		},
+	})
+}
+
+func TestZip(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "массивы одинаковой длины",
+			src: `для каждого п из Связать(["а", "б", "в"], [1, 2, 3]) цикл
+	сообщить(п[0], п[1])
+конеццикла`,
+			want: "а 1\nб 2\nв 3\n",
+		},
+		{
+			name: "разная длина обрезается по короткому",
+			src:  `сообщить(Связать([1, 2, 3], ["а"]), Связать([1], [4, 5, 6]), Связать([], [1]))`,
+			want: `[[1,"а"]] [[1,4]] []` + "\n",
+		},
+		{
+			name: "разъединение обратно",
+			src: `ключи, значения = Разъединить(Связать(["а", "б"], [1, [2]]))
+сообщить(ключи, значения)
+к, з = Разъединить([])
+сообщить(к, з)`,
+			want: `["а","б"] [1,[2]]` + "\n[] []\n",
+		},
+		{
+			name:    "не пара",
+			src:     `Разъединить([[1, 2], [3]])`,
+			wantErr: "Требуется массив из двух элементов",
+		},
+		{
+			name:    "не массив",
+			src:     `Связать([1], "а")`,
+			wantErr: "Требуется значение типа Массив",
+		},
+	})
+}
+
+func TestFlatten(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "один уровень",
+			src: `а = [1, [2, 3], [[4], 5], "с", []]
+сообщить(Развернуть(а), а)`,
+			want: `[1,2,3,[4],5,"с"] [1,[2,3],[[4],5],"с",[]]` + "\n",
+		},
+		{
+			name: "глубина",
+			src: `а = [1, [2, [3, [4, [5]]]], {"к": [6]}]
+сообщить(Развернуть(а, 2))
+сообщить(Развернуть(а, -1))
+сообщить(Развернуть(а, 0))`,
+			want: `[1,2,3,[4,[5]],{"к":[6]}]` + "\n" +
+				`[1,2,3,4,5,{"к":[6]}]` + "\n" +
+				`[1,[2,[3,[4,[5]]]],{"к":[6]}]` + "\n",
+		},
+		{
+			name: "плоский массив не меняется",
+			src:  `сообщить(Развернуть([1, "а", истина]), Развернуть([], -1))`,
+			want: `[1,"а",true] []` + "\n",
+		},
+		{
+			name:    "не массив",
+			src:     `Развернуть("а")`,
+			wantErr: "Требуется значение типа Массив",
+		},
+	})
+}
+
+func TestChunks(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "без остатка",
+			src:  `сообщить(Части([1, 2, 3, 4, 5, 6], 2), Части([1, 2, 3], 3))`,
+			want: "[[1,2],[3,4],[5,6]] [[1,2,3]]\n",
+		},
+		{
+			name: "с остатком",
+			src: `а = [1, 2, 3, 4, 5]
+ч = Части(а, 2)
+ч[0][0] = 9
+сообщить(ч, а)`,
+			want: "[[9,2],[3,4],[5]] [1,2,3,4,5]\n",
+		},
+		{
+			name: "размер больше массива",
+			src:  `сообщить(Части([1, 2], 5), Части([], 3), Части([1, 2, 3], 9223372036854775807), Части([], 9223372036854775807))`,
+			want: "[[1,2]] [] [[1,2,3]] []\n",
+		},
+		{
+			name:    "нулевой размер",
+			src:     `Части([1, 2], 0)`,
+			wantErr: "Размер должен быть больше нуля",
+		},
+		{
+			name:    "отрицательный размер",
+			src:     `Части([1, 2], -1)`,
+			wantErr: "Размер должен быть больше нуля",
+		},
+	})
+}
+
+func TestBinarySearch(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "значение найдено",
+			src: `а = [1, 3, 5, 7, 9, 11]
+сообщить(ДвоичныйПоиск(а, 1), ДвоичныйПоиск(а, 7), ДвоичныйПоиск(а, 11), ДвоичныйПоиск(["а", "б", "в"], "б"))`,
+			want: "0 3 5 1\n",
+		},
+		{
+			name: "позиция вставки",
+			src: `а = [1, 3, 5]
+сообщить(ДвоичныйПоиск(а, 0), ДвоичныйПоиск(а, 4), ДвоичныйПоиск(а, 6), ДвоичныйПоиск(а, 2.5))`,
+			want: "-1 -3 -4 -2\n",
+		},
+		{
+			name: "пустой массив",
+			src:  `сообщить(ДвоичныйПоиск([], 1))`,
+			want: "-1\n",
+		},
+		{
+			name:    "несравнимые значения",
+			src:     `ДвоичныйПоиск([1, 2], {"а": 1})`,
+			wantErr: "Операция между значениями невозможна",
+		},
+	})
+}
+
+func TestAggregates(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "сумма целых остается целой",
+			src: `с = Сумма([1, 2, 3, 4])
+сообщить(с, НРег(ТипЗнч(с)), Сумма([]))`,
+			want: "10 целоечисло 0\n",
+		},
+		{
+			name: "сумма с десятичным",
+			src: `с = Сумма([1, 2.5, 3])
+сообщить(с, НРег(ТипЗнч(с)))`,
+			want: "6.5 число\n",
+		},
+		{
+			name: "среднее",
+			src: `сообщить(Среднее([1, 2, 3, 4]), Среднее([2, 4]) = 3, Среднее([1.5]))
+с = Среднее([1, 2])
+сообщить(НРег(ТипЗнч(с)))`,
+			want: "2.5 true 1.5\nчисло\n",
+		},
+		{
+			name: "мин и макс массива",
+			src:  `сообщить(Макс([3, 7.5, 1]), Мин([3, 7.5, 1]), Макс(["б", "в", "а"]), Мин(2, 5))`,
+			want: "7.5 1 в 2\n",
+		},
+		{
+			name:    "не число",
+			src:     `Сумма([1, "2"])`,
+			wantErr: "Требуется значение типа Число",
+		},
+		{
+			name:    "среднее пустого массива",
+			src:     `Среднее([])`,
+			wantErr: "Массив не должен быть пустым",
+		},
+		{
+			name:    "макс пустого массива",
+			src:     `Макс([])`,
+			wantErr: "Массив не должен быть пустым",
+		},
+	})
+}
+
+func TestUTF8Builtins(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "правильная строка",
+			src: `с = "Привет, world"
+сообщить(ПравильныйUTF8(с), ИсправитьUTF8(с) = с, ПравильныйUTF8(""))`,
+			want: "true true true\n",
+		},
+		{
+			name:    "не строка",
+			src:     `ПравильныйUTF8(1)`,
+			wantErr: "Требуется значение типа Строка",
+		},
+	})
+
+	env := core.NewEnv()
+	env.DefineS("данные", core.VMString("да\xffнн\xd0ые\xc3\x28"))
+	got, err := runScriptEnv(`исправленные = ИсправитьUTF8(данные)
+сообщить(ПравильныйUTF8(данные), ПравильныйUTF8(исправленные), исправленные)`, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "false true да\uFFFDнн\uFFFDые\uFFFD(\n"; got != want {
+		t.Errorf("вывод = %q, ожидался %q", got, want)
+	}
+}
+
+func TestGzip(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "сжатие и распаковка",
+			src: `с = ""
+для н = 1 по 100 цикл
+	с = с + "Повторяющийся текст "
+конеццикла
+сж = Сжать(с)
+сообщить(Длина(сж) < Длина(с), Разжать(сж) = с, Разжать(Сжать("")) = "")`,
+			want: "true true true\n",
+		},
+		{
+			name: "поврежденные данные перехватываются",
+			src: `попытка
+	Разжать("это не gzip")
+исключение
+	сообщить("перехвачено", СтрСодержит(ОписаниеОшибки(), "не сжаты в формате gzip"))
+конецпопытки`,
+			want: "перехвачено true\n",
+		},
+	})
+}
+
+func TestURLEncoding(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "пробелы и кириллица",
+			src:  `сообщить(КодироватьURL("а б&в=1"), КодироватьURL("hello world"))`,
+			want: "%D0%B0+%D0%B1%26%D0%B2%3D1 hello+world\n",
+		},
+		{
+			name: "кодирование и декодирование",
+			src: `с = "Поиск: ключ=значение & 100%/путь?"
+сообщить(ДекодироватьURL(КодироватьURL(с)) = с, ДекодироватьURL("%D0%BC%D0%B8%D1%80+%D0%B8%20world"))`,
+			want: "true мир и world\n",
+		},
+		{
+			name: "неверная последовательность",
+			src: `попытка
+	ДекодироватьURL("а%ZZ")
+исключение
+	сообщить(СтрСодержит(ОписаниеОшибки(), "Неверная последовательность"), СтрСодержит(ОписаниеОшибки(), "%ZZ"))
+конецпопытки`,
+			want: "true true\n",
+		},
+	})
+}
+
+func TestLike(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{
+			name: "шаблоны",
+			src:  `сообщить(Подобно("Петров", "Пет%"), Подобно("кот", "к_т"), Подобно("скидка 10%", "%10\\%"), Подобно("Иванов", "Пет%"))`,
+			want: "true true true false\n",
+		},
+	})
+}
+
+func TestOptionalArgsCount(t *testing.T) {
+	runScriptTests(t, []scriptTest{
+		{name: "httpзапрос", src: `HTTPЗапрос("GET")`, wantErr: "Неверное количество параметров (требуется от 2 до 5)"},
+		{name: "развернуть", src: `Развернуть([1], 1, 2)`, wantErr: "Неверное количество параметров (требуется от 1 до 2)"},
+		{name: "макс", src: `Макс(1, 2, 3)`, wantErr: "Неверное количество параметров (требуется от 1 до 2)"},
+		{name: "повторять", src: `Повторять(1)`, wantErr: "Неверное количество параметров (требуется от 2 до 3)"},
+		{name: "шаблон", src: `Шаблон("а")`, wantErr: "Неверное количество параметров (требуется от 2 до 3)"},
+	})
+}
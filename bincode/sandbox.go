@@ -0,0 +1,66 @@
+package bincode
+
+import (
+	"sync"
+
+	"github.com/shinanca/gonec/core"
+	"github.com/shinanca/gonec/names"
+)
+
+// SandboxBuiltins - функции стандартной библиотеки, доступные коду в ВычислитьИзолированно по умолчанию.
+// Это функции без побочных эффектов: в список не входят ввод-вывод, сеть, переменные окружения,
+// горутины и исполнение другого кода
+var SandboxBuiltins = []string{
+	"длина", "диапазон", "текущаядата", "прошловременис", "хэш",
+	"длительностьнаносекунды", "длительностьмикросекунды", "длительностьмиллисекунды",
+	"длительностьсекунды", "длительностьминуты", "длительностьчаса", "длительностьдня",
+	"нрег", "врег", "стрсодержит", "стрсодержитлюбой", "стрколичество", "стрнайти", "стрнайтилюбой",
+	"стрнайтипоследний", "стрзаменить", "равнобезрегистра", "начинаетсяс", "заканчиваетсяна",
+	"содержитстр", "дополнитьслева", "дополнитьсправа", "повторить",
+	"окр", "масштаб", "разделить", "формат", "соединитьформат", "кодсимвола", "типзнч", "кактип",
+	"число", "шаблон", "макс", "мин", "преобразовать", "отфильтровать", "свернуть", "большоецелое",
+}
+
+var (
+	sandboxOnce sync.Once
+	sandboxLib  *core.Env // полная стандартная библиотека, из которой берутся разрешенные функции
+)
+
+// SandboxEval исполняет код в новом окружении, в котором определены только входные переменные inputs
+// и функции стандартной библиотеки из списка allowed. Переменные вызывающего кода недоступны.
+// Возвращаются все переменные верхнего уровня, оставшиеся после исполнения, включая входные.
+// Если передано окружение вызывающего кода caller, изолированный код наследует его контекст отмены,
+// счетчик инструкций и глубину вызовов
+func SandboxEval(caller *core.Env, src string, inputs core.VMStringMap, allowed []string) (core.VMStringMap, error) {
+	sandboxOnce.Do(func() {
+		sandboxLib = core.NewEnv()
+		core.LoadAllBuiltins(sandboxLib)
+	})
+
+	// глобальное окружение с разрешенными функциями, полная библиотека в нем не загружается
+	genv := core.NewEnv()
+	for _, name := range allowed {
+		id := names.UniqueNames.Set(name)
+		if v, err := sandboxLib.Get(id); err == nil {
+			genv.Define(id, v)
+		}
+	}
+	genv.SetBuiltsIsLoaded()
+	if caller != nil {
+		genv.InheritLimits(caller)
+	}
+
+	env := genv.NewSubEnv()
+	for k, v := range inputs {
+		env.DefineS(k, v)
+	}
+
+	_, bins, err := ParseSrc(src)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = Run(bins, env); err != nil {
+		return nil, err
+	}
+	return env.Locals(), nil
+}
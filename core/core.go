@@ -2,12 +2,18 @@
 package core
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"net/url"
 	"os"
 	"reflect"
 	"runtime"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/covrom/decnum"
 
@@ -15,6 +21,15 @@ import (
 	"github.com/shinanca/gonec/names"
 )
 
+// callerEnv возвращает окружение кода, вызвавшего встроенную функцию,
+// а при вызове из Go без окружения - окружение, в которое загружена библиотека
+func callerEnv(env *Env, envout *(*Env)) *Env {
+	if envout != nil && *envout != nil {
+		return *envout
+	}
+	return env
+}
+
 // LoadAllBuiltins is a convenience function that loads all defineSd builtins.
 func LoadAllBuiltins(env *Env) {
 	Import(env)
@@ -88,6 +103,10 @@ func Import(env *Env) *Env {
 		if min > max {
 			return VMErrorNeedLess
 		}
+		// разность считается без знака, чтобы не переполниться на крайних значениях
+		if uint64(max-min) >= MaxVMLen {
+			return VMErrorTooLarge
+		}
 		arr = make(VMSlice, max-min+1)
 
 		for i := min; i <= max; i++ {
@@ -103,6 +122,14 @@ func Import(env *Env) *Env {
 		return nil
 	}))
 
+	// НаносекундыМоно() возвращает показания монотонных часов в наносекундах от запуска программы.
+	// В отличие от ТекущаяДата не зависит от перевода системных часов, подходит для замера длительности
+	env.DefineS("наносекундымоно", VMFuncMustParams(0, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		rets.Append(VMInt(time.Since(monoStart)))
+		return nil
+	}))
+
 	env.DefineS("прошловременис", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
 		*envout = env
 		if rv, ok := args[0].(VMDateTimer); ok {
@@ -112,14 +139,115 @@ func Import(env *Env) *Env {
 		return VMErrorNeedDate
 	}))
 
+	// Пауза(длительность) приостанавливает только текущую горутину, остальные продолжают работу.
+	// Длительность можно указать и числом секунд (допустимо с дробной частью)
 	env.DefineS("пауза", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
 		*envout = env
-		if v, ok := args[0].(VMNumberer); ok {
-			sec1 := NewVMDecNumFromInt64(int64(VMSecond))
-			time.Sleep(time.Duration(v.DecNum().Mul(sec1).Int()))
-			return nil
+		d, err := durationOf(args[0])
+		if err != nil {
+			return err
 		}
-		return VMErrorNeedSeconds
+		time.Sleep(d)
+		return nil
+	}))
+
+	// Таймер(длительность) возвращает канал, в который по истечении длительности поступит текущее время.
+	// Используется для ограничения ожидания в операторе Выбор по каналам.
+	// Длительность можно указать и числом секунд, как в Пауза
+	env.DefineS("таймер", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		d, err := durationOf(args[0])
+		if err != nil {
+			return err
+		}
+		ch := make(VMChan, 1)
+		time.AfterFunc(d, func() {
+			// канал мог быть закрыт до срабатывания таймера
+			ch.SendErr(Now())
+		})
+		rets.Append(ch)
+		return nil
+	}))
+
+	// ЗакрытьКанал(канал) закрывает канал: отправка в него вызывает ошибку,
+	// а получение возвращает оставшиеся в буфере значения, затем Неопределено
+	env.DefineS("закрытьканал", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		if ch, ok := args[0].(VMChan); ok {
+			return ch.CloseErr()
+		}
+		return VMErrorNeedChan
+	}))
+
+	// Генератор(функция) запускает функцию в горутине, передав ей канал для отправки значений,
+	// и возвращает этот канал для чтения в цикле Для каждого. По завершении функции канал закрывается,
+	// а ее ошибка передается получателю и возникает при очередном чтении из канала.
+	// Если получатель прекращает чтение раньше, он закрывает канал - очередная отправка
+	// завершает функцию генератора. Цикл Для каждого по генератору закрывает канал сам при выходе из цикла
+	// до его окончания - через Прервать, Возврат или ошибку. Канал закрывается и при отмене исполнения
+	// вызвавшего кода, поэтому горутина генератора не остается висеть на канале, даже если получатель его не закрыл
+	env.DefineS("генератор", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		done := callerEnv(env, envout).Context().Done()
+		*envout = env
+		f, ok := args[0].(VMFunc)
+		if !ok {
+			return VMErrorNeedFunc
+		}
+		ch := make(VMChan)
+		finished := make(chan struct{})
+		if done != nil {
+			go func() {
+				select {
+				case <-done:
+					ch.CloseErr()
+				case <-finished:
+				}
+			}()
+		}
+		generators.Store(ch, struct{}{})
+		go func() {
+			defer generators.Delete(ch)
+			defer close(finished)
+			var e *Env
+			r := make(VMSlice, 0)
+			if err := f(VMSlice{ch}, &r, &e); err != nil {
+				// если канал уже закрыт получателем, ошибка отправки ожидаема и отбрасывается
+				ch.SendError(err)
+			}
+			ch.CloseErr()
+		}()
+		rets.Append(ch)
+		return nil
+	}))
+
+	// Ждать(будущее) ожидает завершения функции, запущенной через Старт, и возвращает ее результат,
+	// как будущее.Результат(). Для массива будущих возвращается массив результатов
+	env.DefineS("ждать", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		switch v := args[0].(type) {
+		case *VMFuture:
+			rv, err := v.Wait()
+			if err != nil {
+				return err
+			}
+			rets.Append(rv)
+		case VMSlice:
+			rv := make(VMSlice, len(v))
+			for i := range v {
+				f, ok := v[i].(*VMFuture)
+				if !ok {
+					return VMErrorNeedFuture
+				}
+				var err error
+				if rv[i], err = f.Wait(); err != nil {
+					return err
+				}
+			}
+			rets.Append(rv)
+		default:
+			return VMErrorNeedFuture
+		}
+		return nil
 	}))
 
 	env.DefineS("длительностьнаносекунды", VMNanosecond)
@@ -265,6 +393,353 @@ func Import(env *Env) *Env {
 		return VMErrorNeedString
 	}))
 
+	env.DefineS("равнобезрегистра", VMFuncMustParams(2, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		v1, ok1 := args[0].(VMStringer)
+		v2, ok2 := args[1].(VMStringer)
+		if ok1 && ok2 {
+			rets.Append(VMBool(strings.EqualFold(v1.String(), v2.String())))
+			return nil
+		}
+		return VMErrorNeedString
+	}))
+
+	env.DefineS("начинаетсяс", VMFuncMustParams(2, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		v1, ok1 := args[0].(VMStringer)
+		v2, ok2 := args[1].(VMStringer)
+		if ok1 && ok2 {
+			rets.Append(VMBool(StrHasPrefix(v1.String(), v2.String())))
+			return nil
+		}
+		return VMErrorNeedString
+	}))
+
+	env.DefineS("заканчиваетсяна", VMFuncMustParams(2, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		v1, ok1 := args[0].(VMStringer)
+		v2, ok2 := args[1].(VMStringer)
+		if ok1 && ok2 {
+			rets.Append(VMBool(StrHasSuffix(v1.String(), v2.String())))
+			return nil
+		}
+		return VMErrorNeedString
+	}))
+
+	env.DefineS("содержитстр", VMFuncMustParams(2, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		v1, ok1 := args[0].(VMStringer)
+		v2, ok2 := args[1].(VMStringer)
+		if ok1 && ok2 {
+			rets.Append(VMBool(StrContains(v1.String(), v2.String())))
+			return nil
+		}
+		return VMErrorNeedString
+	}))
+
+	// ПравильныйUTF8(строка) проверяет, что байты строки являются корректной последовательностью UTF-8.
+	// ИсправитьUTF8(строка) заменяет каждую неправильную последовательность байтов символом замены U+FFFD
+	env.DefineS("правильныйutf8", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		v, ok := args[0].(VMString)
+		if !ok {
+			return VMErrorNeedString
+		}
+		rets.Append(VMBool(utf8.ValidString(string(v))))
+		return nil
+	}))
+
+	env.DefineS("исправитьutf8", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		v, ok := args[0].(VMString)
+		if !ok {
+			return VMErrorNeedString
+		}
+		rets.Append(VMString(strings.ToValidUTF8(string(v), string(utf8.RuneError))))
+		return nil
+	}))
+
+	// Сжать(данные) возвращает строку с байтами данных, сжатыми в формате gzip, Разжать(данные) - исходные данные.
+	// Поврежденные данные вызывают исключение, которое можно перехватить в Попытка
+	env.DefineS("сжать", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		v, ok := args[0].(VMString)
+		if !ok {
+			return VMErrorNeedString
+		}
+		rv, err := GzipCompress([]byte(v))
+		if err != nil {
+			return err
+		}
+		rets.Append(VMString(rv))
+		return nil
+	}))
+
+	env.DefineS("разжать", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		v, ok := args[0].(VMString)
+		if !ok {
+			return VMErrorNeedString
+		}
+		rv, err := GzipDecompress([]byte(v))
+		if err != nil {
+			return err
+		}
+		rets.Append(VMString(rv))
+		return nil
+	}))
+
+	// КодироватьURL(строка) кодирует строку для подстановки в URL: пробел заменяется на +, остальные символы - на %XX.
+	// ДекодироватьURL(строка) выполняет обратное преобразование, неверная последовательность % вызывает исключение
+	env.DefineS("кодироватьurl", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		v, ok := args[0].(VMString)
+		if !ok {
+			return VMErrorNeedString
+		}
+		rets.Append(VMString(url.QueryEscape(string(v))))
+		return nil
+	}))
+
+	env.DefineS("декодироватьurl", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		v, ok := args[0].(VMString)
+		if !ok {
+			return VMErrorNeedString
+		}
+		rv, err := url.QueryUnescape(string(v))
+		if err != nil {
+			return fmt.Errorf("%w: %v", VMErrorURLEscape, err)
+		}
+		rets.Append(VMString(rv))
+		return nil
+	}))
+
+	// Подобно(строка, шаблон) проверяет строку по шаблону: % - любая последовательность символов, _ - один символ,
+	// \% и \_ обозначают сами символы
+	env.DefineS("подобно", VMFuncMustParams(2, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		v, ok := args[0].(VMString)
+		if !ok {
+			return VMErrorNeedString
+		}
+		p, ok := args[1].(VMString)
+		if !ok {
+			return VMErrorNeedString
+		}
+		rets.Append(VMBool(StrLike(string(v), string(p))))
+		return nil
+	}))
+
+	env.DefineS("дополнитьслева", VMFuncMustParams(3, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		v, ok := args[0].(VMStringer)
+		if !ok {
+			return VMErrorNeedString
+		}
+		n, ok := args[1].(VMInt)
+		if !ok {
+			return VMErrorNeedInt
+		}
+		f, ok := args[2].(VMStringer)
+		if !ok {
+			return VMErrorNeedString
+		}
+		fill := f.String()
+		r, sz := utf8.DecodeRuneInString(fill)
+		if sz == 0 || sz != len(fill) {
+			return VMErrorNeedSingleRune
+		}
+		rets.Append(VMString(StrPad(v.String(), int(n), r, true)))
+		return nil
+	}))
+
+	env.DefineS("дополнитьсправа", VMFuncMustParams(3, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		v, ok := args[0].(VMStringer)
+		if !ok {
+			return VMErrorNeedString
+		}
+		n, ok := args[1].(VMInt)
+		if !ok {
+			return VMErrorNeedInt
+		}
+		f, ok := args[2].(VMStringer)
+		if !ok {
+			return VMErrorNeedString
+		}
+		fill := f.String()
+		r, sz := utf8.DecodeRuneInString(fill)
+		if sz == 0 || sz != len(fill) {
+			return VMErrorNeedSingleRune
+		}
+		rets.Append(VMString(StrPad(v.String(), int(n), r, false)))
+		return nil
+	}))
+
+	// Уникальные(массив) возвращает новый массив без повторяющихся элементов в порядке первых вхождений
+	env.DefineS("уникальные", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		v, ok := args[0].(VMSlice)
+		if !ok {
+			return VMErrorNeedSlice
+		}
+		rets.Append(v.Unique())
+		return nil
+	}))
+
+	// Связать(массив1, массив2) возвращает массив пар [элемент1, элемент2], лишние элементы более длинного массива отбрасываются.
+	// Разъединить(пары) выполняет обратное преобразование и возвращает два массива: а, б = Разъединить(пары)
+	env.DefineS("связать", VMFuncMustParams(2, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		x, ok := args[0].(VMSlice)
+		if !ok {
+			return VMErrorNeedSlice
+		}
+		y, ok := args[1].(VMSlice)
+		if !ok {
+			return VMErrorNeedSlice
+		}
+		rets.Append(ZipVMSlices(x, y))
+		return nil
+	}))
+
+	env.DefineS("разъединить", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		v, ok := args[0].(VMSlice)
+		if !ok {
+			return VMErrorNeedSlice
+		}
+		x, y, err := UnzipVMSlice(v)
+		if err != nil {
+			return err
+		}
+		rets.Append(x)
+		rets.Append(y)
+		return nil
+	}))
+
+	// Развернуть(массив[, глубина]) заменяет вложенные массивы их элементами, по умолчанию на один уровень.
+	// Отрицательная глубина разворачивает массив полностью
+	env.DefineS("развернуть", VMFunc(func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		if len(args) != 1 && len(args) != 2 {
+			return VMErrorNeedArgsRange(1, 2)
+		}
+		sl, ok := args[0].(VMSlice)
+		if !ok {
+			return VMErrorNeedSlice
+		}
+		depth := VMInt(1)
+		if len(args) == 2 {
+			if depth, ok = args[1].(VMInt); !ok {
+				return VMErrorNeedInt
+			}
+		}
+		rets.Append(sl.Flatten(int(depth)))
+		return nil
+	}))
+
+	// Части(массив, размер) разбивает массив на массивы по размер элементов, последний может быть короче
+	env.DefineS("части", VMFuncMustParams(2, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		sl, ok := args[0].(VMSlice)
+		if !ok {
+			return VMErrorNeedSlice
+		}
+		n, ok := args[1].(VMInt)
+		if !ok {
+			return VMErrorNeedInt
+		}
+		rv, err := sl.Chunks(int(n))
+		if err != nil {
+			return err
+		}
+		rets.Append(rv)
+		return nil
+	}))
+
+	// ДвоичныйПоиск(массив, значение) ищет значение в массиве, отсортированном по возрастанию.
+	// Возвращает индекс элемента, а при его отсутствии -(позиция вставки)-1
+	env.DefineS("двоичныйпоиск", VMFuncMustParams(2, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		sl, ok := args[0].(VMSlice)
+		if !ok {
+			return VMErrorNeedSlice
+		}
+		i, err := sl.BinarySearch(args[1])
+		if err != nil {
+			return err
+		}
+		rets.Append(VMInt(i))
+		return nil
+	}))
+
+	// Перевернуть(значение) возвращает новый массив с элементами в обратном порядке
+	// или строку с символами в обратном порядке, исходное значение не изменяется
+	env.DefineS("перевернуть", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		switch v := args[0].(type) {
+		case VMSlice:
+			rv := make(VMSlice, len(v))
+			for i := range v {
+				rv[len(v)-1-i] = v[i]
+			}
+			rets.Append(rv)
+		case VMString:
+			rets.Append(VMString(StrReverse(string(v))))
+		default:
+			return VMErrorNeedLength
+		}
+		return nil
+	}))
+
+	env.DefineS("дополнитьнулями", VMFuncMustParams(2, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		v, ok := args[0].(VMInt)
+		if !ok {
+			return VMErrorNeedInt
+		}
+		n, ok := args[1].(VMInt)
+		if !ok {
+			return VMErrorNeedInt
+		}
+		s, err := v.ZeroPad(int(n))
+		if err != nil {
+			return err
+		}
+		rets.Append(VMString(s))
+		return nil
+	}))
+
+	env.DefineS("повторить", VMFuncMustParams(2, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		n, ok := args[1].(VMInt)
+		if !ok {
+			return VMErrorNeedInt
+		}
+		if n < 0 {
+			return VMErrorNegativeCount
+		}
+		switch v := args[0].(type) {
+		case VMSlice:
+			rv, err := v.Repeat(int(n))
+			if err != nil {
+				return err
+			}
+			rets.Append(rv)
+		case VMStringer:
+			s := v.String()
+			if err := checkRepeatLen(len(s), int(n)); err != nil {
+				return err
+			}
+			rets.Append(VMString(strings.Repeat(s, int(n))))
+		default:
+			return VMErrorNeedString
+		}
+		return nil
+	}))
+
 	env.DefineS("окр", VMFuncMustParams(2, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
 		*envout = env
 		v1, ok1 := args[0].(VMDecNum)
@@ -280,6 +755,68 @@ func Import(env *Env) *Env {
 		return nil
 	}))
 
+	// Экспонента(х), Логарифм(х) и Логарифм10(х) принимают целое или десятичное число и возвращают десятичное.
+	// Вычисляются с точностью float64, логарифм от неположительного числа вызывает исключение
+	decNumFunc := func(f func(VMDecNum) (VMDecNum, error)) VMFunc {
+		return VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+			*envout = env
+			v, ok := args[0].(VMNumberer)
+			if !ok {
+				return VMErrorNeedDecNum
+			}
+			rv, err := f(v.DecNum())
+			if err != nil {
+				return err
+			}
+			rets.Append(rv)
+			return nil
+		})
+	}
+	env.DefineS("экспонента", decNumFunc(VMDecNum.Exp))
+	env.DefineS("логарифм", decNumFunc(VMDecNum.Log))
+	env.DefineS("логарифм10", decNumFunc(VMDecNum.Log10))
+
+	// тригонометрические функции работают в радианах, Радианы(градусы) и Градусы(радианы) переводят углы.
+	// Аргумент вне области определения обратных функций вызывает исключение
+	floatFunc := func(f func(float64) float64) VMFunc {
+		return decNumFunc(func(x VMDecNum) (VMDecNum, error) {
+			return x.MathFunc(f)
+		})
+	}
+	env.DefineS("синус", floatFunc(math.Sin))
+	env.DefineS("косинус", floatFunc(math.Cos))
+	env.DefineS("тангенс", floatFunc(math.Tan))
+	env.DefineS("арксинус", floatFunc(math.Asin))
+	env.DefineS("арккосинус", floatFunc(math.Acos))
+	env.DefineS("арктангенс", floatFunc(math.Atan))
+	env.DefineS("радианы", floatFunc(func(x float64) float64 { return x * math.Pi / 180 }))
+	env.DefineS("градусы", floatFunc(func(x float64) float64 { return x * 180 / math.Pi }))
+
+	env.DefineS("масштаб", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		v, ok := args[0].(VMNumberer)
+		if !ok {
+			return VMErrorNeedDecNum
+		}
+		rets.Append(VMInt(v.DecNum().Scale()))
+		return nil
+	}))
+
+	env.DefineS("разделить", VMFuncMustParams(3, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		v1, ok1 := args[0].(VMNumberer)
+		v2, ok2 := args[1].(VMNumberer)
+		if !ok1 || !ok2 {
+			return VMErrorNeedDecNum
+		}
+		sc, ok := args[2].(VMInt)
+		if !ok {
+			return VMErrorNeedInt
+		}
+		rets.Append(v1.DecNum().DivScale(v2.DecNum(), int(sc)))
+		return nil
+	}))
+
 	env.DefineS("формат", VMFunc(func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
 		*envout = env
 		if len(args) < 2 {
@@ -293,6 +830,21 @@ func Import(env *Env) *Env {
 		return VMErrorNeedString
 	}))
 
+	env.DefineS("соединитьформат", VMFuncMustParams(3, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		sl, ok := args[0].(VMSlice)
+		if !ok {
+			return VMErrorNeedSlice
+		}
+		tpl, ok1 := args[1].(VMString)
+		sep, ok2 := args[2].(VMString)
+		if !ok1 || !ok2 {
+			return VMErrorNeedString
+		}
+		rets.Append(VMString(sl.JoinFormat(string(tpl), string(sep))))
+		return nil
+	}))
+
 	env.DefineS("кодсимвола", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
 		*envout = env
 		if v, ok := args[0].(VMStringer); ok {
@@ -317,29 +869,255 @@ func Import(env *Env) *Env {
 		return nil
 	}))
 
+	// ЭлементыМодуля(модуль) возвращает имена переменных и функций, определенных в модуле
+	env.DefineS("элементымодуля", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		m, ok := args[0].(*Env)
+		if !ok {
+			return VMErrorNeedModule
+		}
+		rets.Append(m.LocalNames())
+		return nil
+	}))
+
+	// КакТип(значение, "Тип") возвращает значение, если оно уже имеет указанный тип, иначе ошибка - приведение не выполняется
+	env.DefineS("кактип", VMFuncMustParams(2, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		name, ok := args[1].(VMString)
+		if !ok {
+			return VMErrorNeedString
+		}
+		ok, err := env.IsType(args[0], names.UniqueNames.Set(string(name)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return VMErrorNotType(string(name))
+		}
+		rets.Append(args[0])
+		return nil
+	}))
+
+	env.DefineS("отладка", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		rets.Append(VMString(DumpVMValue(env, args[0])))
+		return nil
+	}))
+
+	// Утверждать(условие, сообщение) для тестов на языке: при ложном условии выбрасывается исключение с сообщением,
+	// позиция ошибки - место вызова
+	env.DefineS("утверждать", VMFuncMustParams(2, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		cond, ok := args[0].(VMBooler)
+		if !ok {
+			return VMErrorNeedBool
+		}
+		msg, ok := args[1].(VMStringer)
+		if !ok {
+			return VMErrorNeedString
+		}
+		if !cond.Bool() {
+			return errors.New(msg.String())
+		}
+		return nil
+	}))
+
+	// Целое(значение) и Десятичное(значение) явно приводят числа, строки и булево к целому и десятичному числу.
+	// Дробная часть при приведении к целому отбрасывается. К строке приводит Строка(значение)
+	env.DefineS("целое", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		rv, err := ToVMInt(args[0])
+		if err != nil {
+			return err
+		}
+		rets.Append(rv)
+		return nil
+	}))
+
+	env.DefineS("десятичное", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		rv, err := ToVMDecNum(args[0])
+		if err != nil {
+			return err
+		}
+		rets.Append(rv)
+		return nil
+	}))
+
+	// Число(строка, разделитель) разбирает число с указанным разделителем дробной части ("," или "."),
+	// пропуская разделители разрядов. Вызов с одним параметром - это приведение типа Число(значение)
+	env.DefineS("число", VMFuncMustParams(2, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		s, ok := args[0].(VMString)
+		if !ok {
+			return VMErrorNeedString
+		}
+		sep, ok := args[1].(VMString)
+		if !ok {
+			return VMErrorNeedString
+		}
+		if utf8.RuneCountInString(string(sep)) != 1 {
+			return VMErrorNeedSingleRune
+		}
+		r, _ := utf8.DecodeRuneInString(string(sep))
+		v, err := ParseVMNumber(string(s), r)
+		if err != nil {
+			return err
+		}
+		rets.Append(v)
+		return nil
+	}))
+
+	// Шаблон(строка, структура, строго) подставляет значения вместо {имя}, строго - ошибка при отсутствии имени
+	env.DefineS("шаблон", VMFunc(func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		if len(args) != 2 && len(args) != 3 {
+			return VMErrorNeedArgsRange(2, 3)
+		}
+		tpl, ok := args[0].(VMString)
+		if !ok {
+			return VMErrorNeedString
+		}
+		vals, ok := args[1].(VMStringMap)
+		if !ok {
+			return VMErrorNeedMap
+		}
+		strict := false
+		if len(args) == 3 {
+			b, ok := args[2].(VMBool)
+			if !ok {
+				return VMErrorNeedBool
+			}
+			strict = bool(b)
+		}
+		s, err := StrTemplate(string(tpl), vals, strict)
+		if err != nil {
+			return err
+		}
+		rets.Append(VMString(s))
+		return nil
+	}))
+
+	// Макс(а, б) и Мин(а, б) работают для любых значений, сравнимых операциями > и <: чисел, строк, дат, длительностей.
+	// С одним параметром-массивом возвращают наибольший или наименьший его элемент
+	maxMinFunc := func(op VMOperation) VMFunc {
+		return func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+			*envout = env
+			var v VMValuer
+			var err error
+			switch len(args) {
+			case 1:
+				sl, ok := args[0].(VMSlice)
+				if !ok {
+					return VMErrorNeedSlice
+				}
+				v, err = MaxMinVMSlice(sl, op)
+			case 2:
+				v, err = MaxMinVMValues(args[0], args[1], op)
+			default:
+				return VMErrorNeedArgsRange(1, 2)
+			}
+			if err != nil {
+				return err
+			}
+			rets.Append(v)
+			return nil
+		}
+	}
+	env.DefineS("макс", VMFunc(maxMinFunc(GTR)))
+	env.DefineS("мин", VMFunc(maxMinFunc(LSS)))
+
+	// Сумма(массив) складывает целые и десятичные числа, сумма целых остается целой.
+	// Среднее(массив) возвращает среднее арифметическое как десятичное число
+	env.DefineS("сумма", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		sl, ok := args[0].(VMSlice)
+		if !ok {
+			return VMErrorNeedSlice
+		}
+		v, err := SumVMSlice(sl)
+		if err != nil {
+			return err
+		}
+		rets.Append(v)
+		return nil
+	}))
+
+	env.DefineS("среднее", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		sl, ok := args[0].(VMSlice)
+		if !ok {
+			return VMErrorNeedSlice
+		}
+		v, err := AvgVMSlice(sl)
+		if err != nil {
+			return err
+		}
+		rets.Append(v)
+		return nil
+	}))
+
+	// функции вывода пишут в поток окружения вызывающего кода, см. Env.SetStdOut
 	env.DefineS("сообщить", VMFunc(func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		out := callerEnv(env, envout)
 		*envout = env
 		if len(args) == 0 {
-			env.Println()
+			out.Println()
 			return nil
 		}
 		as := args.Args()
-		env.Println(as...)
+		out.Println(as...)
 		return nil
 	}))
 
 	env.DefineS("сообщитьф", VMFunc(func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		out := callerEnv(env, envout)
 		*envout = env
 		if len(args) < 2 {
 			return VMErrorNeedFormatAndArgs
 		}
 		if v, ok := args[0].(VMString); ok {
 			as := VMSlice(args[1:]).Args()
-			env.Printf(string(v), as...)
+			out.Printf(string(v), as...)
+			return nil
+		}
+		return VMErrorNeedString
+
+	}))
+
+	// СообщитьФормат(шаблон, аргументы...) выводит строку, отформатированную как в Формат, и перевод строки.
+	// Вывод, как и у Сообщить, идет в поток, установленный через Env.SetStdOut
+	env.DefineS("сообщитьформат", VMFunc(func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		out := callerEnv(env, envout)
+		*envout = env
+		if len(args) < 1 {
+			return VMErrorNoArgs
+		}
+		if v, ok := args[0].(VMString); ok {
+			as := VMSlice(args[1:]).Args()
+			out.Println(env.Sprintf(string(v), as...))
 			return nil
 		}
 		return VMErrorNeedString
+	}))
 
+	// ПрочитатьСтроку() читает строку из потока ввода окружения (см. Env.SetStdIn) и возвращает ее без перевода строки.
+	// Остальные пробельные символы сохраняются. В конце ввода возвращается Неопределено
+	env.DefineS("прочитатьстроку", VMFuncMustParams(0, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		in := callerEnv(env, envout).Stdin()
+		*envout = env
+		s, err := in.ReadString('\n')
+		if err != nil && (err != io.EOF || s == "") {
+			if err == io.EOF {
+				rets.Append(VMNil)
+				return nil
+			}
+			return err
+		}
+		s = strings.TrimSuffix(s, "\n")
+		s = strings.TrimSuffix(s, "\r")
+		rets.Append(VMString(s))
+		return nil
 	}))
 
 	env.DefineS("обработатьгорутины", VMFuncMustParams(0, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
@@ -359,6 +1137,432 @@ func Import(env *Env) *Env {
 		return VMErrorNeedSeconds
 	}))
 
+	env.DefineS("переменнаясреды", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		if v, ok := args[0].(VMString); ok {
+			if val, setted := os.LookupEnv(string(v)); setted {
+				rets.Append(VMString(val))
+			} else {
+				rets.Append(VMNullVar)
+			}
+			return nil
+		}
+		return VMErrorNeedString
+	}))
+
+	env.DefineS("аргументыкоманднойстроки", VMFuncMustParams(0, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		// аргументы передает запускающая программа в переменной АргументыЗапуска
+		if v, err := env.Get(names.UniqueNames.Set("аргументызапуска")); err == nil {
+			if sl, ok := v.(VMSlice); ok {
+				rets.Append(sl.CopyRecursive())
+				return nil
+			}
+		}
+		rets.Append(VMSlice{})
+		return nil
+	}))
+
+	env.DefineS("изxml", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		if v, ok := args[0].(VMString); ok {
+			rv, err := VMStringMapFromXML(string(v))
+			if err != nil {
+				return err
+			}
+			rets.Append(rv)
+			return nil
+		}
+		return VMErrorNeedString
+	}))
+
+	env.DefineS("вxml", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		if v, ok := args[0].(VMStringMap); ok {
+			rv, err := VMStringMapToXML(v)
+			if err != nil {
+				return err
+			}
+			rets.Append(VMString(rv))
+			return nil
+		}
+		return VMErrorNeedMap
+	}))
+
+	toJSONIndent := VMFuncMustParams(2, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		indent, ok := args[1].(VMString)
+		if !ok {
+			return VMErrorNeedString
+		}
+		b, err := json.MarshalIndent(args[0], "", string(indent))
+		if err != nil {
+			return err
+		}
+		rets.Append(VMString(b))
+		return nil
+	})
+	env.DefineS("вЈсонкрасиво", toJSONIndent) // Ј не переводится в нижний регистр при поиске имен
+	env.DefineS("вjsonкрасиво", toJSONIndent) // написание с латиницей
+
+	env.DefineS("httpзапрос", VMFunc(func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		// метод, урл, [заголовки], [тело], [таймаут]
+		if len(args) < 2 || len(args) > 5 {
+			return VMErrorNeedArgsRange(2, 5)
+		}
+		meth, ok := args[0].(VMString)
+		if !ok {
+			return VMErrorNeedString
+		}
+		urlstr, ok := args[1].(VMString)
+		if !ok {
+			return VMErrorNeedString
+		}
+		var h VMStringMap
+		if len(args) > 2 && args[2] != VMNil {
+			if h, ok = args[2].(VMStringMap); !ok {
+				return VMErrorNeedMap
+			}
+		}
+		var b VMString
+		if len(args) > 3 && args[3] != VMNil {
+			if b, ok = args[3].(VMString); !ok {
+				return VMErrorNeedString
+			}
+		}
+		timeout := HTTPRequestTimeout
+		if len(args) > 4 {
+			switch v := args[4].(type) {
+			case VMTimeDuration:
+				timeout = time.Duration(v)
+			case VMNumberer:
+				timeout = time.Duration(v.DecNum().Duration())
+			default:
+				return VMErrorNeedDuration
+			}
+		}
+		rv, err := HTTPRequest(string(meth), string(urlstr), h, string(b), timeout)
+		if err != nil {
+			return err
+		}
+		rets.Append(rv)
+		return nil
+	}))
+
+	env.DefineS("преобразовать", VMFuncMustParams(2, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		caller := callerEnv(env, envout)
+		*envout = env
+		sl, ok := args[0].(VMSlice)
+		if !ok {
+			return VMErrorNeedSlice
+		}
+		f, ok := args[1].(VMFunc)
+		if !ok {
+			return VMErrorNeedFunc
+		}
+		rv, err := MapVMSlice(caller, sl, f)
+		if err != nil {
+			return err
+		}
+		rets.Append(rv)
+		return nil
+	}))
+
+	env.DefineS("отфильтровать", VMFuncMustParams(2, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		caller := callerEnv(env, envout)
+		*envout = env
+		sl, ok := args[0].(VMSlice)
+		if !ok {
+			return VMErrorNeedSlice
+		}
+		f, ok := args[1].(VMFunc)
+		if !ok {
+			return VMErrorNeedFunc
+		}
+		rv, err := FilterVMSlice(caller, sl, f)
+		if err != nil {
+			return err
+		}
+		rets.Append(rv)
+		return nil
+	}))
+
+	env.DefineS("свернуть", VMFuncMustParams(3, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		caller := callerEnv(env, envout)
+		*envout = env
+		sl, ok := args[0].(VMSlice)
+		if !ok {
+			return VMErrorNeedSlice
+		}
+		f, ok := args[2].(VMFunc)
+		if !ok {
+			return VMErrorNeedFunc
+		}
+		rv, err := FoldVMSlice(caller, sl, args[1], f)
+		if err != nil {
+			return err
+		}
+		rets.Append(rv)
+		return nil
+	}))
+
+	// Взять(массив, n) возвращает первые n элементов, Отбросить(массив, n) - элементы после первых n.
+	// Если n больше длины массива, берется весь массив
+	takeFunc := func(drop bool) VMFunc {
+		return VMFuncMustParams(2, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+			*envout = env
+			sl, ok := args[0].(VMSlice)
+			if !ok {
+				return VMErrorNeedSlice
+			}
+			n, ok := args[1].(VMInt)
+			if !ok {
+				return VMErrorNeedInt
+			}
+			if n < 0 {
+				return VMErrorNegativeCount
+			}
+			if int64(n) > int64(len(sl)) {
+				n = VMInt(len(sl))
+			}
+			if drop {
+				rets.Append(append(VMSlice{}, sl[n:]...))
+			} else {
+				rets.Append(append(VMSlice{}, sl[:n]...))
+			}
+			return nil
+		})
+	}
+	env.DefineS("взять", takeFunc(false))
+	env.DefineS("отбросить", takeFunc(true))
+
+	// ВзятьПока(массив, предикат) возвращает начальные элементы, пока предикат возвращает Истина,
+	// ОтброситьПока(массив, предикат) - элементы, начиная с первого, для которого предикат вернул Ложь
+	takeWhileFunc := func(drop bool) VMFunc {
+		return VMFuncMustParams(2, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+			caller := callerEnv(env, envout)
+			*envout = env
+			sl, ok := args[0].(VMSlice)
+			if !ok {
+				return VMErrorNeedSlice
+			}
+			f, ok := args[1].(VMFunc)
+			if !ok {
+				return VMErrorNeedFunc
+			}
+			rv, err := TakeWhileVMSlice(caller, sl, f, drop)
+			if err != nil {
+				return err
+			}
+			rets.Append(rv)
+			return nil
+		})
+	}
+	env.DefineS("взятьпока", takeWhileFunc(false))
+	env.DefineS("отброситьпока", takeWhileFunc(true))
+
+	// Повторять(разы, функция[, пауза]) вызывает функцию, пока она не завершится без исключения, но не более указанного числа раз,
+	// и возвращает ее результат. Если все попытки завершились исключением, вызывается исключение последней попытки.
+	// Пауза между попытками указывается длительностью или числом секунд, как в Пауза
+	env.DefineS("повторять", VMFunc(func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		caller := callerEnv(env, envout)
+		*envout = env
+		if len(args) != 2 && len(args) != 3 {
+			return VMErrorNeedArgsRange(2, 3)
+		}
+		n, ok := args[0].(VMInt)
+		if !ok {
+			return VMErrorNeedInt
+		}
+		if n <= 0 {
+			return VMErrorNonPositiveCount
+		}
+		f, ok := args[1].(VMFunc)
+		if !ok {
+			return VMErrorNeedFunc
+		}
+		var d time.Duration
+		if len(args) == 3 {
+			var err error
+			if d, err = durationOf(args[2]); err != nil {
+				return err
+			}
+		}
+		rv, err := RetryVMFunc(caller, int(n), d, f)
+		if err != nil {
+			return err
+		}
+		rets.Append(rv)
+		return nil
+	}))
+
+	// ЧастичноеПрименение(функция, аргументы...) возвращает функцию, в которой первые аргументы исходной функции уже заданы:
+	// добавить5 = ЧастичноеПрименение(Сложить, 5), добавить5(1) равносильно Сложить(5, 1)
+	env.DefineS("частичноеприменение", VMFunc(func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		if len(args) < 1 {
+			return VMErrorNoArgs
+		}
+		f, ok := args[0].(VMFunc)
+		if !ok {
+			return VMErrorNeedFunc
+		}
+		rets.Append(PartialVMFunc(f, args[1:]))
+		return nil
+	}))
+
+	// Композиция(ф1, ф2, ..., фN) возвращает функцию, применяющую переданные функции справа налево:
+	// Композиция(ф, г)(х) равносильно ф(г(х)). Композиция одной функции ведет себя как сама функция
+	env.DefineS("композиция", VMFunc(func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		if len(args) < 1 {
+			return VMErrorNoArgs
+		}
+		fs := make([]VMFunc, len(args))
+		for i := range args {
+			f, ok := args[i].(VMFunc)
+			if !ok {
+				return VMErrorNeedFunc
+			}
+			fs[i] = f
+		}
+		rets.Append(ComposeVMFuncs(fs))
+		return nil
+	}))
+
+	// ОграничитьЧастоту(функция, интервал) возвращает функцию, которая вызывает исходную не чаще одного раза за интервал.
+	// Вызовы внутри интервала отбрасываются и возвращают Неопределено. Интервал задается длительностью или числом секунд
+	env.DefineS("ограничитьчастоту", VMFuncMustParams(2, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		f, ok := args[0].(VMFunc)
+		if !ok {
+			return VMErrorNeedFunc
+		}
+		d, err := durationOf(args[1])
+		if err != nil {
+			return err
+		}
+		rets.Append(ThrottleVMFunc(f, d))
+		return nil
+	}))
+
+	// Запомнить(функция) возвращает функцию, которая вызывает исходную один раз для каждого набора аргументов,
+	// а при повторных вызовах с равными аргументами возвращает запомненный результат
+	env.DefineS("запомнить", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		f, ok := args[0].(VMFunc)
+		if !ok {
+			return VMErrorNeedFunc
+		}
+		rets.Append(MemoizeVMFunc(f))
+		return nil
+	}))
+
+	// Сгруппировать(массив, функцияКлюча) возвращает структуру, где каждому ключу соответствует массив элементов с этим ключом
+	env.DefineS("сгруппировать", VMFuncMustParams(2, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		caller := callerEnv(env, envout)
+		*envout = env
+		sl, ok := args[0].(VMSlice)
+		if !ok {
+			return VMErrorNeedSlice
+		}
+		f, ok := args[1].(VMFunc)
+		if !ok {
+			return VMErrorNeedFunc
+		}
+		rv, err := GroupVMSlice(caller, sl, f)
+		if err != nil {
+			return err
+		}
+		rets.Append(rv)
+		return nil
+	}))
+
+	env.DefineS("большоецелое", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		switch v := args[0].(type) {
+		case VMString:
+			rv, err := ParseVMBigInt(string(v))
+			if err != nil {
+				return err
+			}
+			rets.Append(rv)
+		case VMInt:
+			rets.Append(NewVMBigIntFromInt64(int64(v)))
+		case VMBigInt:
+			rets.Append(v)
+		default:
+			return VMErrorNeedInt
+		}
+		return nil
+	}))
+
+	// Множество(элементы...) создает множество, повторяющиеся элементы добавляются один раз
+	env.DefineS("множество", VMFunc(func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		rv, err := NewVMSet(args...)
+		if err != nil {
+			return err
+		}
+		rets.Append(rv)
+		return nil
+	}))
+
+	// УпорядоченнаяСтруктура([структура или строка JSON]) создает структуру, сохраняющую порядок добавления ключей.
+	// Из строки JSON порядок ключей сохраняется, из обычной структуры ключи добавляются по возрастанию
+	env.DefineS("упорядоченнаяструктура", VMFunc(func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		if len(args) == 0 {
+			rets.Append(NewVMOrderedMap())
+			return nil
+		}
+		if len(args) > 1 {
+			return VMErrorNeedArgsRange(0, 1)
+		}
+		switch v := args[0].(type) {
+		case VMString:
+			rv, err := VMOrderedMapFromJson(string(v))
+			if err != nil {
+				return err
+			}
+			rets.Append(rv)
+		case VMStringMap:
+			rets.Append(NewVMOrderedMapFromStringMap(v))
+		default:
+			return VMErrorNeedMap
+		}
+		return nil
+	}))
+
+	// Заморозить(значение) возвращает неизменяемое представление массива или структуры.
+	// Заморозка глубокая, вложенные массивы и структуры тоже нельзя изменить; прочие значения возвращаются как есть
+	env.DefineS("заморозить", VMFuncMustParams(1, func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		*envout = env
+		rets.Append(Freeze(args[0]))
+		return nil
+	}))
+
+	// Слить(структура1, структура2, ...) возвращает новую структуру, значения более поздних структур заменяют более ранние.
+	// СлитьГлубоко дополнительно сливает вложенные структуры с одинаковыми ключами
+	mergeFunc := func(deep bool) VMFunc {
+		return func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+			*envout = env
+			maps := make([]VMStringMap, len(args))
+			for i := range args {
+				m, ok := args[i].(VMStringMap)
+				if !ok {
+					return VMErrorNeedMap
+				}
+				maps[i] = m
+			}
+			rets.Append(MergeVMStringMaps(deep, maps...))
+			return nil
+		}
+	}
+	env.DefineS("слить", mergeFunc(false))
+	env.DefineS("слитьглубоко", mergeFunc(true))
+
 	// при изменении состава типов не забывать изменять их и в lexer.go
 	env.DefineTypeS("целоечисло", ReflectVMInt)
 	env.DefineTypeS("число", ReflectVMDecNum)
@@ -368,6 +1572,11 @@ func Import(env *Env) *Env {
 	env.DefineTypeS("структура", ReflectVMStringMap)
 	env.DefineTypeS("дата", ReflectVMTime)
 	env.DefineTypeS("длительность", ReflectVMTimeDuration)
+	env.DefineTypeS("большоецелое", ReflectVMBigInt)
+	env.DefineTypeS("множество", ReflectVMSet)
+	env.DefineTypeS("упорядоченнаяструктура", ReflectVMOrderedMap)
+	env.DefineTypeS("замороженное", ReflectVMFrozen)
+	env.DefineTypeS("будущее", ReflectVMFuture)
 
 	env.DefineTypeS("группаожидания", ReflectVMWaitGroup)
 	env.DefineTypeS("файловаябазаданных", ReflectVMBoltDB)
@@ -0,0 +1,207 @@
+package core
+
+import (
+	"math/big"
+	"reflect"
+)
+
+// VMBigInt целое число произвольной длины (math/big), для криптографии и больших вычислений.
+// Значение неизменяемое: каждая операция создает новое число.
+// В операциях с VMInt целое число приводится к VMBigInt, с VMDecNum - большое целое приводится к VMDecNum.
+// Деление целочисленное, с отбрасыванием дробной части.
+type VMBigInt struct {
+	n *big.Int
+}
+
+var ReflectVMBigInt = reflect.TypeOf(VMBigInt{})
+
+// maxVMBigIntBits ограничивает размер результата сдвига влево и возведения в степень,
+// чтобы огромный показатель не приводил к неограниченному выделению памяти
+const maxVMBigIntBits = 1 << 24
+
+func NewVMBigInt(n *big.Int) VMBigInt {
+	return VMBigInt{n: n}
+}
+
+func NewVMBigIntFromInt64(x int64) VMBigInt {
+	return VMBigInt{n: big.NewInt(x)}
+}
+
+// ParseVMBigInt разбирает десятичную запись числа, допускаются префиксы 0x, 0o, 0b
+func ParseVMBigInt(s string) (VMBigInt, error) {
+	n, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return VMBigInt{}, VMErrorBigIntFormat
+	}
+	return VMBigInt{n: n}, nil
+}
+
+func (x VMBigInt) vmval() {}
+
+func (x VMBigInt) Interface() interface{} {
+	return new(big.Int).Set(x.big())
+}
+
+// big возвращает значение, нулевое для неинициализированного VMBigInt
+func (x VMBigInt) big() *big.Int {
+	if x.n == nil {
+		return new(big.Int)
+	}
+	return x.n
+}
+
+func (x VMBigInt) String() string {
+	return x.big().String()
+}
+
+// Int возвращает младшие 64 бита числа
+func (x VMBigInt) Int() int64 {
+	return x.big().Int64()
+}
+
+func (x VMBigInt) Float() float64 {
+	f, _ := new(big.Float).SetInt(x.big()).Float64()
+	return f
+}
+
+// DecNum возвращает число с точностью до 34 значащих цифр
+func (x VMBigInt) DecNum() VMDecNum {
+	d, err := ParseVMDecNum(x.String())
+	if err != nil {
+		return VMDecNumZero
+	}
+	return d
+}
+
+func (x VMBigInt) InvokeNumber() (VMNumberer, error) {
+	return x, nil
+}
+
+func (x VMBigInt) Bool() bool {
+	return x.big().Sign() > 0
+}
+
+func (x VMBigInt) EvalUnOp(op rune) (VMValuer, error) {
+	switch op {
+	case '-':
+		return VMBigInt{n: new(big.Int).Neg(x.big())}, nil
+	case '^':
+		return VMBigInt{n: new(big.Int).Not(x.big())}, nil
+	case '!':
+		return VMBool(!x.Bool()), nil
+	default:
+		return VMNil, VMErrorUnknownOperation
+	}
+}
+
+func (x VMBigInt) EvalBinOp(op VMOperation, y VMOperationer) (VMValuer, error) {
+	var yb *big.Int
+	switch yy := y.(type) {
+	case VMBigInt:
+		yb = yy.big()
+	case VMInt:
+		yb = big.NewInt(int64(yy))
+	case VMDecNum:
+		return x.DecNum().EvalBinOp(op, yy)
+	default:
+		return VMNil, VMErrorIncorrectOperation
+	}
+	xb := x.big()
+	switch op {
+	case ADD:
+		return VMBigInt{n: new(big.Int).Add(xb, yb)}, nil
+	case SUB:
+		return VMBigInt{n: new(big.Int).Sub(xb, yb)}, nil
+	case MUL:
+		return VMBigInt{n: new(big.Int).Mul(xb, yb)}, nil
+	case QUO:
+		if yb.Sign() == 0 {
+			return VMNil, VMErrorDivisionByZero
+		}
+		return VMBigInt{n: new(big.Int).Quo(xb, yb)}, nil
+	case REM:
+		if yb.Sign() == 0 {
+			return VMNil, VMErrorDivisionByZero
+		}
+		return VMBigInt{n: new(big.Int).Rem(xb, yb)}, nil
+	case POW:
+		if yb.Sign() < 0 {
+			return VMNil, VMErrorNegativeExponent
+		}
+		// модуль основания больше 1 дает не меньше (BitLen-1)*y бит результата
+		if xb.CmpAbs(big.NewInt(1)) > 0 && (!yb.IsInt64() || yb.Int64() > maxVMBigIntBits/int64(xb.BitLen()-1)) {
+			return VMNil, VMErrorBigIntTooLarge
+		}
+		return VMBigInt{n: new(big.Int).Exp(xb, yb, nil)}, nil
+	case EQL:
+		return VMBool(xb.Cmp(yb) == 0), nil
+	case NEQ:
+		return VMBool(xb.Cmp(yb) != 0), nil
+	case GTR:
+		return VMBool(xb.Cmp(yb) > 0), nil
+	case GEQ:
+		return VMBool(xb.Cmp(yb) >= 0), nil
+	case LSS:
+		return VMBool(xb.Cmp(yb) < 0), nil
+	case LEQ:
+		return VMBool(xb.Cmp(yb) <= 0), nil
+	case OR:
+		return VMBigInt{n: new(big.Int).Or(xb, yb)}, nil
+	case AND:
+		return VMBigInt{n: new(big.Int).And(xb, yb)}, nil
+	case SHR:
+		if yb.Sign() < 0 {
+			return VMNil, VMErrorNegativeShift
+		}
+		// сдвиг вправо на длину числа и больше дает 0 или -1
+		n := uint(xb.BitLen() + 1)
+		if yb.IsUint64() && yb.Uint64() < uint64(n) {
+			n = uint(yb.Uint64())
+		}
+		return VMBigInt{n: new(big.Int).Rsh(xb, n)}, nil
+	case SHL:
+		if yb.Sign() < 0 {
+			return VMNil, VMErrorNegativeShift
+		}
+		if !yb.IsInt64() || yb.Int64() > maxVMBigIntBits-int64(xb.BitLen()) {
+			return VMNil, VMErrorBigIntTooLarge
+		}
+		return VMBigInt{n: new(big.Int).Lsh(xb, uint(yb.Uint64()))}, nil
+	case LOR, LAND:
+		return VMNil, VMErrorIncorrectOperation
+	}
+	return VMNil, VMErrorUnknownOperation
+}
+
+func (x VMBigInt) ConvertToType(nt reflect.Type) (VMValuer, error) {
+	switch nt {
+	case ReflectVMBigInt:
+		return x, nil
+	case ReflectVMString:
+		return VMString(x.String()), nil
+	case ReflectVMInt:
+		if !x.big().IsInt64() {
+			return VMNil, VMErrorIntOverflow
+		}
+		return VMInt(x.Int()), nil
+	case ReflectVMDecNum:
+		return x.DecNum(), nil
+	case ReflectVMBool:
+		// при явном приведении ложь только для нуля
+		return VMBool(x.big().Sign() != 0), nil
+	}
+	return VMNil, VMErrorNotConverted
+}
+
+func (x VMBigInt) MarshalText() ([]byte, error) {
+	return x.big().MarshalText()
+}
+
+func (x *VMBigInt) UnmarshalText(data []byte) error {
+	n := new(big.Int)
+	if err := n.UnmarshalText(data); err != nil {
+		return err
+	}
+	x.n = n
+	return nil
+}
@@ -0,0 +1,75 @@
+package core
+
+import "testing"
+
+func TestVMBigInt(t *testing.T) {
+	big := func(s string) VMBigInt {
+		v, err := ParseVMBigInt(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	tests := []struct {
+		name string
+		x    VMOperationer
+		op   VMOperation
+		y    VMOperationer
+		want string
+	}{
+		{name: "сложение с переполнением int64", x: big("9223372036854775807"), op: ADD, y: VMInt(1), want: "9223372036854775808"},
+		{name: "целое слева", x: VMInt(2), op: MUL, y: big("9223372036854775807"), want: "18446744073709551614"},
+		{name: "вычитание", x: big("100000000000000000000"), op: SUB, y: big("1"), want: "99999999999999999999"},
+		{name: "деление", x: big("100000000000000000001"), op: QUO, y: VMInt(10), want: "10000000000000000000"},
+		{name: "остаток", x: big("100000000000000000001"), op: REM, y: VMInt(7), want: "3"},
+		{name: "степень", x: big("2"), op: POW, y: VMInt(100), want: "1267650600228229401496703205376"},
+		{name: "равенство", x: big("123"), op: EQL, y: VMInt(123), want: "true"},
+		{name: "равенство с целым слева", x: VMInt(124), op: EQL, y: big("123"), want: "false"},
+		{name: "сравнение", x: big("-5"), op: LSS, y: VMInt(-4), want: "true"},
+		{name: "с числом", x: big("10"), op: QUO, y: VMDecNumOne.Add(VMDecNumOne).Add(VMDecNumOne).Add(VMDecNumOne), want: "2.5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.x.EvalBinOp(tt.op, tt.y)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := got.(VMStringer).String(); s != tt.want {
+				t.Errorf("результат = %s, ожидался %s", s, tt.want)
+			}
+		})
+	}
+
+	if _, err := big("1").EvalBinOp(QUO, VMInt(0)); err != VMErrorDivisionByZero {
+		t.Errorf("ошибка = %v, ожидалось деление на ноль", err)
+	}
+	if _, err := big("1").EvalBinOp(SHL, VMInt(10000000000000)); err != VMErrorBigIntTooLarge {
+		t.Errorf("ошибка = %v, ожидалось слишком большое число", err)
+	}
+	if _, err := big("1").EvalBinOp(SHL, VMInt(-1)); err != VMErrorNegativeShift {
+		t.Errorf("ошибка = %v, ожидался отрицательный сдвиг", err)
+	}
+	if _, err := big("2").EvalBinOp(POW, VMInt(10000000000000)); err != VMErrorBigIntTooLarge {
+		t.Errorf("ошибка = %v, ожидалось слишком большое число", err)
+	}
+	for _, tt := range []struct {
+		op   VMOperation
+		x, y string
+		want string
+	}{
+		{op: SHR, x: "-5", y: "10000000000000000000000", want: "-1"},
+		{op: SHR, x: "1024", y: "3", want: "128"},
+		{op: SHL, x: "1", y: "100", want: "1267650600228229401496703205376"},
+		{op: POW, x: "-1", y: "10000000000000000000001", want: "-1"},
+	} {
+		got, err := big(tt.x).EvalBinOp(tt.op, big(tt.y))
+		if err != nil {
+			t.Errorf("%s %v %s: %v", tt.x, tt.op, tt.y, err)
+		} else if got.(VMBigInt).String() != tt.want {
+			t.Errorf("%s %v %s = %v, ожидалось %s", tt.x, tt.op, tt.y, got, tt.want)
+		}
+	}
+	if _, err := ParseVMBigInt("12а"); err != VMErrorBigIntFormat {
+		t.Errorf("ошибка = %v, ожидалась ошибка формата", err)
+	}
+}
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"sync"
+
 	"github.com/shinanca/gonec/names"
 )
 
@@ -45,6 +47,58 @@ func (x VMChan) TryRecv() (v VMValuer, ok bool, notready bool) {
 
 func (x VMChan) Close() { close(x) }
 
+// SendErr отправляет значение в канал, возвращая ошибку вместо паники, если канал закрыт
+func (x VMChan) SendErr(v VMValuer) (err error) {
+	defer func() {
+		if recover() != nil {
+			err = VMErrorChanClosed
+		}
+	}()
+	x <- v
+	return nil
+}
+
+// CloseErr закрывает канал, возвращая ошибку при повторном закрытии
+func (x VMChan) CloseErr() (err error) {
+	defer func() {
+		if recover() != nil {
+			err = VMErrorChanClosed
+		}
+	}()
+	close(x)
+	return nil
+}
+
+// vmChanError - ошибка отправителя, переданная получателю через канал вместо значения
+type vmChanError struct {
+	err error
+}
+
+func (x vmChanError) vmval() {}
+
+// SendError передает получателю ошибку отправителя: очередное получение из канала вызовет ее вместо значения.
+// Если канал уже закрыт получателем, ошибка отбрасывается
+func (x VMChan) SendError(err error) {
+	x.SendErr(vmChanError{err})
+}
+
+// SenderError возвращает ошибку отправителя, если полученное из канала значение передает ее
+func SenderError(v VMValuer) error {
+	if e, ok := v.(vmChanError); ok {
+		return e.err
+	}
+	return nil
+}
+
+// generators - каналы, созданные функцией Генератор, горутина которой еще не завершилась
+var generators sync.Map
+
+// IsGenerator возвращает true, если канал создан функцией Генератор и ее горутина еще работает
+func IsGenerator(x VMChan) bool {
+	_, ok := generators.Load(x)
+	return ok
+}
+
 func (x VMChan) Size() int { return cap(x) }
 
 func (x VMChan) MethodMember(name int) (VMFunc, bool) {
@@ -55,13 +109,29 @@ func (x VMChan) MethodMember(name int) (VMFunc, bool) {
 		return VMFuncMustParams(0, x.Закрыть), true
 	case "размер":
 		return VMFuncMustParams(0, x.Размер), true
+	case "получить":
+		return VMFuncMustParams(0, x.Получить), true
 		// TODO: подключить соединение
 	}
 	return nil, false
 }
 
 func (x VMChan) Закрыть(args VMSlice, rets *VMSlice, envout *(*Env)) error {
-	x.Close()
+	return x.CloseErr()
+}
+
+// Получить возвращает очередное значение и признак того, что оно получено из открытого канала.
+// Из закрытого и опустошенного канала возвращается Неопределено и Ложь
+func (x VMChan) Получить(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+	v, ok := x.Recv()
+	if err := SenderError(v); err != nil {
+		return err
+	}
+	if !ok {
+		v = VMNil
+	}
+	rets.Append(v)
+	rets.Append(VMBool(ok))
 	return nil
 }
 
@@ -3,10 +3,12 @@ package core
 import (
 	"bytes"
 	"compress/flate"
+	"compress/gzip"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
 	"crypto/tls"
+	"fmt"
 	"io"
 	"sync"
 )
@@ -133,3 +135,34 @@ func UnGZip(src []byte) ([]byte, error) {
 	}
 	return bo.Bytes(), nil
 }
+
+// GzipCompress сжимает данные в формат gzip с заголовком и контрольной суммой,
+// в отличие от GZip, который сжимает без заголовка для передачи по соединениям
+func GzipCompress(src []byte) ([]byte, error) {
+	var b bytes.Buffer
+	w := gzip.NewWriter(&b)
+	if _, err := w.Write(src); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// GzipDecompress распаковывает данные формата gzip, для поврежденных данных возвращает VMErrorCorruptGzip
+func GzipDecompress(src []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(src))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", VMErrorCorruptGzip, err)
+	}
+	defer r.Close()
+	bo := bytes.NewBuffer(make([]byte, 0, len(src)*2))
+	buf := getZipBuf()
+	_, err = io.CopyBuffer(bo, r, buf)
+	putZipBuf(buf)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", VMErrorCorruptGzip, err)
+	}
+	return bo.Bytes(), nil
+}
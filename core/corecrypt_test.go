@@ -1,6 +1,7 @@
 package core
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 )
@@ -52,6 +53,26 @@ func TestGZip(t *testing.T) {
 	}
 }
 
+func TestGzipCompress(t *testing.T) {
+	src := []byte("Сжимаемые данные, сжимаемые данные, сжимаемые данные")
+	z, err := GzipCompress(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := GzipDecompress(z)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, src) {
+		t.Errorf("GzipDecompress(GzipCompress()) = %q, want %q", got, src)
+	}
+	for _, bad := range [][]byte{[]byte("не gzip"), z[:len(z)-4], nil} {
+		if _, err := GzipDecompress(bad); !errors.Is(err, VMErrorCorruptGzip) {
+			t.Errorf("GzipDecompress(%q) error = %v, want VMErrorCorruptGzip", bad, err)
+		}
+	}
+}
+
 func TestEncryptAES128(t *testing.T) {
 	type args struct {
 		plaintext []byte
@@ -3,14 +3,26 @@ package core
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
 	"math"
 	"reflect"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/covrom/decnum"
 )
 
 // VMDecNum с плавающей токой, для финансовых расчетов высокой точности (decnum)
+//
+// Число хранится как коэффициент до 34 значащих цифр и десятичный порядок,
+// масштаб (количество знаков после запятой) определяется порядком и сохраняется, включая конечные нули.
+// Масштаб при арифметических операциях:
+//   - сложение и вычитание выполняются точно, масштаб результата равен большему из масштабов операндов;
+//   - при умножении масштабы складываются (1.25 * 1.5 = 1.875);
+//   - деление выполняется до 34 значащих цифр с округлением к четному (1 / 3 = 0.3333333333333333333333333333333333),
+//     для деления с заданным масштабом используется DivScale;
+//   - SetScale приводит число к заданному масштабу с арифметическим округлением.
 type VMDecNum struct {
 	num decnum.Quad
 }
@@ -71,6 +83,89 @@ func (x VMDecNum) String() string {
 	return x.num.String()
 }
 
+// Format реализует fmt.Formatter. Для %f и %F число выводится по точному десятичному значению,
+// с округлением половины от нуля до указанной точности (по умолчанию 6 знаков), без перевода во float64
+func (x VMDecNum) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'f', 'F':
+		p, ok := f.Precision()
+		if !ok {
+			p = 6
+		}
+		if p <= decnum.DecquadPmax {
+			r := x.num.RoundWithMode(int32(p), decnum.RoundHalfUp)
+			// если значащих цифр больше, чем вмещает decQuad, выводим через float64
+			if r.IsFinite() && int(r.GetExponent()) == -p {
+				s := r.String()
+				if !strings.HasPrefix(s, "-") {
+					if f.Flag('+') {
+						s = "+" + s
+					} else if f.Flag(' ') {
+						s = " " + s
+					}
+				}
+				writePadded(f, s)
+				return
+			}
+		}
+		fmt.Fprintf(f, fmtDirective(f, verb), x.Float())
+	case 'v', 's':
+		if verb == 'v' && f.Flag('#') {
+			// синтаксис Го, как при выводе без Format
+			fmt.Fprintf(f, "core.VMDecNum{num:%#v}", x.num)
+			return
+		}
+		fmt.Fprintf(f, fmtDirective(f, verb), x.String())
+	case 'd':
+		fmt.Fprintf(f, fmtDirective(f, verb), x.Int())
+	default:
+		fmt.Fprintf(f, fmtDirective(f, verb), x.Float())
+	}
+}
+
+// fmtDirective восстанавливает директиву форматирования с флагами, шириной и точностью
+func fmtDirective(f fmt.State, verb rune) string {
+	var b strings.Builder
+	b.WriteByte('%')
+	for _, c := range "+-# 0" {
+		if f.Flag(int(c)) {
+			b.WriteRune(c)
+		}
+	}
+	if w, ok := f.Width(); ok {
+		b.WriteString(strconv.Itoa(w))
+	}
+	if p, ok := f.Precision(); ok {
+		b.WriteByte('.')
+		b.WriteString(strconv.Itoa(p))
+	}
+	b.WriteRune(verb)
+	return b.String()
+}
+
+// writePadded выводит число с учетом ширины и флагов '-' и '0'
+func writePadded(f fmt.State, s string) {
+	w, ok := f.Width()
+	if !ok || len(s) >= w {
+		f.Write([]byte(s))
+		return
+	}
+	pad := w - len(s)
+	switch {
+	case f.Flag('-'):
+		s = s + strings.Repeat(" ", pad)
+	case f.Flag('0'):
+		sign := ""
+		if s[0] == '-' || s[0] == '+' || s[0] == ' ' {
+			sign, s = s[:1], s[1:]
+		}
+		s = sign + strings.Repeat("0", pad) + s
+	default:
+		s = strings.Repeat(" ", pad) + s
+	}
+	f.Write([]byte(s))
+}
+
 func (x VMDecNum) Int() int64 {
 	i, err := x.num.ToInt64(decnum.RoundDown) //целая часть, без округления
 	if err != nil {
@@ -149,6 +244,25 @@ func (x VMDecNum) Div(d2 VMDecNum) VMDecNum {
 	return VMDecNum{num: x.num.Div(d2.num)}
 }
 
+// DivScale делит с округлением результата до scale знаков после запятой
+func (x VMDecNum) DivScale(d2 VMDecNum, scale int) VMDecNum {
+	return x.Div(d2).SetScale(scale)
+}
+
+// Scale возвращает количество знаков после запятой
+func (x VMDecNum) Scale() int {
+	if e := int(x.num.GetExponent()); e < 0 {
+		return -e
+	}
+	return 0
+}
+
+// SetScale округляет число до scale знаков после запятой (половина округляется от нуля),
+// недостающие знаки дополняются нулями
+func (x VMDecNum) SetScale(scale int) VMDecNum {
+	return VMDecNum{num: x.num.RoundWithMode(int32(scale), decnum.RoundHalfUp)}
+}
+
 func (x VMDecNum) Mod(d2 VMDecNum) VMDecNum {
 	return VMDecNum{num: x.num.Mod(d2.num)}
 }
@@ -167,6 +281,42 @@ func (x VMDecNum) Pow(d2 VMDecNum) VMDecNum {
 	return VMDecNum{num: decnum.FromFloat(rv)}
 }
 
+// powInt возводит в целую неотрицательную степень умножениями, точность ограничена точностью decnum,
+// а при выходе за диапазон порядков получается бесконечность
+func (x VMDecNum) powInt(y VMInt) VMDecNum {
+	r, b := VMDecNumOne, x
+	for n := y; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			r = r.Mul(b)
+		}
+		if n > 1 {
+			b = b.Mul(b)
+		}
+	}
+	return r
+}
+
+// Exp вычисляет e в степени x, работает только в диапазоне и точности чисел float64
+func (x VMDecNum) Exp() (VMDecNum, error) {
+	return x.MathFunc(math.Exp)
+}
+
+// Log вычисляет натуральный логарифм, работает только в диапазоне и точности чисел float64
+func (x VMDecNum) Log() (VMDecNum, error) {
+	if !x.num.IsPositive() {
+		return VMDecNum{}, VMErrorLogDomain
+	}
+	return VMDecNum{num: decnum.FromFloat(math.Log(x.Float()))}, nil
+}
+
+// Log10 вычисляет десятичный логарифм, работает только в диапазоне и точности чисел float64
+func (x VMDecNum) Log10() (VMDecNum, error) {
+	if !x.num.IsPositive() {
+		return VMDecNum{}, VMErrorLogDomain
+	}
+	return VMDecNum{num: decnum.FromFloat(math.Log10(x.Float()))}, nil
+}
+
 func (x VMDecNum) Equal(d2 VMDecNum) VMBool {
 	return VMBool(x.num.Equal(d2.num))
 }
@@ -180,6 +330,19 @@ func (x VMDecNum) Less(d2 VMDecNum) bool {
 	return x.num.Less(d2.num)
 }
 
+// MathFunc вычисляет математическую функцию с точностью float64.
+// Результат вне области определения (NaN) и бесконечность возвращаются как ошибки
+func (x VMDecNum) MathFunc(f func(float64) float64) (VMDecNum, error) {
+	rv := f(x.Float())
+	if math.IsNaN(rv) {
+		return VMDecNum{}, VMErrorMathDomain
+	}
+	if math.IsInf(rv, 0) {
+		return VMDecNum{}, VMErrorNumberOverflow
+	}
+	return VMDecNum{num: decnum.FromFloat(rv)}, nil
+}
+
 func NewVMDecNumFromInt64(x int64) VMDecNum {
 	return VMDecNum{num: decnum.FromInt64(x)}
 }
@@ -196,6 +359,9 @@ func (x VMDecNum) EvalUnOp(op rune) (VMValuer, error) {
 }
 
 func (x VMDecNum) EvalBinOp(op VMOperation, y VMOperationer) (VMValuer, error) {
+	if yy, ok := y.(VMBigInt); ok {
+		return x.EvalBinOp(op, yy.DecNum())
+	}
 	switch op {
 	case ADD:
 		switch yy := y.(type) {
@@ -322,7 +488,8 @@ func (x VMDecNum) ConvertToType(nt reflect.Type) (VMValuer, error) {
 	case ReflectVMTimeDuration:
 		return x.Duration(), nil
 	case ReflectVMBool:
-		return VMBool(x.Bool()), nil
+		// при явном приведении ложь только для нуля
+		return VMBool(!x.num.IsZero()), nil
 	case ReflectVMString:
 		return VMString(x.String()), nil
 	case ReflectVMInt:
@@ -331,15 +498,107 @@ func (x VMDecNum) ConvertToType(nt reflect.Type) (VMValuer, error) {
 	return VMNil, VMErrorNotConverted
 }
 
+// MarshalBinary сохраняет 16 байт значения decQuad и статус числа в формате encoding/binary
 func (x VMDecNum) MarshalBinary() ([]byte, error) {
 	var buf bytes.Buffer
 	err := binary.Write(&buf, binary.LittleEndian, x.num)
 	return buf.Bytes(), err
 }
 
+// UnmarshalBinary восстанавливает число, сохраненное MarshalBinary. Поля decnum не экспортируются,
+// поэтому 16 байт значения, записанные в формате IEEE 754 decimal128 (DPD, little-endian), переводятся в строку
+// и разбираются через decnum.FromString. Статус числа не восстанавливается
 func (x *VMDecNum) UnmarshalBinary(data []byte) error {
-	err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &x.num)
-	return err
+	if len(data) < decnum.DecquadBytes+2 {
+		return VMErrorNumberFormat
+	}
+	num, err := decnum.FromString(decimal128String(data[:decnum.DecquadBytes]))
+	if err != nil {
+		return VMErrorNumberFormat
+	}
+	x.num = num
+	return nil
+}
+
+// decimal128String возвращает запись числа IEEE 754 decimal128 с коэффициентом в кодировке DPD,
+// в которой сохраняется порядок, а значит, и масштаб числа
+func decimal128String(b []byte) string {
+	lo, hi := binary.LittleEndian.Uint64(b), binary.LittleEndian.Uint64(b[8:])
+	bits := func(pos, n uint) uint64 {
+		var v uint64
+		if pos >= 64 {
+			v = hi >> (pos - 64)
+		} else {
+			v = lo >> pos
+			if pos+n > 64 {
+				v |= hi << (64 - pos)
+			}
+		}
+		return v & (1<<n - 1)
+	}
+	sign := ""
+	if bits(127, 1) == 1 {
+		sign = "-"
+	}
+	// поле комбинации: старшие биты порядка и старшая цифра коэффициента, либо бесконечность и NaN
+	comb := bits(122, 5)
+	var expHi, msd uint64
+	switch {
+	case comb == 0x1e:
+		return sign + "Infinity"
+	case comb == 0x1f:
+		if bits(121, 1) == 1 {
+			return sign + "sNaN"
+		}
+		return sign + "NaN"
+	case comb>>3 == 3:
+		expHi, msd = comb>>1&3, 8+comb&1
+	default:
+		expHi, msd = comb>>3, comb&7
+	}
+	exp := int64(expHi<<12|bits(110, 12)) - 6176
+
+	digits := make([]byte, 0, 34)
+	digits = append(digits, byte('0'+msd))
+	for i := 10; i >= 0; i-- {
+		d := dpdDecode(bits(uint(10*i), 10))
+		digits = append(digits, byte('0'+d/100), byte('0'+d/10%10), byte('0'+d%10))
+	}
+	coef := strings.TrimLeft(string(digits), "0")
+	if coef == "" {
+		coef = "0"
+	}
+	return sign + coef + "E" + strconv.FormatInt(exp, 10)
+}
+
+// dpdDecode переводит 10 бит плотно упакованного десятичного числа (DPD) в число от 0 до 999
+func dpdDecode(d uint64) uint64 {
+	b := func(i uint) uint64 { return d >> i & 1 }
+	small := func(i uint) uint64 { return d >> i & 7 } // три бита, цифра 0-7
+	if b(3) == 0 {
+		return small(7)*100 + small(4)*10 + small(0)
+	}
+	var d1, d2, d3 uint64
+	switch d >> 1 & 3 {
+	case 0:
+		d1, d2, d3 = small(7), small(4), 8+b(0)
+	case 1:
+		d1, d2, d3 = small(7), 8+b(4), d>>4&6|b(0)
+	case 2:
+		d1, d2, d3 = 8+b(7), small(4), d>>7&6|b(0)
+	default:
+		switch d >> 5 & 3 {
+		case 0:
+			d1, d2, d3 = 8+b(7), 8+b(4), d>>7&6|b(0)
+		case 1:
+			d1, d2, d3 = 8+b(7), d>>7&6|b(4), 8+b(0)
+		case 2:
+			d1, d2, d3 = small(7), 8+b(4), 8+b(0)
+		default:
+			d1, d2, d3 = 8+b(7), 8+b(4), 8+b(0)
+		}
+	}
+	return d1*100 + d2*10 + d3
 }
 
 func (x VMDecNum) GobEncode() ([]byte, error) {
@@ -0,0 +1,146 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func mustDecNum(t *testing.T, s string) VMDecNum {
+	t.Helper()
+	d, err := ParseVMDecNum(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestVMDecNumScale(t *testing.T) {
+	tests := []struct {
+		name      string
+		got       VMDecNum
+		want      string
+		wantScale int
+	}{
+		{
+			name:      "деление с масштабом",
+			got:       mustDecNum(t, "1").DivScale(mustDecNum(t, "3"), 4),
+			want:      "0.3333",
+			wantScale: 4,
+		},
+		{
+			name:      "деление с округлением вверх",
+			got:       mustDecNum(t, "2").DivScale(mustDecNum(t, "3"), 2),
+			want:      "0.67",
+			wantScale: 2,
+		},
+		{
+			name:      "деление без масштаба",
+			got:       mustDecNum(t, "1").Div(mustDecNum(t, "3")),
+			want:      "0.3333333333333333333333333333333333",
+			wantScale: 34,
+		},
+		{
+			name:      "умножение складывает масштабы",
+			got:       mustDecNum(t, "1.25").Mul(mustDecNum(t, "1.5")),
+			want:      "1.875",
+			wantScale: 3,
+		},
+		{
+			name:      "сложение с разными масштабами",
+			got:       mustDecNum(t, "1.5").Add(mustDecNum(t, "2.25")),
+			want:      "3.75",
+			wantScale: 2,
+		},
+		{
+			name:      "конечные нули сохраняются",
+			got:       mustDecNum(t, "1.10").Add(mustDecNum(t, "2.2")),
+			want:      "3.30",
+			wantScale: 2,
+		},
+		{
+			name:      "дополнение нулями",
+			got:       mustDecNum(t, "5").SetScale(2),
+			want:      "5.00",
+			wantScale: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got.String() != tt.want {
+				t.Errorf("значение = %s, ожидалось %s", tt.got, tt.want)
+			}
+			if tt.got.Scale() != tt.wantScale {
+				t.Errorf("масштаб = %d, ожидался %d", tt.got.Scale(), tt.wantScale)
+			}
+		})
+	}
+}
+
+func TestVMDecNumFormat(t *testing.T) {
+	tests := []struct {
+		format string
+		val    VMDecNum
+		want   string
+	}{
+		{"%.2f", mustDecNum(t, "0.1").Add(mustDecNum(t, "0.2")), "0.30"},
+		{"%.2f", mustDecNum(t, "12345678901234567890.125"), "12345678901234567890.13"},
+		{"%10.2f", mustDecNum(t, "-3.14159"), "     -3.14"},
+		{"%-8.1f|", mustDecNum(t, "-2.45"), "-2.5    |"},
+		{"%08.2f", mustDecNum(t, "-3.5"), "-0003.50"},
+		{"%+.1f", mustDecNum(t, "2"), "+2.0"},
+		{"%f", mustDecNum(t, "1.5"), "1.500000"},
+		{"%v", mustDecNum(t, "1.50"), "1.50"},
+		{"%6v|", mustDecNum(t, "1.5"), "   1.5|"},
+		{"%d", mustDecNum(t, "7.9"), "7"},
+	}
+	if got := fmt.Sprintf("%#v", VMDecNumOne); !strings.HasPrefix(got, "core.VMDecNum{num:decnum.Quad{") {
+		t.Errorf("%%#v = %q, ожидался синтаксис Го", got)
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprintf(tt.format, tt.val); got != tt.want {
+			t.Errorf("Sprintf(%q, %v) = %q, ожидалось %q", tt.format, tt.val, got, tt.want)
+		}
+	}
+}
+
+func TestVMDecNumMarshalBinary(t *testing.T) {
+	// так 12.50 сохраняли прежние версии, формат должен читаться и дальше
+	old := []byte{0x50, 0x05, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x80, 0x07, 0x22, 0, 0, 0, 0}
+	var x VMDecNum
+	if err := x.UnmarshalBinary(old); err != nil {
+		t.Fatal(err)
+	}
+	if x.String() != "12.50" {
+		t.Errorf("прочитано %s, ожидалось 12.50", x)
+	}
+	if b, err := x.MarshalBinary(); err != nil || !bytes.Equal(b, old) {
+		t.Errorf("MarshalBinary = %x, %v, ожидалось %x", b, err, old)
+	}
+
+	nums := []string{"0", "-1.005", "1E+400", "123456789012345678901234567890.1234", "-0.000",
+		"9.999999999999999999999999999999999E+6144", "1E-6176", "Infinity", "-Infinity", "NaN"}
+	// все значения групп из трех цифр в каждой позиции коэффициента
+	for i := 0; i < 1000; i++ {
+		nums = append(nums, fmt.Sprintf("%d%03d%03d.%03d", i, 999-i, (i*7)%1000, (i*13)%1000))
+	}
+	for _, s := range nums {
+		v, _ := ParseVMDecNum(s)
+		b, err := v.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got VMDecNum
+		if err := got.UnmarshalBinary(b); err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != v.String() {
+			t.Errorf("после чтения %s, ожидалось %s", got, v)
+		}
+	}
+
+	if err := x.UnmarshalBinary([]byte("12.50")); err != VMErrorNumberFormat {
+		t.Errorf("ошибка = %v, ожидалась %v", err, VMErrorNumberFormat)
+	}
+}
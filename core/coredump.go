@@ -0,0 +1,111 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/shinanca/gonec/names"
+)
+
+// DumpVMValue возвращает многострочное представление значения с указанием типов,
+// вложенные массивы и структуры выводятся с отступами.
+// Циклические ссылки выводятся как <цикл>, без повторного обхода.
+func DumpVMValue(env *Env, v VMValuer) string {
+	var buf bytes.Buffer
+	d := &dumper{env: env, buf: &buf, path: make(map[uintptr]bool)}
+	d.dump(v, 0)
+	return buf.String()
+}
+
+type dumper struct {
+	env  *Env
+	buf  *bytes.Buffer
+	path map[uintptr]bool // контейнеры на текущем пути обхода
+}
+
+func (d *dumper) typeName(v VMValuer) string {
+	switch vv := v.(type) {
+	case nil, VMNilType:
+		return "Неопределено"
+	case *VMStruct:
+		return names.UniqueNames.Get(vv.Type().Name)
+	}
+	return names.UniqueNames.Get(d.env.TypeName(reflect.TypeOf(v)))
+}
+
+// enter отмечает контейнер на пути обхода, возвращает false, если он уже там есть
+func (d *dumper) enter(p uintptr) bool {
+	if d.path[p] {
+		return false
+	}
+	d.path[p] = true
+	return true
+}
+
+func (d *dumper) dump(v VMValuer, level int) {
+	ind := strings.Repeat("  ", level+1)
+	switch vv := v.(type) {
+	case VMSlice:
+		p := reflect.ValueOf(vv).Pointer()
+		if len(vv) > 0 && !d.enter(p) {
+			d.buf.WriteString("<цикл>")
+			return
+		}
+		fmt.Fprintf(d.buf, "%s (%d) [", d.typeName(v), len(vv))
+		for i, e := range vv {
+			fmt.Fprintf(d.buf, "\n%s%d: ", ind, i)
+			d.dump(e, level+1)
+		}
+		if len(vv) > 0 {
+			fmt.Fprintf(d.buf, "\n%s", ind[2:])
+			delete(d.path, p)
+		}
+		d.buf.WriteString("]")
+	case VMStringMap:
+		p := reflect.ValueOf(vv).Pointer()
+		if !d.enter(p) {
+			d.buf.WriteString("<цикл>")
+			return
+		}
+		keys := make([]string, 0, len(vv))
+		for k := range vv {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		fmt.Fprintf(d.buf, "%s (%d) {", d.typeName(v), len(vv))
+		for _, k := range keys {
+			fmt.Fprintf(d.buf, "\n%s%q: ", ind, k)
+			d.dump(vv[k], level+1)
+		}
+		if len(vv) > 0 {
+			fmt.Fprintf(d.buf, "\n%s", ind[2:])
+		}
+		d.buf.WriteString("}")
+		delete(d.path, p)
+	case *VMStruct:
+		p := reflect.ValueOf(vv).Pointer()
+		if !d.enter(p) {
+			d.buf.WriteString("<цикл>")
+			return
+		}
+		fmt.Fprintf(d.buf, "%s {", d.typeName(v))
+		for i, f := range vv.typ.Fields {
+			fmt.Fprintf(d.buf, "\n%s%s: ", ind, names.UniqueNames.Get(f))
+			d.dump(vv.vals[i], level+1)
+		}
+		if len(vv.vals) > 0 {
+			fmt.Fprintf(d.buf, "\n%s", ind[2:])
+		}
+		d.buf.WriteString("}")
+		delete(d.path, p)
+	case VMString:
+		fmt.Fprintf(d.buf, "%s %q", d.typeName(v), string(vv))
+	case nil, VMNilType:
+		d.buf.WriteString("Неопределено")
+	default:
+		fmt.Fprintf(d.buf, "%s %v", d.typeName(v), v)
+	}
+}
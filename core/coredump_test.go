@@ -0,0 +1,87 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/shinanca/gonec/names"
+)
+
+func TestDumpVMValue(t *testing.T) {
+	env := NewEnv()
+	Import(env)
+
+	cyclic := VMStringMap{"имя": VMString("узел")}
+	cyclic["сам"] = cyclic
+
+	st := NewVMStructType(names.UniqueNames.Set("Узел"), []int{names.UniqueNames.Set("Знач"), names.UniqueNames.Set("След")})
+	node := st.New()
+	node.SetField(names.UniqueNames.Set("Знач"), VMInt(1))
+	node.SetField(names.UniqueNames.Set("След"), node)
+
+	tests := []struct {
+		name string
+		v    VMValuer
+		want string
+	}{
+		{
+			name: "скаляр",
+			v:    VMInt(42),
+			want: "целоечисло 42",
+		},
+		{
+			name: "неопределено",
+			v:    VMNil,
+			want: "Неопределено",
+		},
+		{
+			name: "структура массивов",
+			v: VMStringMap{
+				"б": VMSlice{VMInt(1), VMString("два")},
+				"а": VMSlice{},
+			},
+			want: `структура (2) {
+  "а": массив (0) []
+  "б": массив (2) [
+    0: целоечисло 1
+    1: строка "два"
+  ]
+}`,
+		},
+		{
+			name: "цикл в структуре",
+			v:    cyclic,
+			want: `структура (2) {
+  "имя": строка "узел"
+  "сам": <цикл>
+}`,
+		},
+		{
+			name: "цикл в пользовательской структуре",
+			v:    node,
+			want: `Узел {
+  Знач: целоечисло 1
+  След: <цикл>
+}`,
+		},
+		{
+			name: "повтор без цикла",
+			v:    VMSlice{cyclic["имя"], VMSlice{VMInt(1)}, VMSlice{VMInt(1)}},
+			want: `массив (3) [
+  0: строка "узел"
+  1: массив (1) [
+    0: целоечисло 1
+  ]
+  2: массив (1) [
+    0: целоечисло 1
+  ]
+]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DumpVMValue(env, tt.v); got != tt.want {
+				t.Errorf("DumpVMValue() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
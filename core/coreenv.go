@@ -1,6 +1,8 @@
 package core
 
 import (
+	"bufio"
+	"context"
 	"encoding/gob"
 	"fmt"
 	"io"
@@ -8,6 +10,7 @@ import (
 	"reflect"
 	"sort"
 	"sync"
+	"sync/atomic"
 
 	"github.com/shinanca/gonec/names"
 )
@@ -82,13 +85,21 @@ type Env struct {
 	name         string
 	env          *Vals
 	typ          map[int]reflect.Type
+	styp         map[int]*VMStructType
 	parent       *Env
 	interrupt    *bool
 	stdout       io.Writer
+	stdin        *bufio.Reader
 	sid          string
 	lastid       int
 	lastval      VMValuer
 	builtsLoaded bool
+	callDepth    int             // глубина вложенности вызовов функций, в которой исполняется окружение
+	maxCallDepth int             // ограничение глубины вызовов, 0 - действует ограничение родительского окружения
+	evalDepth    int32           // вложенность вызовов Выполнить, исполняющих код в этом окружении
+	ctx          context.Context // контекст отмены исполнения, действует и на вложенные окружения
+	budget       *int64          // оставшееся число инструкций, nil - без ограничения
+	module       bool            // окружение модуля, объявленного оператором Модуль
 	Valid        bool
 }
 
@@ -121,7 +132,6 @@ func (e *Env) NewEnv() *Env {
 				typ:          make(map[int]reflect.Type),
 				parent:       ee,
 				interrupt:    e.interrupt,
-				stdout:       e.stdout,
 				lastid:       -1,
 				builtsLoaded: ee.builtsLoaded,
 				Valid:        true,
@@ -132,6 +142,16 @@ func (e *Env) NewEnv() *Env {
 	panic("Не найден глобальный контекст!")
 }
 
+// NewModuleEnv создает новое окружение под ближайшим к e модулем, а вне модулей - под глобальным контекстом
+func (e *Env) NewModuleEnv() *Env {
+	for ee := e; ee != nil; ee = ee.parent {
+		if ee.module {
+			return ee.NewSubEnv()
+		}
+	}
+	return e.NewEnv()
+}
+
 // NewSubEnv создает новое окружение под e, нужно для замыкания в анонимных функциях
 func (e *Env) NewSubEnv() *Env {
 	return &Env{
@@ -139,7 +159,6 @@ func (e *Env) NewSubEnv() *Env {
 		typ:          make(map[int]reflect.Type),
 		parent:       e,
 		interrupt:    e.interrupt,
-		stdout:       e.stdout,
 		lastid:       -1,
 		builtsLoaded: e.builtsLoaded,
 		Valid:        true,
@@ -158,6 +177,7 @@ func (e *Env) NewModule(n string) *Env {
 
 	m := e.NewEnv()
 	m.name = n
+	m.module = true
 
 	// на модуль можно ссылаться через переменную породившего глобального контекста
 	e.DefineGlobal(id, m)
@@ -171,13 +191,50 @@ func (e *Env) NewPackage(n string) *Env {
 		parent:       e,
 		name:         names.FastToLower(n),
 		interrupt:    e.interrupt,
-		stdout:       e.stdout,
 		lastid:       -1,
 		builtsLoaded: e.builtsLoaded,
 		Valid:        true,
 	}
 }
 
+// CallDepth возвращает глубину вложенности вызовов функций, для окружения вне функций - 0
+func (e *Env) CallDepth() int {
+	return e.callDepth
+}
+
+// SetCallDepth устанавливает глубину вложенности вызовов для окружения вызванной функции
+func (e *Env) SetCallDepth(d int) {
+	e.callDepth = d
+}
+
+// SetMaxCallDepth ограничивает глубину вложенности вызовов функций в окружении, во вложенных окружениях
+// и в вызванных из них функциях. Устанавливается до начала исполнения кода в окружении
+func (e *Env) SetMaxCallDepth(n int) {
+	e.maxCallDepth = n
+}
+
+// MaxCallDepth возвращает ограничение глубины вызовов ближайшего окружения, в котором оно установлено,
+// или 0, если ограничение нигде не установлено
+func (e *Env) MaxCallDepth() int {
+	for ee := e; ee != nil; ee = ee.parent {
+		if ee.maxCallDepth > 0 {
+			return ee.maxCallDepth
+		}
+	}
+	return 0
+}
+
+// EnterEval увеличивает вложенность вызовов Выполнить в окружении и возвращает ее новое значение.
+// Окружение может использоваться из нескольких горутин, поэтому счетчик изменяется атомарно
+func (e *Env) EnterEval() int32 {
+	return atomic.AddInt32(&e.evalDepth, 1)
+}
+
+// LeaveEval уменьшает вложенность вызовов Выполнить после завершения фрагмента
+func (e *Env) LeaveEval() {
+	atomic.AddInt32(&e.evalDepth, -1)
+}
+
 // Destroy deletes current scope.
 func (e *Env) Destroy() {
 	if e.parent == nil {
@@ -203,6 +260,13 @@ func (e *Env) Destroy() {
 		id := names.UniqueNames.Set(e.name)
 		e.DefineGlobal(id, nil)
 	}
+	// уничтоженное окружение продолжает выводить в поток родителя, например, ошибки завершившейся горутины
+	if e.stdout == nil {
+		e.stdout = e.parent.Stdout()
+	}
+	if e.stdin == nil {
+		e.stdin = e.parent.Stdin()
+	}
 	e.parent = nil
 	e.env.Destroy()
 	e.env = nil
@@ -247,6 +311,24 @@ func (e *Env) TypeName(t reflect.Type) int {
 	return names.UniqueNames.Set(t.String())
 }
 
+// IsType проверяет, что значение имеет тип с идентификатором имени k без приведения:
+// пользовательскую структуру, Неопределено или зарегистрированный тип (в т.ч. по ссылке)
+func (e *Env) IsType(v VMValuer, k int) (bool, error) {
+	if st, ok := e.StructType(k); ok {
+		vs, ok := v.(*VMStruct)
+		return ok && vs.Type() == st, nil
+	}
+	if names.UniqueNames.GetLowerCase(k) == "неопределено" {
+		return v == nil || v == VMNil, nil
+	}
+	rt, err := e.Type(k)
+	if err != nil {
+		return false, err
+	}
+	vt := reflect.TypeOf(v)
+	return vt != nil && (vt == rt || vt.Kind() == reflect.Ptr && vt.Elem() == rt), nil
+}
+
 // Type returns type which specified symbol. It goes to upper scope until
 // found or returns error.
 func (e *Env) Type(k int) (reflect.Type, error) {
@@ -317,6 +399,17 @@ func (e *Env) DefineGlobal(k int, v VMValuer) error {
 	return fmt.Errorf("Отсутствует глобальный контекст!")
 }
 
+// DefineModule определяет переменную в окружении ближайшего модуля, а вне модулей - в глобальном контексте.
+// Используется для переменных, объявленных в функции как Глобальная
+func (e *Env) DefineModule(k int, v VMValuer) error {
+	for ee := e; ee != nil; ee = ee.parent {
+		if ee.module || ee.parent == nil {
+			return ee.Define(k, v)
+		}
+	}
+	return fmt.Errorf("Отсутствует глобальный контекст!")
+}
+
 // DefineType defines type which specifis symbol in global scope.
 func (e *Env) DefineType(k int, t reflect.Type) error {
 	for ee := e; ee != nil; ee = ee.parent {
@@ -334,6 +427,35 @@ func (e *Env) DefineTypeS(k string, t reflect.Type) error {
 	return e.DefineType(names.UniqueNames.Set(k), t)
 }
 
+// DefineStructType регистрирует в глобальном контексте пользовательскую структуру, объявленную в коде
+func (e *Env) DefineStructType(t *VMStructType) error {
+	for ee := e; ee != nil; ee = ee.parent {
+		if ee.parent == nil {
+			ee.Lock()
+			defer ee.Unlock()
+			if ee.styp == nil {
+				ee.styp = make(map[int]*VMStructType)
+			}
+			ee.styp[t.Name] = t
+			return nil
+		}
+	}
+	return fmt.Errorf("Отсутствует глобальный контекст!")
+}
+
+// StructType возвращает описание пользовательской структуры, если она была объявлена
+func (e *Env) StructType(k int) (*VMStructType, bool) {
+	for ee := e; ee != nil; ee = ee.parent {
+		ee.RLock()
+		if t, ok := ee.styp[k]; ok {
+			ee.RUnlock()
+			return t, true
+		}
+		ee.RUnlock()
+	}
+	return nil, false
+}
+
 // DefineTypeStruct регистрирует системную функциональную структуру, переданную в виде указателя!
 func (e *Env) DefineTypeStruct(k string, t interface{}) error {
 	gob.Register(t)
@@ -356,6 +478,34 @@ func (e *Env) DefineS(k string, v VMValuer) error {
 	return e.Define(names.UniqueNames.Set(k), v)
 }
 
+// Locals возвращает переменные, определенные непосредственно в этом окружении, без переменных родительских окружений
+func (e *Env) Locals() VMStringMap {
+	e.RLock()
+	defer e.RUnlock()
+	m := make(VMStringMap, len(e.env.idx))
+	for k, i := range e.env.idx {
+		if v := e.env.vals[i]; v != nil {
+			m[names.UniqueNames.Get(k)] = v
+		}
+	}
+	return m
+}
+
+// LocalNames возвращает отсортированные имена переменных и функций, определенных непосредственно в этом окружении
+func (e *Env) LocalNames() VMSlice {
+	locals := e.Locals()
+	keys := make([]string, 0, len(locals))
+	for k := range locals {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	rv := make(VMSlice, len(keys))
+	for i, k := range keys {
+		rv[i] = VMString(k)
+	}
+	return rv
+}
+
 // String return the name of current scope.
 func (e *Env) String() string {
 	return e.name
@@ -381,13 +531,13 @@ func (e *Env) Dump() {
 func (e *Env) Println(a ...interface{}) (n int, err error) {
 	// e.RLock()
 	// defer e.RUnlock()
-	return fmt.Fprintln(e.stdout, a...)
+	return fmt.Fprintln(e.Stdout(), a...)
 }
 
 func (e *Env) Printf(format string, a ...interface{}) (n int, err error) {
 	// e.RLock()
 	// defer e.RUnlock()
-	return fmt.Fprintf(e.stdout, format, a...)
+	return fmt.Fprintf(e.Stdout(), format, a...)
 }
 
 func (e *Env) Sprintf(format string, a ...interface{}) string {
@@ -399,15 +549,56 @@ func (e *Env) Sprintf(format string, a ...interface{}) string {
 func (e *Env) Print(a ...interface{}) (n int, err error) {
 	// e.RLock()
 	// defer e.RUnlock()
-	return fmt.Fprint(e.stdout, a...)
+	return fmt.Fprint(e.Stdout(), a...)
 }
 
 func (e *Env) StdOut() reflect.Value {
 	// e.RLock()
 	// defer e.RUnlock()
-	return reflect.ValueOf(e.stdout)
+	return reflect.ValueOf(e.Stdout())
+}
+
+// Stdout возвращает поток вывода Сообщить и других функций вывода: установленный через SetStdOut
+// в этом окружении или в ближайшем родительском, для глобального окружения по умолчанию - os.Stdout
+func (e *Env) Stdout() io.Writer {
+	for ee := e; ee != nil; ee = ee.parent {
+		if ee.stdout != nil {
+			return ee.stdout
+		}
+	}
+	return os.Stdout
+}
+
+// stdin - стандартный ввод, общий для всех окружений без собственного потока ввода
+var stdin = bufio.NewReader(os.Stdin)
+
+// Stdin возвращает поток ввода ПрочитатьСтроку: установленный через SetStdIn в этом окружении
+// или в ближайшем родительском, по умолчанию - os.Stdin
+func (e *Env) Stdin() *bufio.Reader {
+	for ee := e; ee != nil; ee = ee.parent {
+		if ee.stdin != nil {
+			return ee.stdin
+		}
+	}
+	return stdin
+}
+
+// SetStdIn устанавливает поток ввода для окружения и всех вложенных в него окружений. nil возвращает поток родителя
+func (e *Env) SetStdIn(r io.Reader) {
+	if r == nil {
+		e.stdin = nil
+		return
+	}
+	if br, ok := r.(*bufio.Reader); ok {
+		e.stdin = br
+		return
+	}
+	e.stdin = bufio.NewReader(r)
 }
 
+// SetStdOut устанавливает поток вывода для окружения и всех вложенных в него окружений,
+// в том числе созданных ранее, если в них не установлен собственный поток.
+// Так каждый экземпляр интерпретатора может выводить в свой поток. nil возвращает поток родительского окружения
 func (e *Env) SetStdOut(w io.Writer) {
 	// e.Lock()
 	//пренебрегаем возможными коллизиями при установке потока вывода, т.к. это совсем редкая операция
@@ -439,6 +630,77 @@ func (e *Env) Interrupt() {
 	*(e.interrupt) = true
 }
 
+// SetContext устанавливает контекст, при отмене которого исполнение кода в окружении
+// и во всех вложенных окружениях, включая запущенные из него горутины, прекращается.
+// Возвращается контекст, ранее установленный в самом окружении, или nil, чтобы его можно было восстановить
+func (e *Env) SetContext(ctx context.Context) context.Context {
+	e.Lock()
+	prev := e.ctx
+	e.ctx = ctx
+	e.Unlock()
+	return prev
+}
+
+// Context возвращает контекст отмены ближайшего окружения, в котором он установлен
+func (e *Env) Context() context.Context {
+	for ee := e; ee != nil; ee = ee.parent {
+		ee.RLock()
+		ctx := ee.ctx
+		ee.RUnlock()
+		if ctx != nil {
+			return ctx
+		}
+	}
+	return context.Background()
+}
+
+// SetInstructionBudget ограничивает число инструкций, исполняемых в окружении и во всех вложенных окружениях,
+// включая вызванные функции и горутины. При исчерпании исполнение прекращается с ошибкой
+func (e *Env) SetInstructionBudget(n int64) {
+	e.Lock()
+	e.budget = &n
+	e.Unlock()
+}
+
+// InstructionBudget возвращает счетчик оставшихся инструкций ближайшего окружения, в котором он установлен,
+// или nil, если число инструкций не ограничено. Счетчик уменьшается атомарно
+func (e *Env) InstructionBudget() *int64 {
+	for ee := e; ee != nil; ee = ee.parent {
+		ee.RLock()
+		b := ee.budget
+		ee.RUnlock()
+		if b != nil {
+			return b
+		}
+	}
+	return nil
+}
+
+// InheritLimits передает окружению ограничения исполнения окружения from: контекст отмены,
+// общий с ним счетчик инструкций, глубину вложенности вызовов и ее ограничение. Используется для окружений,
+// не являющихся вложенными в from, но исполняющихся от его имени
+func (e *Env) InheritLimits(from *Env) {
+	ctx, budget := from.Context(), from.InstructionBudget()
+	e.Lock()
+	e.ctx = ctx
+	e.budget = budget
+	e.callDepth = from.CallDepth()
+	e.maxCallDepth = from.MaxCallDepth()
+	e.Unlock()
+}
+
+// InstructionsLeft возвращает число оставшихся инструкций и признак того, что ограничение установлено
+func (e *Env) InstructionsLeft() (int64, bool) {
+	b := e.InstructionBudget()
+	if b == nil {
+		return 0, false
+	}
+	if n := atomic.LoadInt64(b); n > 0 {
+		return n, true
+	}
+	return 0, true
+}
+
 func (e *Env) CheckInterrupt() bool {
 	if *(e.interrupt) {
 		*(e.interrupt) = false
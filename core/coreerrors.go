@@ -24,6 +24,16 @@ var (
 	VMErrorNeedSeconds     = errors.New("Должно быть число секунд (допустимо с дробной частью)")
 	VMErrorNeedHash        = errors.New("Параметр не может быть хэширован")
 	VMErrorNeedBinaryTyper = errors.New("Требуется значение, которое может быть сериализовано в бинарное")
+	VMErrorNeedXMLElement  = errors.New("Требуется структура с описанием элемента XML")
+	VMErrorNeedFunc        = errors.New("Требуется значение типа Функция")
+	VMErrorNeedChan        = errors.New("Требуется значение типа Канал")
+	VMErrorNeedSingleRune  = errors.New("Требуется строка из одного символа")
+	VMErrorNeedModule      = errors.New("Требуется значение типа Модуль")
+	VMErrorNeedSet         = errors.New("Требуется значение типа Множество")
+	VMErrorNeedFuture      = errors.New("Требуется результат функции, запущенной через Старт")
+	VMErrorNeedPair        = errors.New("Требуется массив из двух элементов")
+	VMErrorSetElement      = errors.New("Элементом множества может быть только строка, целое число, булево или массив и структура из них")
+	VMErrorFrozen          = errors.New("Значение заморожено и не может быть изменено")
 
 	VMErrorIndexOutOfBoundary  = errors.New("Индекс находится за пределами массива")
 	VMErrorNotConverted        = errors.New("Приведение к типу невозможно")
@@ -37,7 +47,27 @@ var (
 	VMErrorNoArgs     = errors.New("Отсутствуют аргументы")
 
 	VMErrorIncorrectOperation = errors.New("Операция между значениями невозможна")
+	VMErrorIntOverflow        = errors.New("Переполнение целого числа")
+	VMErrorDivisionByZero     = errors.New("Деление на ноль")
+	VMErrorNegativeExponent   = errors.New("Показатель степени не может быть отрицательным")
+	VMErrorNegativeShift      = errors.New("Величина сдвига не может быть отрицательной")
+	VMErrorBigIntTooLarge     = errors.New("Слишком большое целое число")
+	VMErrorNegativeCount      = errors.New("Количество не может быть отрицательным")
+	VMErrorTooLarge           = errors.New("Слишком большой размер результата")
+	VMErrorNonPositiveSize    = errors.New("Размер должен быть больше нуля")
+	VMErrorNonPositiveCount   = errors.New("Количество должно быть больше нуля")
+	VMErrorEmptySlice         = errors.New("Массив не должен быть пустым")
+	VMErrorCorruptGzip        = errors.New("Данные повреждены или не сжаты в формате gzip")
+	VMErrorURLEscape          = errors.New("Неверная последовательность в закодированной строке URL")
+	VMErrorBigIntFormat       = errors.New("Неверный формат большого целого числа")
 	VMErrorUnknownOperation   = errors.New("Неизвестная операция")
+	VMErrorChanClosed         = errors.New("Канал закрыт")
+	VMErrorNumberFormat       = errors.New("Неверный формат числа")
+	VMErrorNumberOverflow     = errors.New("Переполнение числа")
+	VMErrorLogDomain          = errors.New("Логарифм определен только для положительных чисел")
+	VMErrorMathDomain         = errors.New("Аргумент вне области определения функции")
+	VMErrorEvalDepth          = errors.New("Превышена глубина вложенности вызовов Выполнить")
+	VMErrorCallDepth          = errors.New("Превышена глубина вложенности вызовов функций")
 
 	VMErrorServerNowOnline   = errors.New("Сервер уже запущен")
 	VMErrorServerOffline     = errors.New("Сервер уже остановлен")
@@ -63,3 +93,16 @@ var (
 func VMErrorNeedArgs(n int) error {
 	return fmt.Errorf("Неверное количество параметров (требуется %d)", n)
 }
+
+// VMErrorNeedArgsRange для функций с необязательными параметрами
+func VMErrorNeedArgsRange(min, max int) error {
+	return fmt.Errorf("Неверное количество параметров (требуется от %d до %d)", min, max)
+}
+
+func VMErrorNotType(name string) error {
+	return fmt.Errorf("Значение не является значением типа %s", name)
+}
+
+func VMErrorTemplateKey(name string) error {
+	return fmt.Errorf("Нет значения для '%s' в шаблоне", name)
+}
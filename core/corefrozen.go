@@ -0,0 +1,211 @@
+package core
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/shinanca/gonec/names"
+)
+
+// VMFrozen неизменяемое представление массива или структуры: Заморозить(значение).
+// Заморозка глубокая: вложенные массивы и структуры при чтении также возвращаются замороженными.
+// Копирование не выполняется, поэтому изменения исходного значения видны через замороженное.
+// Любая попытка изменения вызывает исключение, которое можно перехватить в Попытка
+type VMFrozen struct {
+	v VMValuer // VMSlice, VMStringMap или *VMOrderedMap
+}
+
+var ReflectVMFrozen = reflect.TypeOf(&VMFrozen{})
+
+// Freeze возвращает замороженное представление массива или структуры, остальные значения возвращаются как есть
+func Freeze(v VMValuer) VMValuer {
+	switch v.(type) {
+	case VMSlice, VMStringMap, *VMOrderedMap:
+		return &VMFrozen{v: v}
+	}
+	return v
+}
+
+// frozenMutators методы массивов и структур, изменяющие значение
+var frozenMutators = map[string]bool{
+	"сортировать":     true,
+	"сортироватьубыв": true,
+	"обратить":        true,
+	"вставить":        true,
+	"удалить":         true,
+}
+
+func (x *VMFrozen) vmval() {}
+
+func (x *VMFrozen) Interface() interface{} {
+	return x.v.(VMInterfacer).Interface()
+}
+
+// Value возвращает исходное значение, изменять его через полученную ссылку нельзя
+func (x *VMFrozen) Value() VMValuer {
+	return x.v
+}
+
+func (x *VMFrozen) Length() VMInt {
+	return x.v.(VMIndexer).Length()
+}
+
+// IndexVal возвращает замороженный элемент, за пределами массива и для отсутствующего ключа - Неопределено
+func (x *VMFrozen) IndexVal(i VMValuer) VMValuer {
+	rv, err := x.Index(i)
+	if err != nil {
+		return VMNil
+	}
+	return rv
+}
+
+// Index возвращает замороженный элемент массива по индексу или значение структуры по ключу
+func (x *VMFrozen) Index(i VMValuer) (VMValuer, error) {
+	switch vv := x.v.(type) {
+	case VMSlice:
+		ii, ok := i.(VMInt)
+		if !ok {
+			return VMNil, VMErrorNeedInt
+		}
+		if ii < 0 {
+			ii += VMInt(len(vv))
+		}
+		if ii < 0 || int(ii) >= len(vv) {
+			return VMNil, VMErrorIndexOutOfBoundary
+		}
+		return Freeze(vv[ii]), nil
+	case VMStringMap:
+		k, ok := i.(VMString)
+		if !ok {
+			return VMNil, VMErrorNeedString
+		}
+		if rv, ok := vv[string(k)]; ok {
+			return Freeze(rv), nil
+		}
+	case *VMOrderedMap:
+		k, ok := i.(VMString)
+		if !ok {
+			return VMNil, VMErrorNeedString
+		}
+		if rv, ok := vv.Get(string(k)); ok {
+			return Freeze(rv), nil
+		}
+	}
+	return VMNil, nil
+}
+
+// Member возвращает замороженное поле структуры или метод, не изменяющий значение
+func (x *VMFrozen) Member(name int) (VMValuer, error) {
+	var ok bool
+	var rv VMValuer
+	switch vv := x.v.(type) {
+	case VMStringMap:
+		rv, ok = vv[names.UniqueNames.Get(name)]
+	case *VMOrderedMap:
+		rv, ok = vv.Get(names.UniqueNames.Get(name))
+	}
+	if ok {
+		return Freeze(rv), nil
+	}
+	if f, ok := x.MethodMember(name); ok {
+		return f, nil
+	}
+	if _, ok := x.v.(VMSlice); ok {
+		return VMNil, VMErrorNotDefined
+	}
+	return VMNil, nil
+}
+
+// Slice возвращает замороженные элементы массива или ключи структуры для обхода в цикле Для каждого
+func (x *VMFrozen) Slice() VMSlice {
+	switch vv := x.v.(type) {
+	case VMSlice:
+		rv := make(VMSlice, len(vv))
+		for i, v := range vv {
+			rv[i] = Freeze(v)
+		}
+		return rv
+	case VMStringMap:
+		keys := make([]string, 0, len(vv))
+		for k := range vv {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		rv := make(VMSlice, len(keys))
+		for i, k := range keys {
+			rv[i] = VMString(k)
+		}
+		return rv
+	case *VMOrderedMap:
+		return vv.Keys()
+	}
+	return VMSlice{}
+}
+
+func (x *VMFrozen) String() string {
+	b, err := json.Marshal(x.v)
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
+
+func (x *VMFrozen) MarshalJSON() ([]byte, error) {
+	return json.Marshal(x.v)
+}
+
+func (x *VMFrozen) EvalBinOp(op VMOperation, y VMOperationer) (VMValuer, error) {
+	if yy, ok := y.(*VMFrozen); ok {
+		y = yy.v.(VMOperationer)
+	}
+	return x.v.(VMOperationer).EvalBinOp(op, y)
+}
+
+func (x *VMFrozen) ConvertToType(nt reflect.Type) (VMValuer, error) {
+	switch nt {
+	case ReflectVMString:
+		return VMString(x.String()), nil
+	}
+	return VMNil, VMErrorNotConverted
+}
+
+func (x *VMFrozen) MethodMember(name int) (VMFunc, bool) {
+	ln := names.UniqueNames.GetLowerCase(name)
+	if frozenMutators[ln] {
+		return func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+			return VMErrorFrozen
+		}, true
+	}
+	if ln == "значения" {
+		if _, ok := x.v.(VMSlice); !ok {
+			return VMFuncMustParams(0, x.Значения), true
+		}
+	}
+	// остальные методы только читают значение, Скопировать возвращает изменяемую копию
+	if m, ok := x.v.(VMMethodImplementer); ok {
+		return m.MethodMember(name)
+	}
+	return nil, false
+}
+
+// Значения возвращает замороженные значения структуры
+func (x *VMFrozen) Значения(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+	var vals VMSlice
+	switch vv := x.v.(type) {
+	case VMStringMap:
+		if err := vv.Значения(args, &vals, envout); err != nil {
+			return err
+		}
+	case *VMOrderedMap:
+		if err := vv.Значения(args, &vals, envout); err != nil {
+			return err
+		}
+	}
+	rv := vals[0].(VMSlice)
+	for i, v := range rv {
+		rv[i] = Freeze(v)
+	}
+	rets.Append(rv)
+	return nil
+}
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"reflect"
+
+	"github.com/shinanca/gonec/names"
+)
+
+// VMFuture результат функции, запущенной в горутине: б = Старт Функция().
+// Результат() ожидает завершения функции и возвращает ее значение или вызывает исключение, возникшее в ней
+type VMFuture struct {
+	done chan struct{}
+	val  VMValuer
+	err  error
+}
+
+var ReflectVMFuture = reflect.TypeOf(&VMFuture{})
+
+func NewVMFuture() *VMFuture {
+	return &VMFuture{done: make(chan struct{})}
+}
+
+func (x *VMFuture) vmval() {}
+
+func (x *VMFuture) Interface() interface{} {
+	return x
+}
+
+// Resolve сохраняет результат функции и освобождает ожидающих, вызывается один раз
+func (x *VMFuture) Resolve(v VMValuer, err error) {
+	x.val, x.err = v, err
+	close(x.done)
+}
+
+// Wait ожидает завершения функции
+func (x *VMFuture) Wait() (VMValuer, error) {
+	<-x.done
+	return x.val, x.err
+}
+
+// Done возвращает признак завершения функции без ожидания
+func (x *VMFuture) Done() bool {
+	select {
+	case <-x.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (x *VMFuture) String() string {
+	if x.Done() {
+		return "[Будущее: завершено]"
+	}
+	return "[Будущее: выполняется]"
+}
+
+func (x *VMFuture) MethodMember(name int) (VMFunc, bool) {
+
+	// только эти методы будут доступны из кода на языке Гонец!
+	switch names.UniqueNames.GetLowerCase(name) {
+	case "результат":
+		return VMFuncMustParams(0, x.Результат), true
+	case "завершено":
+		return VMFuncMustParams(0, x.Завершено), true
+	}
+	return nil, false
+}
+
+// Результат ожидает завершения функции и возвращает ее значение, ошибка функции вызывает исключение
+func (x *VMFuture) Результат(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+	v, err := x.Wait()
+	if err != nil {
+		return err
+	}
+	rets.Append(v)
+	return nil
+}
+
+func (x *VMFuture) Завершено(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+	rets.Append(VMBool(x.Done()))
+	return nil
+}
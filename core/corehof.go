@@ -0,0 +1,240 @@
+package core
+
+import (
+	"sync"
+	"time"
+)
+
+// CallVMFunc вызывает функцию с аргументами и возвращает ее результат так же, как при вызове из кода:
+// без возвращаемых значений - Неопределено, одно значение - само значение, несколько - массив.
+// env - окружение вызывающего кода, от него отсчитывается глубина вызовов; nil начинает отсчет заново
+func CallVMFunc(env *Env, f VMFunc, args ...VMValuer) (VMValuer, error) {
+	rets := make(VMSlice, 0, 1)
+	if err := f(VMSlice(args), &rets, &env); err != nil {
+		return VMNil, err
+	}
+	switch len(rets) {
+	case 0:
+		return VMNil, nil
+	case 1:
+		return rets[0], nil
+	}
+	return rets, nil
+}
+
+// MapVMSlice возвращает новый массив из результатов вызова функции для каждого элемента
+func MapVMSlice(env *Env, sl VMSlice, f VMFunc) (VMSlice, error) {
+	rv := make(VMSlice, len(sl))
+	for i, v := range sl {
+		r, err := CallVMFunc(env, f, v)
+		if err != nil {
+			return nil, err
+		}
+		rv[i] = r
+	}
+	return rv, nil
+}
+
+// FilterVMSlice возвращает новый массив из элементов, для которых предикат вернул Истина
+func FilterVMSlice(env *Env, sl VMSlice, f VMFunc) (VMSlice, error) {
+	rv := make(VMSlice, 0, len(sl))
+	for _, v := range sl {
+		r, err := CallVMFunc(env, f, v)
+		if err != nil {
+			return nil, err
+		}
+		b, ok := r.(VMBooler)
+		if !ok {
+			return nil, VMErrorNeedBool
+		}
+		if b.Bool() {
+			rv = append(rv, v)
+		}
+	}
+	return rv, nil
+}
+
+// TakeWhileVMSlice возвращает начальные элементы массива до первого, для которого предикат вернул Ложь.
+// При drop возвращаются остальные элементы, начиная с этого первого
+func TakeWhileVMSlice(env *Env, sl VMSlice, f VMFunc, drop bool) (VMSlice, error) {
+	n := len(sl)
+	for i, v := range sl {
+		r, err := CallVMFunc(env, f, v)
+		if err != nil {
+			return nil, err
+		}
+		b, ok := r.(VMBooler)
+		if !ok {
+			return nil, VMErrorNeedBool
+		}
+		if !b.Bool() {
+			n = i
+			break
+		}
+	}
+	if drop {
+		return append(VMSlice{}, sl[n:]...), nil
+	}
+	return append(VMSlice{}, sl[:n]...), nil
+}
+
+// FoldVMSlice сворачивает массив слева направо, передавая в функцию аккумулятор и очередной элемент,
+// результат каждого вызова становится новым значением аккумулятора
+func FoldVMSlice(env *Env, sl VMSlice, init VMValuer, f VMFunc) (VMValuer, error) {
+	acc := init
+	for _, v := range sl {
+		r, err := CallVMFunc(env, f, acc, v)
+		if err != nil {
+			return VMNil, err
+		}
+		acc = r
+	}
+	return acc, nil
+}
+
+// PartialVMFunc возвращает функцию, которая вызывает исходную с заранее заданными первыми аргументами,
+// за которыми следуют аргументы вызова
+func PartialVMFunc(f VMFunc, bound VMSlice) VMFunc {
+	bound = append(VMSlice{}, bound...)
+	return func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		all := make(VMSlice, 0, len(bound)+len(args))
+		all = append(all, bound...)
+		all = append(all, args...)
+		return f(all, rets, envout)
+	}
+}
+
+// ComposeVMFuncs возвращает композицию функций, которые применяются справа налево, как в математической записи:
+// композиция (f, g) для x вычисляет f(g(x)). Последняя функция получает все аргументы вызова,
+// каждая следующая - результат предыдущей
+func ComposeVMFuncs(fs []VMFunc) VMFunc {
+	fs = append([]VMFunc{}, fs...)
+	return func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		last := len(fs) - 1
+		if last == 0 {
+			return fs[0](args, rets, envout)
+		}
+		caller := callerEnv(nil, envout)
+		v, err := CallVMFunc(caller, fs[last], args...)
+		if err != nil {
+			return err
+		}
+		for i := last - 1; i > 0; i-- {
+			if v, err = CallVMFunc(caller, fs[i], v); err != nil {
+				return err
+			}
+		}
+		return fs[0](VMSlice{v}, rets, envout)
+	}
+}
+
+// ThrottleVMFunc возвращает функцию, которая вызывает исходную не чаще одного раза за интервал d.
+// Вызовы, пришедшие раньше, чем через d после последнего выполненного, отбрасываются и возвращают Неопределено,
+// в очередь они не ставятся. Функция безопасна для вызова из нескольких горутин
+func ThrottleVMFunc(f VMFunc, d time.Duration) VMFunc {
+	return throttleVMFunc(f, d, time.Now)
+}
+
+// throttleVMFunc - ThrottleVMFunc с источником текущего времени clock
+func throttleVMFunc(f VMFunc, d time.Duration, clock func() time.Time) VMFunc {
+	var mu sync.Mutex
+	var last time.Time
+	return func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		mu.Lock()
+		now := clock()
+		if !last.IsZero() && now.Sub(last) < d {
+			mu.Unlock()
+			rets.Append(VMNil)
+			return nil
+		}
+		last = now
+		mu.Unlock()
+		return f(args, rets, envout)
+	}
+}
+
+// GroupVMSlice группирует элементы по ключу, который функция возвращает для каждого элемента.
+// Ключ приводится к строке, элементы в группах идут в порядке исходного массива
+func GroupVMSlice(env *Env, sl VMSlice, f VMFunc) (VMStringMap, error) {
+	rv := make(VMStringMap)
+	for _, v := range sl {
+		r, err := CallVMFunc(env, f, v)
+		if err != nil {
+			return nil, err
+		}
+		k, ok := r.(VMStringer)
+		if !ok {
+			return nil, VMErrorNeedString
+		}
+		g, _ := rv[k.String()].(VMSlice)
+		rv[k.String()] = append(g, v)
+	}
+	return rv, nil
+}
+
+// RetryVMFunc вызывает функцию без параметров до n раз, пока она не завершится без ошибки, и возвращает ее результат.
+// Между попытками выдерживается пауза d. Если все попытки неудачны, возвращается ошибка последней из них.
+// Попытки прекращаются досрочно при отмене контекста или исчерпании лимита инструкций окружения,
+// так как повтор в этих случаях все равно завершится ошибкой
+func RetryVMFunc(env *Env, n int, d time.Duration, f VMFunc) (VMValuer, error) {
+	ctx := env.Context()
+	var err error
+	for i := 0; i < n; i++ {
+		if i > 0 && d > 0 {
+			t := time.NewTimer(d)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return VMNil, err
+			case <-t.C:
+			}
+		}
+		var rv VMValuer
+		if rv, err = CallVMFunc(env, f); err == nil {
+			return rv, nil
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		if left, limited := env.InstructionsLeft(); limited && left == 0 {
+			break
+		}
+	}
+	return VMNil, err
+}
+
+// MemoizeVMFunc возвращает функцию, которая запоминает результаты вызовов исходной функции по значениям аргументов.
+// Ключом служит каноническое двоичное представление массива аргументов, в котором ключи структур упорядочены,
+// поэтому равные по содержимому аргументы дают один ключ. Вызовы с аргументами, которые нельзя сериализовать,
+// и вызовы, завершившиеся ошибкой, не запоминаются. Запомненные массивы и структуры возвращаются копиями,
+// чтобы их изменение вызывающим кодом не меняло последующие результаты. Функция безопасна для вызова из нескольких горутин
+func MemoizeVMFunc(f VMFunc) VMFunc {
+	var mu sync.Mutex
+	cache := make(map[string]VMSlice)
+	return func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		b, err := args.MarshalBinary()
+		if err != nil {
+			return f(args, rets, envout)
+		}
+		key := string(b)
+		mu.Lock()
+		rv, ok := cache[key]
+		mu.Unlock()
+		if ok {
+			// вызывающий код может изменить полученные массивы и структуры, поэтому отдаем копию
+			*rets = append(*rets, rv.CopyRecursive()...)
+			return nil
+		}
+		// функция может заменить массив возвратов, поэтому вызываем ее с отдельным массивом
+		var res VMSlice
+		if err := f(args, &res, envout); err != nil {
+			return err
+		}
+		rv = res.CopyRecursive()
+		mu.Lock()
+		cache[key] = rv
+		mu.Unlock()
+		*rets = append(*rets, res...)
+		return nil
+	}
+}
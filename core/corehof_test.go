@@ -0,0 +1,45 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestThrottleVMFunc(t *testing.T) {
+	calls := 0
+	f := VMFunc(func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		calls++
+		rets.Append(VMInt(calls))
+		return nil
+	})
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	th := throttleVMFunc(f, 20*time.Millisecond, func() time.Time { return now })
+
+	// время задается явно, поэтому результат не зависит от загрузки машины
+	steps := []struct {
+		after time.Duration
+		want  VMValuer
+	}{
+		{0, VMInt(1)},
+		{10 * time.Millisecond, VMNil},
+		{10 * time.Millisecond, VMInt(2)},
+		{19 * time.Millisecond, VMNil},
+		{time.Millisecond, VMInt(3)},
+		{time.Hour, VMInt(4)},
+		{0, VMNil},
+	}
+	for i, st := range steps {
+		now = now.Add(st.after)
+		var rets VMSlice
+		var env *Env
+		if err := th(nil, &rets, &env); err != nil {
+			t.Fatal(err)
+		}
+		if len(rets) != 1 || rets[0] != st.want {
+			t.Errorf("вызов %d: %v, ожидалось %v", i+1, rets, st.want)
+		}
+	}
+	if calls != 4 {
+		t.Errorf("выполнено вызовов %d, ожидалось 4", calls)
+	}
+}
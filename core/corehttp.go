@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/shinanca/gonec/names"
 )
@@ -376,3 +378,37 @@ func (x *VMHttpResponse) Сообщение(args VMSlice, rets *VMSlice, envout
 	rets.Append(v)
 	return nil
 }
+
+// HTTPRequestTimeout таймаут по умолчанию для HTTPЗапрос
+const HTTPRequestTimeout = 30 * time.Second
+
+// HTTPRequest выполняет http запрос и возвращает структуру с ключами КодСтатуса, Заголовки и Тело.
+// Ответы с кодом, отличным от 2xx, ошибкой не считаются - код возвращается в структуре.
+func HTTPRequest(method, urlstr string, headers VMStringMap, body string, timeout time.Duration) (VMStringMap, error) {
+	req, err := http.NewRequest(strings.ToUpper(method), urlstr, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, fmt.Sprint(v))
+	}
+	cl := &http.Client{Timeout: timeout}
+	resp, err := cl.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	h := make(VMStringMap, len(resp.Header))
+	for k, v := range resp.Header {
+		h[k] = VMString(strings.Join(v, ", "))
+	}
+	return VMStringMap{
+		"КодСтатуса": VMInt(resp.StatusCode),
+		"Заголовки":  h,
+		"Тело":       VMString(b),
+	}, nil
+}
@@ -0,0 +1,47 @@
+package core
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.Header().Set("X-Test", r.Header.Get("X-Test"))
+			fmt.Fprintf(w, "%s привет", r.Method)
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		default:
+			http.Error(w, "нет", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	rv, err := HTTPRequest("get", srv.URL+"/ok", VMStringMap{"X-Test": VMString("1")}, "", HTTPRequestTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rv["КодСтатуса"] != VMInt(200) || rv["Тело"] != VMString("GET привет") {
+		t.Errorf("HTTPRequest() = %v", rv)
+	}
+	if h := rv["Заголовки"].(VMStringMap); h["X-Test"] != VMString("1") {
+		t.Errorf("Заголовки = %v", h)
+	}
+
+	rv, err = HTTPRequest("GET", srv.URL+"/missing", nil, "", HTTPRequestTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rv["КодСтатуса"] != VMInt(404) {
+		t.Errorf("КодСтатуса = %v, want 404", rv["КодСтатуса"])
+	}
+
+	if _, err = HTTPRequest("GET", srv.URL+"/slow", nil, "", 50*time.Millisecond); err == nil {
+		t.Error("ожидалась ошибка таймаута")
+	}
+}
@@ -83,6 +83,11 @@ type (
 		InvokeNumber() (VMNumberer, error) // извлекает VMInt или VMDecNum, в зависимости от наличия .eE
 	}
 
+	// VMNumberInvoker может быть приведен к числу - это числа и строки с записью числа
+	VMNumberInvoker interface {
+		InvokeNumber() (VMNumberer, error)
+	}
+
 	// VMBooler сообщает значение булево
 	VMBooler interface {
 		VMInterfacer
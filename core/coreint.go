@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
 	"strconv"
 	"strings"
@@ -63,6 +64,22 @@ func (x VMInt) String() string {
 	return strconv.FormatInt(int64(x), 10)
 }
 
+// ZeroPad возвращает число, дополненное слева нулями до ширины width. Знак минуса ставится перед нулями
+// и входит в ширину, более длинные числа не обрезаются. Ширина больше MaxVMLen - ошибка
+func (x VMInt) ZeroPad(width int) (string, error) {
+	if width > MaxVMLen {
+		return "", VMErrorTooLarge
+	}
+	s := strconv.FormatInt(int64(x), 10)
+	if len(s) >= width {
+		return s, nil
+	}
+	if x < 0 {
+		return "-" + strings.Repeat("0", width-len(s)) + s[1:], nil
+	}
+	return strings.Repeat("0", width-len(s)) + s, nil
+}
+
 func (x VMInt) Int() int64 {
 	return int64(x)
 }
@@ -126,12 +143,79 @@ func (x VMInt) EvalUnOp(op rune) (VMValuer, error) {
 	}
 }
 
+// checkedAdd, checkedSub и checkedMul выполняют операцию над 64-битными целыми,
+// при выходе за пределы диапазона возвращается ошибка, а не усеченное значение
+
+func (x VMInt) checkedAdd(y VMInt) (VMValuer, error) {
+	r := x + y
+	if (y > 0 && r < x) || (y < 0 && r > x) {
+		return VMNil, VMErrorIntOverflow
+	}
+	return r, nil
+}
+
+func (x VMInt) checkedSub(y VMInt) (VMValuer, error) {
+	r := x - y
+	if (y > 0 && r > x) || (y < 0 && r < x) {
+		return VMNil, VMErrorIntOverflow
+	}
+	return r, nil
+}
+
+func (x VMInt) checkedMul(y VMInt) (VMValuer, error) {
+	if x == 0 || y == 0 {
+		return VMInt(0), nil
+	}
+	r := x * y
+	if r/y != x || (x == -1 && y == math.MinInt64) || (y == -1 && x == math.MinInt64) {
+		return VMNil, VMErrorIntOverflow
+	}
+	return r, nil
+}
+
+// powInt возводит в целую неотрицательную степень без перевода во float64.
+// Пока результат помещается в 64 бита, он точный, иначе вычисляется в десятичных числах.
+// Результат вне диапазона десятичных чисел - ошибка переполнения
+func (x VMInt) powInt(y VMInt) (VMValuer, error) {
+	r, b, n := VMInt(1), x, y
+	for n > 0 {
+		if n&1 == 1 {
+			v, err := r.checkedMul(b)
+			if err != nil {
+				return powDecNum(x, y)
+			}
+			r = v.(VMInt)
+		}
+		n >>= 1
+		if n > 0 {
+			v, err := b.checkedMul(b)
+			if err != nil {
+				return powDecNum(x, y)
+			}
+			b = v.(VMInt)
+		}
+	}
+	return NewVMDecNumFromInt64(int64(r)), nil
+}
+
+// powDecNum возводит целое в степень, результат которой не помещается в 64 бита
+func powDecNum(x, y VMInt) (VMValuer, error) {
+	rv := NewVMDecNumFromInt64(int64(x)).powInt(y)
+	if !rv.num.IsFinite() {
+		return VMNil, VMErrorNumberOverflow
+	}
+	return rv, nil
+}
+
 func (x VMInt) EvalBinOp(op VMOperation, y VMOperationer) (VMValuer, error) {
+	if yy, ok := y.(VMBigInt); ok {
+		return NewVMBigIntFromInt64(int64(x)).EvalBinOp(op, yy)
+	}
 	switch op {
 	case ADD:
 		switch yy := y.(type) {
 		case VMInt:
-			return VMInt(int64(x) + int64(yy)), nil
+			return x.checkedAdd(yy)
 		case VMDecNum:
 			return NewVMDecNumFromInt64(int64(x)).Add(yy), nil
 		}
@@ -139,7 +223,7 @@ func (x VMInt) EvalBinOp(op VMOperation, y VMOperationer) (VMValuer, error) {
 	case SUB:
 		switch yy := y.(type) {
 		case VMInt:
-			return VMInt(int64(x) - int64(yy)), nil
+			return x.checkedSub(yy)
 		case VMDecNum:
 			return NewVMDecNumFromInt64(int64(x)).Sub(yy), nil
 		}
@@ -147,7 +231,7 @@ func (x VMInt) EvalBinOp(op VMOperation, y VMOperationer) (VMValuer, error) {
 	case MUL:
 		switch yy := y.(type) {
 		case VMInt:
-			return VMInt(int64(x) * int64(yy)), nil
+			return x.checkedMul(yy)
 		case VMDecNum:
 			return NewVMDecNumFromInt64(int64(x)).Mul(yy), nil
 		}
@@ -237,7 +321,9 @@ func (x VMInt) EvalBinOp(op VMOperation, y VMOperationer) (VMValuer, error) {
 	case POW:
 		switch yy := y.(type) {
 		case VMInt:
-			// TODO: переделать на math
+			if yy >= 0 {
+				return x.powInt(yy)
+			}
 			return NewVMDecNumFromInt64(int64(x)).Pow(NewVMDecNumFromInt64(int64(yy))), nil
 		case VMDecNum:
 			return NewVMDecNumFromInt64(int64(x)).Pow(yy), nil
@@ -269,7 +355,8 @@ func (x VMInt) ConvertToType(nt reflect.Type) (VMValuer, error) {
 	case ReflectVMTimeDuration:
 		return x.Duration(), nil
 	case ReflectVMBool:
-		return VMBool(x.Bool()), nil
+		// при явном приведении ложь только для нуля
+		return VMBool(x != 0), nil
 	case ReflectVMString:
 		return VMString(x.String()), nil
 	case ReflectVMDecNum:
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVMIntOverflow(t *testing.T) {
+	tests := []struct {
+		name    string
+		x, y    VMInt
+		op      VMOperation
+		want    VMValuer
+		wantErr bool
+	}{
+		{name: "сложение на границе", x: math.MaxInt64 - 1, y: 1, op: ADD, want: VMInt(math.MaxInt64)},
+		{name: "сложение с переполнением", x: math.MaxInt64, y: 1, op: ADD, wantErr: true},
+		{name: "сложение отрицательных", x: math.MinInt64, y: -1, op: ADD, wantErr: true},
+		{name: "вычитание на границе", x: math.MinInt64 + 1, y: 1, op: SUB, want: VMInt(math.MinInt64)},
+		{name: "вычитание с переполнением", x: math.MinInt64, y: 1, op: SUB, wantErr: true},
+		{name: "вычитание отрицательного", x: math.MaxInt64, y: -1, op: SUB, wantErr: true},
+		{name: "умножение", x: 3037000499, y: 3037000499, op: MUL, want: VMInt(9223372030926249001)},
+		{name: "умножение с переполнением", x: 3037000500, y: 3037000500, op: MUL, wantErr: true},
+		{name: "умножение минимального на -1", x: math.MinInt64, y: -1, op: MUL, wantErr: true},
+		{name: "умножение на ноль", x: math.MinInt64, y: 0, op: MUL, want: VMInt(0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.x.EvalBinOp(tt.op, tt.y)
+			if tt.wantErr {
+				if err != VMErrorIntOverflow {
+					t.Errorf("ошибка = %v, ожидалось переполнение", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("результат = %v, ожидался %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVMIntPow(t *testing.T) {
+	tests := []struct {
+		x, y VMInt
+		want string
+	}{
+		{x: 2, y: 62, want: "4611686018427387904"},
+		{x: 2, y: 63, want: "9223372036854775808"},
+		{x: 2, y: 64, want: "18446744073709551616"},
+		{x: -2, y: 63, want: "-9223372036854775808"},
+		{x: 7, y: 40, want: "6366805760909027985741435139224001"},
+		{x: 0, y: 0, want: "1"},
+		{x: 2, y: -1, want: "0.5"},
+	}
+	for _, tt := range tests {
+		got, err := tt.x.EvalBinOp(POW, tt.y)
+		if err != nil {
+			t.Errorf("%d**%d: %v", tt.x, tt.y, err)
+			continue
+		}
+		if got.(VMDecNum).String() != tt.want {
+			t.Errorf("%d**%d = %v, ожидалось %s", tt.x, tt.y, got, tt.want)
+		}
+	}
+	// результат вне диапазона десятичных чисел
+	if _, err := VMInt(10).EvalBinOp(POW, VMInt(7000)); err != VMErrorNumberOverflow {
+		t.Errorf("10**7000: ошибка = %v, ожидалось переполнение", err)
+	}
+}
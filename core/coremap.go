@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"reflect"
+	"sort"
 
 	"github.com/shinanca/gonec/names"
 )
@@ -123,6 +124,27 @@ func (x VMStringMap) CopyRecursive() VMStringMap {
 	return rv
 }
 
+// MergeVMStringMaps возвращает новую структуру из пар всех структур, значения более поздних структур
+// заменяют значения с теми же ключами. При deep вложенные структуры с одинаковым ключом сливаются рекурсивно,
+// исходные структуры не изменяются
+func MergeVMStringMaps(deep bool, maps ...VMStringMap) VMStringMap {
+	rv := make(VMStringMap)
+	for _, m := range maps {
+		for k, v := range m {
+			if deep {
+				old, ok1 := rv[k].(VMStringMap)
+				vm, ok2 := v.(VMStringMap)
+				if ok1 && ok2 {
+					rv[k] = MergeVMStringMaps(true, old, vm)
+					continue
+				}
+			}
+			rv[k] = v
+		}
+	}
+	return rv
+}
+
 func (x VMStringMap) Скопировать(args VMSlice, rets *VMSlice, envout *(*Env)) error {
 	rv := x.CopyRecursive()
 	rets.Append(rv)
@@ -334,7 +356,13 @@ func (x VMStringMap) ConvertToType(nt reflect.Type) (VMValuer, error) {
 func (x VMStringMap) MarshalBinary() ([]byte, error) {
 	var buf bytes.Buffer
 	binary.Write(&buf, binary.LittleEndian, uint64(len(x))) //количество пар ключ-значение
-	for i := range x {
+	// ключи пишутся по возрастанию, чтобы одинаковые структуры давали одинаковые байты и хеш
+	keys := make([]string, 0, len(x))
+	for k := range x {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, i := range keys {
 		if v, ok := x[i].(VMBinaryTyper); ok {
 			bb, err := v.MarshalBinary()
 			if err != nil {
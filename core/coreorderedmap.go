@@ -0,0 +1,268 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/shinanca/gonec/names"
+)
+
+// VMOrderedMap структура, сохраняющая порядок добавления ключей: УпорядоченнаяСтруктура().
+// Обход, ключи, значения и сериализация в JSON идут в порядке добавления,
+// присваивание существующему ключу не меняет его позицию
+type VMOrderedMap struct {
+	keys []string
+	vals map[string]VMValuer
+}
+
+var ReflectVMOrderedMap = reflect.TypeOf(&VMOrderedMap{})
+
+func NewVMOrderedMap() *VMOrderedMap {
+	return &VMOrderedMap{vals: make(map[string]VMValuer)}
+}
+
+// NewVMOrderedMapFromStringMap создает упорядоченную структуру из обычной, ключи добавляются по возрастанию
+func NewVMOrderedMapFromStringMap(m VMStringMap) *VMOrderedMap {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	x := NewVMOrderedMap()
+	for _, k := range keys {
+		x.Set(k, m[k])
+	}
+	return x
+}
+
+// VMOrderedMapFromJson разбирает объект JSON, сохраняя порядок ключей, в том числе во вложенных объектах
+func VMOrderedMapFromJson(s string) (*VMOrderedMap, error) {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	v, err := orderedFromJSON(dec)
+	if err != nil {
+		return nil, err
+	}
+	if m, ok := v.(*VMOrderedMap); ok {
+		return m, nil
+	}
+	return nil, VMErrorNeedMap
+}
+
+func orderedFromJSON(dec *json.Decoder) (VMValuer, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '{' {
+			m := NewVMOrderedMap()
+			for dec.More() {
+				kt, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				v, err := orderedFromJSON(dec)
+				if err != nil {
+					return nil, err
+				}
+				m.Set(kt.(string), v)
+			}
+			_, err = dec.Token()
+			return m, err
+		}
+		sl := VMSlice{}
+		for dec.More() {
+			v, err := orderedFromJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			sl = append(sl, v)
+		}
+		_, err = dec.Token()
+		return sl, err
+	case json.Number:
+		return VMValuerFromJSON(t.String())
+	case string:
+		return VMString(t), nil
+	case bool:
+		return VMBool(t), nil
+	}
+	return VMNil, nil
+}
+
+func (x *VMOrderedMap) vmval() {}
+
+func (x *VMOrderedMap) Interface() interface{} {
+	return x
+}
+
+// Set устанавливает значение ключа, новый ключ добавляется в конец
+func (x *VMOrderedMap) Set(k string, v VMValuer) {
+	if _, ok := x.vals[k]; !ok {
+		x.keys = append(x.keys, k)
+	}
+	x.vals[k] = v
+}
+
+// Get возвращает значение ключа и признак его наличия
+func (x *VMOrderedMap) Get(k string) (VMValuer, bool) {
+	v, ok := x.vals[k]
+	return v, ok
+}
+
+// Delete удаляет ключ, порядок остальных ключей сохраняется
+func (x *VMOrderedMap) Delete(k string) {
+	if _, ok := x.vals[k]; !ok {
+		return
+	}
+	delete(x.vals, k)
+	for i, kk := range x.keys {
+		if kk == k {
+			x.keys = append(x.keys[:i], x.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+func (x *VMOrderedMap) Length() VMInt {
+	return VMInt(len(x.keys))
+}
+
+func (x *VMOrderedMap) IndexVal(i VMValuer) VMValuer {
+	if ii, ok := i.(VMStringer); ok {
+		if v, ok := x.vals[ii.String()]; ok {
+			return v
+		}
+		return VMNil
+	}
+	panic("Индекс должен быть строкой")
+}
+
+// Keys возвращает ключи в порядке добавления
+func (x *VMOrderedMap) Keys() VMSlice {
+	rv := make(VMSlice, len(x.keys))
+	for i, k := range x.keys {
+		rv[i] = VMString(k)
+	}
+	return rv
+}
+
+// Slice возвращает ключи в порядке добавления, используется для обхода в цикле Для каждого
+func (x *VMOrderedMap) Slice() VMSlice {
+	return x.Keys()
+}
+
+// StringMap возвращает копию значений в виде обычной структуры без порядка ключей
+func (x *VMOrderedMap) StringMap() VMStringMap {
+	rv := make(VMStringMap, len(x.vals))
+	for k, v := range x.vals {
+		rv[k] = v
+	}
+	return rv
+}
+
+func (x *VMOrderedMap) String() string {
+	b, err := json.Marshal(x)
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
+
+func (x *VMOrderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range x.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		kb, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(kb)
+		buf.WriteByte(':')
+		vb, err := json.Marshal(x.vals[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(vb)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (x *VMOrderedMap) EvalBinOp(op VMOperation, y VMOperationer) (VMValuer, error) {
+	switch op {
+	case EQL, NEQ:
+		yy, ok := y.(*VMOrderedMap)
+		if !ok {
+			return VMNil, VMErrorIncorrectOperation
+		}
+		eq := len(x.keys) == len(yy.keys)
+		for i := 0; eq && i < len(x.keys); i++ {
+			eq = x.keys[i] == yy.keys[i] && EqualVMValues(x.vals[x.keys[i]], yy.vals[yy.keys[i]])
+		}
+		if op == NEQ {
+			return VMBool(!eq), nil
+		}
+		return VMBool(eq), nil
+	case ADD, SUB, MUL, QUO, REM, GTR, GEQ, LSS, LEQ, OR, LOR, AND, LAND, POW, SHR, SHL:
+		return VMNil, VMErrorIncorrectOperation
+	}
+	return VMNil, VMErrorUnknownOperation
+}
+
+func (x *VMOrderedMap) ConvertToType(nt reflect.Type) (VMValuer, error) {
+	switch nt {
+	case ReflectVMString:
+		return VMString(x.String()), nil
+	case ReflectVMStringMap:
+		return x.StringMap(), nil
+	}
+	return VMNil, VMErrorNotConverted
+}
+
+func (x *VMOrderedMap) MethodMember(name int) (VMFunc, bool) {
+
+	// только эти методы будут доступны из кода на языке Гонец!
+	switch names.UniqueNames.GetLowerCase(name) {
+	case "ключи":
+		return VMFuncMustParams(0, x.Ключи), true
+	case "значения":
+		return VMFuncMustParams(0, x.Значения), true
+	case "удалить":
+		return VMFuncMustParams(1, x.Удалить), true
+	}
+	return nil, false
+}
+
+// Ключи возвращаются в порядке добавления
+func (x *VMOrderedMap) Ключи(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+	rets.Append(x.Keys())
+	return nil
+}
+
+// Значения возвращаются в порядке добавления ключей
+func (x *VMOrderedMap) Значения(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+	rv := make(VMSlice, len(x.keys))
+	for i, k := range x.keys {
+		rv[i] = x.vals[k]
+	}
+	rets.Append(rv)
+	return nil
+}
+
+func (x *VMOrderedMap) Удалить(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+	p, ok := args[0].(VMString)
+	if !ok {
+		return VMErrorNeedString
+	}
+	x.Delete(string(p))
+	return nil
+}
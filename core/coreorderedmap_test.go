@@ -0,0 +1,54 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestVMOrderedMap(t *testing.T) {
+	m := NewVMOrderedMap()
+	m.Set("в", VMInt(1))
+	m.Set("а", VMInt(2))
+	m.Set("б", VMInt(3))
+	m.Set("в", VMInt(4))
+	if got := m.Keys().String(); got != `["в","а","б"]` {
+		t.Errorf("ключи = %s", got)
+	}
+	if v, ok := m.Get("в"); !ok || v != VMInt(4) {
+		t.Errorf("значение в = %v", v)
+	}
+	m.Delete("а")
+	m.Delete("нет")
+	if m.Length() != 2 {
+		t.Errorf("длина = %d, ожидалась 2", m.Length())
+	}
+
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{name: "плоский", src: `{"я": 1, "а": "б", "м": true}`, want: `{"я":1,"а":"б","м":true}`},
+		{name: "вложенный", src: `{"я": {"ю": [1, {"щ": 2, "б": 3}], "а": null}, "б": []}`, want: `{"я":{"ю":[1,{"щ":2,"б":3}],"а":null},"б":[]}`},
+		{name: "пустой", src: `{}`, want: `{}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			om, err := VMOrderedMapFromJson(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := json.Marshal(om)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("получено %s, ожидалось %s", b, tt.want)
+			}
+		})
+	}
+
+	if _, err := VMOrderedMapFromJson(`[1]`); err != VMErrorNeedMap {
+		t.Errorf("массив: ошибка %v", err)
+	}
+}
@@ -0,0 +1,321 @@
+package core
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/shinanca/gonec/names"
+)
+
+// VMSet множество уникальных значений: Множество(1, 2, "а").
+// Элементами могут быть строки, целые числа, булево, а также массивы и структуры из таких значений.
+// Массивы и структуры сравниваются по содержимому, в множество добавляется их копия.
+// Проверка вхождения, добавление и удаление выполняются за O(1), операции над множествами - за O(n)
+type VMSet struct {
+	m map[interface{}]VMValuer // ключ сравнения -> элемент
+}
+
+var ReflectVMSet = reflect.TypeOf(&VMSet{})
+
+// NewVMSet создает множество из значений, повторяющиеся значения добавляются один раз
+func NewVMSet(vals ...VMValuer) (*VMSet, error) {
+	x := &VMSet{m: make(map[interface{}]VMValuer, len(vals))}
+	for _, v := range vals {
+		if err := x.Add(v); err != nil {
+			return nil, err
+		}
+	}
+	return x, nil
+}
+
+func (x *VMSet) vmval() {}
+
+func (x *VMSet) Interface() interface{} {
+	return x
+}
+
+// setCompositeKey ключ сравнения массива или структуры - тип и каноническое двоичное представление,
+// в котором ключи структур упорядочены, поэтому равные по содержимому значения дают равные ключи
+type setCompositeKey struct {
+	t VMBinaryType
+	b string
+}
+
+// setKey проверяет, что значение может быть элементом множества, и возвращает ключ сравнения
+func setKey(v VMValuer) (interface{}, error) {
+	switch vv := v.(type) {
+	case VMString, VMInt, VMBool:
+		return v, nil
+	case VMSlice, VMStringMap:
+		if !setComposable(vv) {
+			return nil, VMErrorSetElement
+		}
+		b, err := vv.(VMBinaryTyper).MarshalBinary()
+		if err != nil {
+			return nil, VMErrorSetElement
+		}
+		return setCompositeKey{t: vv.(VMBinaryTyper).BinaryType(), b: string(b)}, nil
+	}
+	return nil, VMErrorSetElement
+}
+
+// setComposable проверяет, что массив или структура состоят только из допустимых элементов множества
+func setComposable(v VMValuer) bool {
+	switch vv := v.(type) {
+	case VMString, VMInt, VMBool:
+		return true
+	case VMSlice:
+		for _, e := range vv {
+			if !setComposable(e) {
+				return false
+			}
+		}
+		return true
+	case VMStringMap:
+		for _, e := range vv {
+			if !setComposable(e) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// Add добавляет значение, повторное добавление ничего не меняет.
+// Массивы и структуры копируются, чтобы их последующее изменение не нарушало множество
+func (x *VMSet) Add(v VMValuer) error {
+	k, err := setKey(v)
+	if err != nil {
+		return err
+	}
+	if _, ok := x.m[k]; ok {
+		return nil
+	}
+	switch vv := v.(type) {
+	case VMSlice:
+		v = vv.CopyRecursive()
+	case VMStringMap:
+		v = vv.CopyRecursive()
+	}
+	x.m[k] = v
+	return nil
+}
+
+// Delete удаляет значение, если оно есть в множестве
+func (x *VMSet) Delete(v VMValuer) error {
+	k, err := setKey(v)
+	if err != nil {
+		return err
+	}
+	delete(x.m, k)
+	return nil
+}
+
+// Has проверяет вхождение значения, для значений, которые не могут быть элементами, возвращает false
+func (x *VMSet) Has(v VMValuer) bool {
+	k, err := setKey(v)
+	if err != nil {
+		return false
+	}
+	_, ok := x.m[k]
+	return ok
+}
+
+func (x *VMSet) Length() VMInt {
+	return VMInt(len(x.m))
+}
+
+// IndexVal возвращает Истина, если значение входит в множество
+func (x *VMSet) IndexVal(i VMValuer) VMValuer {
+	return VMBool(x.Has(i))
+}
+
+// Slice возвращает элементы в отсортированном порядке, используется и для обхода в цикле Для каждого.
+// Сначала идут булевы значения, затем числа, затем строки, затем массивы и структуры
+// в порядке их двоичного представления
+func (x *VMSet) Slice() VMSlice {
+	keys := make([]interface{}, 0, len(x.m))
+	for k := range x.m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		vi, vj := x.m[keys[i]], x.m[keys[j]]
+		ri, rj := setKeyRank(vi), setKeyRank(vj)
+		if ri != rj {
+			return ri < rj
+		}
+		switch ki := keys[i].(type) {
+		case VMBool:
+			return !bool(ki) && bool(keys[j].(VMBool))
+		case setCompositeKey:
+			kj := keys[j].(setCompositeKey)
+			if ki.t != kj.t {
+				return ki.t < kj.t
+			}
+			return ki.b < kj.b
+		}
+		return SortLessVMValues(vi, vj)
+	})
+	rv := make(VMSlice, len(keys))
+	for i, k := range keys {
+		rv[i] = x.m[k]
+	}
+	return rv
+}
+
+func setKeyRank(v VMValuer) int {
+	switch v.(type) {
+	case VMBool:
+		return 0
+	case VMInt:
+		return 1
+	case VMString:
+		return 2
+	}
+	return 3
+}
+
+// Union возвращает новое множество из элементов обоих множеств
+func (x *VMSet) Union(y *VMSet) *VMSet {
+	rv := &VMSet{m: make(map[interface{}]VMValuer, len(x.m)+len(y.m))}
+	for k, v := range x.m {
+		rv.m[k] = v
+	}
+	for k, v := range y.m {
+		rv.m[k] = v
+	}
+	return rv
+}
+
+// Intersect возвращает новое множество из элементов, входящих в оба множества
+func (x *VMSet) Intersect(y *VMSet) *VMSet {
+	if len(y.m) < len(x.m) {
+		x, y = y, x
+	}
+	rv := &VMSet{m: make(map[interface{}]VMValuer)}
+	for k, v := range x.m {
+		if _, ok := y.m[k]; ok {
+			rv.m[k] = v
+		}
+	}
+	return rv
+}
+
+// Difference возвращает новое множество из элементов x, не входящих в y
+func (x *VMSet) Difference(y *VMSet) *VMSet {
+	rv := &VMSet{m: make(map[interface{}]VMValuer)}
+	for k, v := range x.m {
+		if _, ok := y.m[k]; !ok {
+			rv.m[k] = v
+		}
+	}
+	return rv
+}
+
+func (x *VMSet) String() string {
+	return x.Slice().String()
+}
+
+func (x *VMSet) EvalBinOp(op VMOperation, y VMOperationer) (VMValuer, error) {
+	switch op {
+	case EQL, NEQ:
+		yy, ok := y.(*VMSet)
+		if !ok {
+			return VMNil, VMErrorIncorrectOperation
+		}
+		eq := len(x.m) == len(yy.m)
+		if eq {
+			for k := range x.m {
+				if _, ok := yy.m[k]; !ok {
+					eq = false
+					break
+				}
+			}
+		}
+		if op == NEQ {
+			return VMBool(!eq), nil
+		}
+		return VMBool(eq), nil
+	case ADD, SUB, MUL, QUO, REM, GTR, GEQ, LSS, LEQ, OR, LOR, AND, LAND, POW, SHR, SHL:
+		return VMNil, VMErrorIncorrectOperation
+	}
+	return VMNil, VMErrorUnknownOperation
+}
+
+func (x *VMSet) ConvertToType(nt reflect.Type) (VMValuer, error) {
+	switch nt {
+	case ReflectVMString:
+		return VMString(x.String()), nil
+	case ReflectVMSlice:
+		return x.Slice(), nil
+	}
+	return VMNil, VMErrorNotConverted
+}
+
+func (x *VMSet) MethodMember(name int) (VMFunc, bool) {
+
+	// только эти методы будут доступны из кода на языке Гонец!
+	switch names.UniqueNames.GetLowerCase(name) {
+	case "добавить":
+		return VMFuncMustParams(1, x.Добавить), true
+	case "удалить":
+		return VMFuncMustParams(1, x.Удалить), true
+	case "содержит":
+		return VMFuncMustParams(1, x.Содержит), true
+	case "объединение":
+		return VMFuncMustParams(1, x.Объединение), true
+	case "пересечение":
+		return VMFuncMustParams(1, x.Пересечение), true
+	case "разность":
+		return VMFuncMustParams(1, x.Разность), true
+	case "элементы":
+		return VMFuncMustParams(0, x.Элементы), true
+	}
+	return nil, false
+}
+
+func (x *VMSet) Добавить(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+	return x.Add(args[0])
+}
+
+func (x *VMSet) Удалить(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+	return x.Delete(args[0])
+}
+
+func (x *VMSet) Содержит(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+	rets.Append(VMBool(x.Has(args[0])))
+	return nil
+}
+
+func (x *VMSet) Объединение(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+	y, ok := args[0].(*VMSet)
+	if !ok {
+		return VMErrorNeedSet
+	}
+	rets.Append(x.Union(y))
+	return nil
+}
+
+func (x *VMSet) Пересечение(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+	y, ok := args[0].(*VMSet)
+	if !ok {
+		return VMErrorNeedSet
+	}
+	rets.Append(x.Intersect(y))
+	return nil
+}
+
+func (x *VMSet) Разность(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+	y, ok := args[0].(*VMSet)
+	if !ok {
+		return VMErrorNeedSet
+	}
+	rets.Append(x.Difference(y))
+	return nil
+}
+
+func (x *VMSet) Элементы(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+	rets.Append(x.Slice())
+	return nil
+}
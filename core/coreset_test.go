@@ -0,0 +1,90 @@
+package core
+
+import "testing"
+
+func TestVMSet(t *testing.T) {
+	set := func(vals ...VMValuer) *VMSet {
+		s, err := NewVMSet(vals...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	s := set(VMString("а"), VMInt(1))
+	if err := s.Add(VMInt(2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add(VMInt(2)); err != nil {
+		t.Fatal(err)
+	}
+	if s.Length() != 3 {
+		t.Errorf("длина после повторного добавления = %d, ожидалась 3", s.Length())
+	}
+	if err := s.Delete(VMString("а")); err != nil {
+		t.Fatal(err)
+	}
+	if s.Has(VMString("а")) || !s.Has(VMInt(1)) || s.Has(VMString("1")) {
+		t.Errorf("неверная проверка вхождения в %s", s)
+	}
+	if err := s.Add(VMSlice{VMNil}); err != VMErrorSetElement {
+		t.Errorf("добавление массива с Неопределено: ошибка %v", err)
+	}
+
+	x, y := set(VMInt(1), VMInt(2), VMString("в")), set(VMInt(2), VMInt(3), VMBool(true))
+	tests := []struct {
+		name string
+		got  *VMSet
+		want string
+	}{
+		{name: "объединение", got: x.Union(y), want: `[true,1,2,3,"в"]`},
+		{name: "пересечение", got: x.Intersect(y), want: `[2]`},
+		{name: "разность", got: x.Difference(y), want: `[1,"в"]`},
+		{name: "исходные не изменились", got: x, want: `[1,2,"в"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if s := tt.got.String(); s != tt.want {
+				t.Errorf("результат = %s, ожидался %s", s, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonicalHash(t *testing.T) {
+	// структуры с одинаковым содержимым, заполненные в разном порядке
+	a, b := make(VMStringMap), make(VMStringMap)
+	for i := 0; i < 20; i++ {
+		a[string(rune('а'+i))] = VMSlice{VMInt(i), VMStringMap{"к": VMBool(i%2 == 0), "с": VMString("x")}}
+	}
+	for i := 19; i >= 0; i-- {
+		b[string(rune('а'+i))] = VMSlice{VMInt(i), VMStringMap{"с": VMString("x"), "к": VMBool(i%2 == 0)}}
+	}
+	for i := 0; i < 10; i++ {
+		if ha, hb := a.Hash(), b.Hash(); ha != hb {
+			t.Fatalf("хеши равных структур различаются: %s и %s", ha, hb)
+		}
+	}
+	b["а"] = VMSlice{VMInt(0), VMStringMap{"к": VMBool(false), "с": VMString("x")}}
+	if a.Hash() == b.Hash() {
+		t.Errorf("хеши различных структур совпадают")
+	}
+
+	if x, y := (VMSlice{VMInt(1), VMInt(2)}), (VMSlice{VMInt(2), VMInt(1)}); x.Hash() == y.Hash() {
+		t.Errorf("хеши массивов с разным порядком элементов совпадают")
+	}
+
+	s, err := NewVMSet(VMStringMap{"а": VMInt(1), "б": VMInt(2)}, VMSlice{VMInt(1), VMInt(2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add(VMStringMap{"б": VMInt(2), "а": VMInt(1)}); err != nil {
+		t.Fatal(err)
+	}
+	if s.Length() != 2 {
+		t.Errorf("длина после добавления равной структуры = %d, ожидалась 2", s.Length())
+	}
+	if !s.Has(VMSlice{VMInt(1), VMInt(2)}) || s.Has(VMSlice{VMInt(2), VMInt(1)}) {
+		t.Errorf("неверная проверка вхождения массива в %s", s)
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 	"sync"
 
 	"github.com/shinanca/gonec/names"
@@ -14,6 +16,18 @@ import (
 
 const ChunkVMSlicePool = 64
 
+// MaxVMLen ограничивает длину массивов и строк, которые встроенные функции создают по размеру из аргумента,
+// чтобы огромный размер вызывал перехватываемую ошибку, а не исчерпание памяти
+const MaxVMLen = 1 << 26
+
+// checkRepeatLen проверяет, что n повторов по size элементов не длиннее MaxVMLen
+func checkRepeatLen(size, n int) error {
+	if n > 0 && size > MaxVMLen/n {
+		return VMErrorTooLarge
+	}
+	return nil
+}
+
 // globalVMSlicePool используется виртуальной машиной для переиспользования в регистрах и параметрах вызова
 var globalVMSlicePool = sync.Pool{
 	New: func() interface{} {
@@ -205,6 +219,148 @@ func (x VMSlice) Обратить(args VMSlice, rets *VMSlice, envout *(*Env)) e
 	return nil
 }
 
+// Unique возвращает новый массив без повторов с сохранением порядка первых вхождений.
+// Элементы сравниваются с учетом содержимого вложенных массивов и структур
+func (x VMSlice) Unique() VMSlice {
+	rv := make(VMSlice, 0, len(x))
+	seen := make(map[interface{}]struct{}) // допустимые элементы множества сравниваются по его ключу
+	var other VMSlice                      // остальные значения сравниваются с каждым добавленным
+	for _, v := range x {
+		if k, err := setKey(v); err == nil {
+			if _, ok := seen[k]; ok || containsDeepEqual(other, v) {
+				continue
+			}
+			seen[k] = struct{}{}
+		} else {
+			if containsDeepEqual(rv, v) {
+				continue
+			}
+			other = append(other, v)
+		}
+		rv = append(rv, v)
+	}
+	return rv
+}
+
+// Flatten возвращает новый массив, в котором вложенные массивы заменены их элементами на depth уровней,
+// при отрицательной глубине массив разворачивается полностью. Остальные элементы переносятся как есть
+func (x VMSlice) Flatten(depth int) VMSlice {
+	rv := make(VMSlice, 0, len(x))
+	for _, v := range x {
+		if vv, ok := v.(VMSlice); ok && depth != 0 {
+			rv = append(rv, vv.Flatten(depth-1)...)
+		} else {
+			rv = append(rv, v)
+		}
+	}
+	return rv
+}
+
+// Chunks разбивает массив на новые массивы по size элементов, последний может быть короче
+func (x VMSlice) Chunks(size int) (VMSlice, error) {
+	if size <= 0 {
+		return nil, VMErrorNonPositiveSize
+	}
+	// часть не длиннее массива, иначе сложение с размером переполняется
+	if size > len(x) && len(x) > 0 {
+		size = len(x)
+	}
+	rv := make(VMSlice, 0, (len(x)+size-1)/size)
+	for i := 0; i < len(x); i += size {
+		j := i + size
+		if j > len(x) {
+			j = len(x)
+		}
+		ch := make(VMSlice, j-i)
+		copy(ch, x[i:j])
+		rv = append(rv, ch)
+	}
+	return rv, nil
+}
+
+// BinarySearch ищет значение в массиве, отсортированном по возрастанию, сравнивая элементы операцией "<".
+// Возвращает индекс найденного элемента, а если его нет - -(позиция вставки)-1,
+// чтобы отсутствие значения при вставке в начало отличалось от найденного нулевого элемента
+func (x VMSlice) BinarySearch(v VMValuer) (int, error) {
+	less := func(a, b VMValuer) (bool, error) {
+		ao, ok := a.(VMOperationer)
+		if !ok {
+			return false, VMErrorIncorrectOperation
+		}
+		bo, ok := b.(VMOperationer)
+		if !ok {
+			return false, VMErrorIncorrectOperation
+		}
+		r, err := ao.EvalBinOp(LSS, bo)
+		if err != nil {
+			return false, err
+		}
+		rb, ok := r.(VMBool)
+		if !ok {
+			return false, VMErrorNeedBool
+		}
+		return bool(rb), nil
+	}
+	lo, hi := 0, len(x)
+	for lo < hi {
+		m := int(uint(lo+hi) >> 1)
+		l, err := less(x[m], v)
+		if err != nil {
+			return 0, err
+		}
+		if l {
+			lo = m + 1
+		} else {
+			hi = m
+		}
+	}
+	if lo < len(x) {
+		g, err := less(v, x[lo])
+		if err != nil {
+			return 0, err
+		}
+		if !g {
+			return lo, nil
+		}
+	}
+	return -lo - 1, nil
+}
+
+// ZipVMSlices возвращает массив пар [x[i], y[i]], длина результата равна длине более короткого массива
+func ZipVMSlices(x, y VMSlice) VMSlice {
+	n := len(x)
+	if len(y) < n {
+		n = len(y)
+	}
+	rv := make(VMSlice, n)
+	for i := 0; i < n; i++ {
+		rv[i] = VMSlice{x[i], y[i]}
+	}
+	return rv
+}
+
+// UnzipVMSlice разделяет массив пар на массив первых и массив вторых элементов
+func UnzipVMSlice(x VMSlice) (VMSlice, VMSlice, error) {
+	rx, ry := make(VMSlice, len(x)), make(VMSlice, len(x))
+	for i := range x {
+		p, ok := x[i].(VMSlice)
+		if !ok || len(p) != 2 {
+			return nil, nil, VMErrorNeedPair
+		}
+		rx[i], ry[i] = p[0], p[1]
+	}
+	return rx, ry, nil
+}
+
+func containsDeepEqual(x VMSlice, v VMValuer) bool {
+	for i := range x {
+		if DeepEqualVMValues(x[i], v) {
+			return true
+		}
+	}
+	return false
+}
+
 func (x VMSlice) CopyRecursive() VMSlice {
 	rv := make(VMSlice, len(x))
 	for i, v := range x {
@@ -585,6 +741,31 @@ func (x VMSliceUpSort) Len() int           { return len(x) }
 func (x VMSliceUpSort) Swap(i, j int)      { x[i], x[j] = x[j], x[i] }
 func (x VMSliceUpSort) Less(i, j int) bool { return SortLessVMValues(x[i], x[j]) }
 
+// Repeat возвращает новый слайс из n копий элементов исходного слайса подряд.
+// Сами элементы не копируются
+func (x VMSlice) Repeat(n int) (VMSlice, error) {
+	if err := checkRepeatLen(len(x), n); err != nil {
+		return nil, err
+	}
+	rv := make(VMSlice, 0, len(x)*n)
+	for i := 0; i < n; i++ {
+		rv = append(rv, x...)
+	}
+	return rv, nil
+}
+
+// JoinFormat форматирует каждый элемент по шаблону, как функция Формат, и соединяет результаты через разделитель
+func (x VMSlice) JoinFormat(tpl, sep string) string {
+	var b strings.Builder
+	for i := range x {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		fmt.Fprintf(&b, tpl, x[i])
+	}
+	return b.String()
+}
+
 // NewVMSliceFromStrings создает слайс вирт. машины []VMString из слайса строк []string на языке Го
 func NewVMSliceFromStrings(ss []string) (rv VMSlice) {
 	for i := range ss {
@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 	"unicode/utf8"
 
 	"github.com/covrom/decnum"
@@ -70,6 +71,50 @@ func (x VMString) Decimal() VMDecNum {
 	return VMDecNum{num: d}
 }
 
+// ParseVMNumber разбирает число, записанное с разделителем дробной части decsep (точка или запятая).
+// Пробелы (в т.ч. неразрывные), апострофы и второй из символов точка/запятая считаются разделителями разрядов и пропускаются.
+// Целое число возвращается как VMInt, дробное или с экспонентой - как VMDecNum.
+// Если строка не является числом, возвращается ошибка VMErrorNumberFormat
+func ParseVMNumber(s string, decsep rune) (VMNumberer, error) {
+	group := ','
+	if decsep == ',' {
+		group = '.'
+	}
+	var sb strings.Builder
+	for _, r := range s {
+		switch {
+		case unicode.IsSpace(r) || r == '\'' || r == group:
+		case r == decsep:
+			sb.WriteByte('.')
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return parseVMNumber(sb.String())
+}
+
+// ParseVMNumberStrict разбирает число с точкой в качестве разделителя дробной части и без разделителей разрядов,
+// пробелы допускаются только по краям строки. Так строка приводится к числу в Число(строка), Целое и Десятичное
+func ParseVMNumberStrict(s string) (VMNumberer, error) {
+	return parseVMNumber(strings.TrimSpace(s))
+}
+
+func parseVMNumber(ns string) (VMNumberer, error) {
+	var (
+		v   VMNumberer
+		err error
+	)
+	if strings.ContainsAny(ns, ".eE") && !strings.HasPrefix(ns, "0x") {
+		v, err = ParseVMDecNum(ns)
+	} else {
+		v, err = ParseVMInt(ns)
+	}
+	if err != nil || ns == "" {
+		return nil, VMErrorNumberFormat
+	}
+	return v, nil
+}
+
 func (x VMString) InvokeNumber() (v VMNumberer, err error) {
 	if strings.ContainsAny(string(x), ".eE") {
 		v, err = ParseVMDecNum(string(x))
@@ -247,9 +292,9 @@ func (x VMString) ConvertToType(nt reflect.Type) (VMValuer, error) {
 	case ReflectVMTime:
 		return x.Time(), nil
 	case ReflectVMBool:
-		return VMBool(x.Bool()), nil
+		return ParseVMBool(string(x))
 	case ReflectVMDecNum:
-		return x.Decimal(), nil
+		return ParseVMNumberStrict(string(x))
 	case ReflectVMSlice:
 		return VMSliceFromJson(string(x))
 	case ReflectVMStringMap:
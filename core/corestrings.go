@@ -0,0 +1,178 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Строковые функции стандартной библиотеки, работающие с символами (рунами), а не с байтами
+
+// runeBoundary сообщает, что позиция i в строке приходится на начало символа или конец строки
+func runeBoundary(s string, i int) bool {
+	return i == 0 || i >= len(s) || utf8.RuneStart(s[i])
+}
+
+// StrHasPrefix проверяет, что строка начинается с префикса, который заканчивается на границе символа
+func StrHasPrefix(s, prefix string) bool {
+	return strings.HasPrefix(s, prefix) && runeBoundary(s, len(prefix))
+}
+
+// StrHasSuffix проверяет, что строка заканчивается суффиксом, который начинается на границе символа
+func StrHasSuffix(s, suffix string) bool {
+	return strings.HasSuffix(s, suffix) && runeBoundary(s, len(s)-len(suffix))
+}
+
+// StrContains проверяет вхождение подстроки, начало и конец которого на границах символов.
+// Пустая подстрока содержится в любой строке
+func StrContains(s, sub string) bool {
+	for i := 0; i <= len(s); {
+		j := strings.Index(s[i:], sub)
+		if j < 0 {
+			return false
+		}
+		k := i + j
+		if runeBoundary(s, k) && runeBoundary(s, k+len(sub)) {
+			return true
+		}
+		i = k + 1
+	}
+	return false
+}
+
+// ConcatVMStrings соединяет значения в одну строку, если все они строки
+func ConcatVMStrings(vs []VMValuer) (VMString, bool) {
+	n := 0
+	for _, v := range vs {
+		s, ok := v.(VMString)
+		if !ok {
+			return "", false
+		}
+		n += len(s)
+	}
+	var sb strings.Builder
+	sb.Grow(n)
+	for _, v := range vs {
+		sb.WriteString(string(v.(VMString)))
+	}
+	return VMString(sb.String()), true
+}
+
+// StrPad дополняет строку символом fill слева или справа до длины n символов.
+// Строка, длина которой уже не меньше n, возвращается без изменений (не обрезается)
+func StrPad(s string, n int, fill rune, left bool) string {
+	cnt := n - utf8.RuneCountInString(s)
+	if cnt <= 0 {
+		return s
+	}
+	pad := strings.Repeat(string(fill), cnt)
+	if left {
+		return pad + s
+	}
+	return s + pad
+}
+
+// StrReverse переворачивает строку по символам. Комбинируемые знаки (ударения, диакритика)
+// остаются после своего базового символа, поэтому "и\u0306" (й из двух символов) не распадается
+func StrReverse(s string) string {
+	rs := []rune(s)
+	rv := make([]rune, 0, len(rs))
+	for end := len(rs); end > 0; {
+		start := end - 1
+		for start > 0 && unicode.Is(unicode.M, rs[start]) {
+			start--
+		}
+		rv = append(rv, rs[start:end]...)
+		end = start
+	}
+	return string(rv)
+}
+
+// StrLike проверяет соответствие строки шаблону в стиле SQL LIKE: % - любая последовательность символов,
+// _ - ровно один символ. Обратная косая черта экранирует следующий символ шаблона, например \% или \\.
+// Сравнение выполняется по символам с учетом регистра
+func StrLike(s, pattern string) bool {
+	const (
+		likeRune = iota
+		likeAny
+		likeOne
+	)
+	type likeToken struct {
+		kind int
+		r    rune
+	}
+	pr := []rune(pattern)
+	toks := make([]likeToken, 0, len(pr))
+	for i := 0; i < len(pr); i++ {
+		switch pr[i] {
+		case '%':
+			toks = append(toks, likeToken{kind: likeAny})
+		case '_':
+			toks = append(toks, likeToken{kind: likeOne})
+		case '\\':
+			if i+1 < len(pr) {
+				i++
+			}
+			toks = append(toks, likeToken{r: pr[i]})
+		default:
+			toks = append(toks, likeToken{r: pr[i]})
+		}
+	}
+	rs := []rune(s)
+	// жадное сопоставление с возвратом к последнему %
+	si, ti := 0, 0
+	star, mark := -1, 0
+	for si < len(rs) {
+		switch {
+		case ti < len(toks) && (toks[ti].kind == likeOne || toks[ti].kind == likeRune && toks[ti].r == rs[si]):
+			si++
+			ti++
+		case ti < len(toks) && toks[ti].kind == likeAny:
+			star, mark = ti, si
+			ti++
+		case star >= 0:
+			mark++
+			si, ti = mark, star+1
+		default:
+			return false
+		}
+	}
+	for ti < len(toks) && toks[ti].kind == likeAny {
+		ti++
+	}
+	return ti == len(toks)
+}
+
+// StrTemplate подставляет в шаблон значения по именам {имя} из структуры.
+// Двойные фигурные скобки {{ и }} дают одиночную скобку, незакрытая скобка остается как есть.
+// Имена, отсутствующие в структуре, остаются в тексте без изменений,
+// а при strict возвращается ошибка
+func StrTemplate(tpl string, vals VMStringMap, strict bool) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < len(tpl); i++ {
+		switch c := tpl[i]; {
+		case (c == '{' || c == '}') && i+1 < len(tpl) && tpl[i+1] == c:
+			sb.WriteByte(c)
+			i++
+		case c == '{':
+			j := strings.IndexAny(tpl[i+1:], "{}")
+			if j < 0 || tpl[i+1+j] != '}' {
+				sb.WriteByte(c)
+				continue
+			}
+			name := tpl[i+1 : i+1+j]
+			if v, ok := vals[name]; ok {
+				sb.WriteString(fmt.Sprint(v))
+			} else if strict {
+				return "", VMErrorTemplateKey(name)
+			} else {
+				sb.WriteString(tpl[i : i+j+2])
+			}
+			i += j + 1
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,195 @@
+package core
+
+import "testing"
+
+func TestStrPrefixSuffixContains(t *testing.T) {
+	tests := []struct {
+		name     string
+		s, sub   string
+		prefix   bool
+		suffix   bool
+		contains bool
+	}{
+		{name: "кириллица", s: "ёжик", sub: "ёж", prefix: true, contains: true},
+		{name: "суффикс", s: "ёжик", sub: "ик", suffix: true, contains: true},
+		{name: "середина", s: "ёжик", sub: "жи", contains: true},
+		{name: "вся строка", s: "ёж", sub: "ёж", prefix: true, suffix: true, contains: true},
+		{name: "часть символа в начале", s: "ёж", sub: "\xd1", contains: false},
+		{name: "часть символа в конце", s: "ёж", sub: "\xb6", contains: false},
+		{name: "пустая подстрока", s: "ёж", sub: "", prefix: true, suffix: true, contains: true},
+		{name: "пустая строка", s: "", sub: "а", prefix: false, suffix: false, contains: false},
+		{name: "обе пустые", s: "", sub: "", prefix: true, suffix: true, contains: true},
+		{name: "длиннее строки", s: "ё", sub: "ёж"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StrHasPrefix(tt.s, tt.sub); got != tt.prefix {
+				t.Errorf("StrHasPrefix = %v, ожидалось %v", got, tt.prefix)
+			}
+			if got := StrHasSuffix(tt.s, tt.sub); got != tt.suffix {
+				t.Errorf("StrHasSuffix = %v, ожидалось %v", got, tt.suffix)
+			}
+			if got := StrContains(tt.s, tt.sub); got != tt.contains {
+				t.Errorf("StrContains = %v, ожидалось %v", got, tt.contains)
+			}
+		})
+	}
+}
+
+func TestStrPad(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		n    int
+		fill rune
+		left bool
+		want string
+	}{
+		{name: "слева кириллица", s: "ёж", n: 5, fill: '.', left: true, want: "...ёж"},
+		{name: "справа кириллица", s: "ёж", n: 4, fill: '_', want: "ёж__"},
+		{name: "многобайтовый символ", s: "ab", n: 4, fill: '─', left: true, want: "──ab"},
+		{name: "уже длиннее", s: "ёжики", n: 3, fill: ' ', left: true, want: "ёжики"},
+		{name: "равная длина", s: "ёж", n: 2, fill: ' ', want: "ёж"},
+		{name: "пустая строка", s: "", n: 2, fill: 'я', want: "яя"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StrPad(tt.s, tt.n, tt.fill, tt.left); got != tt.want {
+				t.Errorf("StrPad = %q, ожидалось %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrReverse(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "ascii", s: "abc", want: "cba"},
+		{name: "кириллица", s: "ёжик", want: "кижё"},
+		{name: "комбинируемый знак", s: "ми\u0306ка", want: "аки\u0306м"},
+		{name: "знак в начале строки", s: "\u0301аб", want: "ба\u0301"},
+		{name: "пустая строка", s: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StrReverse(tt.s); got != tt.want {
+				t.Errorf("StrReverse = %q, ожидалось %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrLike(t *testing.T) {
+	tests := []struct {
+		name    string
+		s, tmpl string
+		want    bool
+	}{
+		{name: "процент в конце", s: "Петров", tmpl: "Пет%", want: true},
+		{name: "процент в середине", s: "Петров Иван", tmpl: "П%в%н", want: true},
+		{name: "процент с возвратом", s: "абвабвг", tmpl: "%абвг", want: true},
+		{name: "пустой процент", s: "", tmpl: "%", want: true},
+		{name: "подчеркивание", s: "кот", tmpl: "к_т", want: true},
+		{name: "подчеркивание не пустое", s: "кт", tmpl: "к_т", want: false},
+		{name: "экранированный процент", s: "100%", tmpl: "100\\%", want: true},
+		{name: "экранированный процент не шаблон", s: "1000", tmpl: "100\\%", want: false},
+		{name: "экранированное подчеркивание", s: "а_б", tmpl: "а\\_б", want: true},
+		{name: "экранированная черта", s: "а\\б", tmpl: "а\\\\%", want: true},
+		{name: "не соответствует", s: "Иванов", tmpl: "Пет%", want: false},
+		{name: "регистр", s: "петров", tmpl: "Пет%", want: false},
+		{name: "лишние символы", s: "котик", tmpl: "кот", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StrLike(tt.s, tt.tmpl); got != tt.want {
+				t.Errorf("StrLike(%q, %q) = %v, ожидалось %v", tt.s, tt.tmpl, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrTemplate(t *testing.T) {
+	vals := VMStringMap{"имя": VMString("Мир"), "н": VMInt(3)}
+	tests := []struct {
+		name    string
+		tpl     string
+		strict  bool
+		want    string
+		wantErr string
+	}{
+		{name: "несколько имен", tpl: "Привет, {имя}! {н} раза {имя}", want: "Привет, Мир! 3 раза Мир"},
+		{name: "отсутствующее имя", tpl: "{имя} и {нет}", want: "Мир и {нет}"},
+		{name: "строго", tpl: "{имя} и {нет}", strict: true, wantErr: "Нет значения для 'нет' в шаблоне"},
+		{name: "экранирование", tpl: "{{имя}} = {имя}, }}", want: "{имя} = Мир, }"},
+		{name: "незакрытая скобка", tpl: "а { б {имя}", want: "а { б Мир"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := StrTemplate(tt.tpl, vals, tt.strict)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("ошибка = %v, ожидалась %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("StrTemplate = %q, ожидалось %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseVMNumber(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		decsep  rune
+		want    string
+		wantInt bool
+		wantErr bool
+	}{
+		{name: "запятая и пробелы", s: "1 234,56", decsep: ',', want: "1234.56"},
+		{name: "неразрывный пробел", s: "-12\u00a0345", decsep: ',', want: "-12345", wantInt: true},
+		{name: "точка и запятые", s: "1,234,567.5", decsep: '.', want: "1234567.5"},
+		{name: "целое", s: "42", decsep: '.', want: "42", wantInt: true},
+		{name: "неверная строка", s: "12абв", decsep: '.', wantErr: true},
+		{name: "пустая строка", s: " ", decsep: '.', wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseVMNumber(tt.s, tt.decsep)
+			if tt.wantErr {
+				if err != VMErrorNumberFormat {
+					t.Errorf("ошибка = %v, ожидалась %v", err, VMErrorNumberFormat)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := got.(VMInt); ok != tt.wantInt {
+				t.Errorf("тип %T, ожидалось целое: %v", got, tt.wantInt)
+			}
+			if got.DecNum().String() != tt.want {
+				t.Errorf("ParseVMNumber = %s, ожидалось %s", got.DecNum(), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseVMNumberStrict(t *testing.T) {
+	for _, s := range []string{"1,5", "1 5", "1'000", ""} {
+		if _, err := ParseVMNumberStrict(s); err != VMErrorNumberFormat {
+			t.Errorf("ParseVMNumberStrict(%q): ошибка = %v, ожидалась %v", s, err, VMErrorNumberFormat)
+		}
+	}
+	if v, err := ParseVMNumberStrict(" 1.5 "); err != nil || v.DecNum().String() != "1.5" {
+		t.Errorf("ParseVMNumberStrict(\" 1.5 \") = %v, %v", v, err)
+	}
+}
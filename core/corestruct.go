@@ -0,0 +1,294 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/shinanca/gonec/names"
+)
+
+// VMStructType описание пользовательской структуры, объявленной в коде на языке Гонец:
+// структура Точка { Х, У }
+type VMStructType struct {
+	Name   int   // id имени типа
+	Fields []int // id полей в порядке объявления
+
+	fieldIdx map[int]int
+
+	mu      sync.RWMutex
+	methods map[int]VMFunc // методы, получатель передается первым аргументом
+}
+
+func NewVMStructType(name int, fields []int) *VMStructType {
+	t := &VMStructType{
+		Name:     name,
+		Fields:   fields,
+		fieldIdx: make(map[int]int, len(fields)),
+	}
+	for i, f := range fields {
+		t.fieldIdx[f] = i
+	}
+	return t
+}
+
+// FieldIndex возвращает номер поля в структуре
+func (t *VMStructType) FieldIndex(name int) (int, bool) {
+	i, ok := t.fieldIdx[name]
+	return i, ok
+}
+
+// DefineMethod регистрирует метод структуры: функция (т Точка) Модуль()
+func (t *VMStructType) DefineMethod(name int, f VMFunc) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.methods == nil {
+		t.methods = make(map[int]VMFunc)
+	}
+	t.methods[name] = f
+}
+
+// Method возвращает метод структуры без привязки к получателю
+func (t *VMStructType) Method(name int) (VMFunc, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	f, ok := t.methods[name]
+	return f, ok
+}
+
+// New создает новый экземпляр структуры, все поля которого неопределены
+func (t *VMStructType) New() *VMStruct {
+	v := &VMStruct{
+		typ:  t,
+		vals: make(VMSlice, len(t.Fields)),
+	}
+	for i := range v.vals {
+		v.vals[i] = VMNil
+	}
+	return v
+}
+
+// VMStruct экземпляр пользовательской структуры, всегда передается по ссылке
+type VMStruct struct {
+	typ  *VMStructType
+	vals VMSlice
+}
+
+var ReflectVMStruct = reflect.TypeOf(&VMStruct{})
+
+func (x *VMStruct) vmval() {}
+
+func (x *VMStruct) Interface() interface{} {
+	return x
+}
+
+func (x *VMStruct) Type() *VMStructType {
+	return x.typ
+}
+
+// Field возвращает значение поля, или ошибку, если такое поле не объявлено
+func (x *VMStruct) Field(name int) (VMValuer, error) {
+	if i, ok := x.typ.FieldIndex(name); ok {
+		return x.vals[i], nil
+	}
+	return VMNil, x.errorNoField(name)
+}
+
+// SetField устанавливает значение поля, или возвращает ошибку, если такое поле не объявлено
+func (x *VMStruct) SetField(name int, v VMValuer) error {
+	if i, ok := x.typ.FieldIndex(name); ok {
+		x.vals[i] = v
+		return nil
+	}
+	return x.errorNoField(name)
+}
+
+// MethodMember возвращает метод структуры, привязанный к данному экземпляру
+func (x *VMStruct) MethodMember(name int) (VMFunc, bool) {
+	f, ok := x.typ.Method(name)
+	if !ok {
+		return nil, false
+	}
+	return func(args VMSlice, rets *VMSlice, envout *(*Env)) error {
+		fargs := make(VMSlice, 0, len(args)+1)
+		fargs = append(fargs, x)
+		fargs = append(fargs, args...)
+		return f(fargs, rets, envout)
+	}, true
+}
+
+// Member возвращает значение поля или привязанный метод
+func (x *VMStruct) Member(name int) (VMValuer, error) {
+	if i, ok := x.typ.FieldIndex(name); ok {
+		return x.vals[i], nil
+	}
+	if f, ok := x.MethodMember(name); ok {
+		return f, nil
+	}
+	return VMNil, fmt.Errorf("Поле или метод '%s' не объявлены в структуре '%s'",
+		names.UniqueNames.Get(name), names.UniqueNames.Get(x.typ.Name))
+}
+
+func (x *VMStruct) errorNoField(name int) error {
+	return fmt.Errorf("Поле '%s' не объявлено в структуре '%s'",
+		names.UniqueNames.Get(name), names.UniqueNames.Get(x.typ.Name))
+}
+
+func (x *VMStruct) String() string {
+	var b bytes.Buffer
+	b.WriteString(names.UniqueNames.Get(x.typ.Name))
+	b.WriteByte('{')
+	for i, f := range x.typ.Fields {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s: %v", names.UniqueNames.Get(f), x.vals[i])
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// StringMap возвращает копию полей в виде структуры Гонец (VMStringMap)
+func (x *VMStruct) StringMap() VMStringMap {
+	rv := make(VMStringMap, len(x.vals))
+	for i, f := range x.typ.Fields {
+		rv[names.UniqueNames.Get(f)] = x.vals[i]
+	}
+	return rv
+}
+
+// opMethods - имена методов структуры, перегружающих операции: функция (а Вектор) __Сложить__(б).
+// Метод вызывается для левого операнда, правый передается аргументом
+var opMethods = map[VMOperation]int{
+	ADD: names.UniqueNames.Set("__Сложить__"),
+	SUB: names.UniqueNames.Set("__Вычесть__"),
+	MUL: names.UniqueNames.Set("__Умножить__"),
+	QUO: names.UniqueNames.Set("__Разделить__"),
+	REM: names.UniqueNames.Set("__Остаток__"),
+	POW: names.UniqueNames.Set("__Степень__"),
+	EQL: names.UniqueNames.Set("__Равно__"),
+	LSS: names.UniqueNames.Set("__Меньше__"),
+}
+
+// overloadOp выполняет операцию перегружающим методом структуры, если он объявлен.
+// Метод всегда вызывается для структуры x, второй операнд передается аргументом, а окружение env
+// вызывающего кода - для учета глубины вызовов. Неравенство вычисляется через __Равно__, x >= y - как !(x < y),
+// а x > y - как !(x < y) и x != y, x <= y - обратное ему
+func (x *VMStruct) overloadOp(env *Env, op VMOperation, y VMOperationer) (VMValuer, bool, error) {
+	switch op {
+	case NEQ, GEQ:
+		mop := EQL
+		if op == GEQ {
+			mop = LSS
+		}
+		rv, ok, err := x.callOp(env, mop, y)
+		if ok && err == nil {
+			rv = !rv.(VMBool)
+		}
+		return rv, ok, err
+	case GTR, LEQ:
+		lt, ok, err := x.callOp(env, LSS, y)
+		if !ok || err != nil {
+			return lt, ok, err
+		}
+		le := bool(lt.(VMBool))
+		if !le {
+			if le, err = x.equal(env, y); err != nil {
+				return VMNil, true, err
+			}
+		}
+		return VMBool(le == (op == LEQ)), true, nil
+	}
+	return x.callOp(env, op, y)
+}
+
+// callOp вызывает метод, перегружающий операцию, если он объявлен. Сравнения должны возвращать булево
+func (x *VMStruct) callOp(env *Env, op VMOperation, y VMOperationer) (VMValuer, bool, error) {
+	name, ok := opMethods[op]
+	if !ok {
+		return VMNil, false, nil
+	}
+	f, ok := x.typ.Method(name)
+	if !ok {
+		return VMNil, false, nil
+	}
+	rv, err := CallVMFunc(env, f, x, y)
+	if err != nil {
+		return VMNil, true, err
+	}
+	if op == EQL || op == LSS {
+		b, ok := rv.(VMBool)
+		if !ok {
+			return VMNil, true, VMErrorNeedBool
+		}
+		return b, true, nil
+	}
+	return rv, true, nil
+}
+
+// equal сравнивает структуру со значением методом __Равно__, а без него - по типу и полям
+func (x *VMStruct) equal(env *Env, y VMOperationer) (bool, error) {
+	if rv, ok, err := x.callOp(env, EQL, y); ok {
+		if err != nil {
+			return false, err
+		}
+		return bool(rv.(VMBool)), nil
+	}
+	yy, ok := y.(*VMStruct)
+	return ok && x.equalFields(yy), nil
+}
+
+// equalFields сравнивает структуры одного типа по значениям полей
+func (x *VMStruct) equalFields(y *VMStruct) bool {
+	eq := x.typ == y.typ
+	for i := 0; eq && i < len(x.vals); i++ {
+		eq = EqualVMValues(x.vals[i], y.vals[i])
+	}
+	return eq
+}
+
+func (x *VMStruct) EvalBinOp(op VMOperation, y VMOperationer) (VMValuer, error) {
+	return x.evalBinOp(nil, op, y)
+}
+
+// evalBinOp выполняет операцию, вызывая перегружающие методы в окружении env
+func (x *VMStruct) evalBinOp(env *Env, op VMOperation, y VMOperationer) (VMValuer, error) {
+	if rv, ok, err := x.overloadOp(env, op, y); ok {
+		return rv, err
+	}
+	switch op {
+	case EQL, NEQ:
+		yy, ok := y.(*VMStruct)
+		if !ok {
+			return VMNil, VMErrorIncorrectOperation
+		}
+		eq := x.equalFields(yy)
+		if op == NEQ {
+			return VMBool(!eq), nil
+		}
+		return VMBool(eq), nil
+	case ADD, SUB, MUL, QUO, REM, GTR, GEQ, LSS, LEQ, OR, LOR, AND, LAND, POW, SHR, SHL:
+		return VMNil, VMErrorIncorrectOperation
+	}
+	return VMNil, VMErrorUnknownOperation
+}
+
+// EvalBinOpEnv выполняет операцию, как x.EvalBinOp, но методы структур, перегружающие операции,
+// вызываются в окружении env вызывающего кода, чтобы на них действовало ограничение глубины вызовов
+func EvalBinOpEnv(env *Env, op VMOperation, x, y VMOperationer) (VMValuer, error) {
+	if s, ok := x.(*VMStruct); ok {
+		return s.evalBinOp(env, op, y)
+	}
+	return x.EvalBinOp(op, y)
+}
+
+func (x *VMStruct) ConvertToType(nt reflect.Type) (VMValuer, error) {
+	switch nt {
+	case ReflectVMString:
+		return VMString(x.String()), nil
+	case ReflectVMStringMap:
+		return x.StringMap(), nil
+	}
+	return VMNil, VMErrorNotConverted
+}
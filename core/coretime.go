@@ -38,6 +38,18 @@ func (v VMTimeDuration) Duration() VMTimeDuration {
 	return v
 }
 
+// durationOf возвращает длительность из значения типа Длительность или из числа секунд
+func durationOf(v VMValuer) (time.Duration, error) {
+	switch vv := v.(type) {
+	case VMTimeDuration:
+		return time.Duration(vv), nil
+	case VMNumberer:
+		sec1 := NewVMDecNumFromInt64(int64(VMSecond))
+		return time.Duration(vv.DecNum().Mul(sec1).Int()), nil
+	}
+	return 0, VMErrorNeedDuration
+}
+
 func (x VMTimeDuration) BinaryType() VMBinaryType {
 	return VMDURATION
 }
@@ -329,6 +341,9 @@ func Now() VMTime {
 	return VMTime(time.Now())
 }
 
+// monoStart - точка отсчета монотонных часов для НаносекундыМоно
+var monoStart = time.Now()
+
 func (t VMTime) vmval() {}
 
 func (t VMTime) Interface() interface{} {
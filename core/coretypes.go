@@ -59,6 +59,13 @@ var OperMap = map[string]VMOperation{
 	">>": SHR,  // >>
 }
 
+// UnaryOperMap сопоставляет унарные операторы языка с кодом операции для VMUnarer.EvalUnOp
+var UnaryOperMap = map[string]rune{
+	"-": '-', // -
+	"!": '!', // !
+	"^": '^', // ^
+}
+
 var OperMapR = map[VMOperation]string{
 	ADD:  "+",  // +
 	SUB:  "-",  // -
@@ -218,6 +225,14 @@ func (x VMNilType) EvalBinOp(op VMOperation, y VMOperationer) (VMValuer, error)
 	return VMNil, VMErrorUnknownOperation
 }
 
+// ConvertToType приводит Неопределено к Булево (Ложь), другие приведения невозможны
+func (x VMNilType) ConvertToType(nt reflect.Type) (VMValuer, error) {
+	if nt == ReflectVMBool {
+		return VMBool(false), nil
+	}
+	return VMNil, VMErrorNotConverted
+}
+
 func (x VMNilType) MarshalBinary() ([]byte, error) {
 	return []byte{}, nil
 }
@@ -267,6 +282,14 @@ func (x VMNullType) BinaryType() VMBinaryType {
 
 var VMNullVar = VMNullType{}
 
+// ConvertToType приводит NULL к Булево (Ложь), другие приведения невозможны
+func (x VMNullType) ConvertToType(nt reflect.Type) (VMValuer, error) {
+	if nt == ReflectVMBool {
+		return VMBool(false), nil
+	}
+	return VMNil, VMErrorNotConverted
+}
+
 // EvalBinOp сравнивает два значения или выполняет бинарную операцию
 func (x VMNullType) EvalBinOp(op VMOperation, y VMOperationer) (VMValuer, error) {
 	switch op {
@@ -464,6 +487,60 @@ func VMValuerFromJSON(s string) (VMValuer, error) {
 	}
 }
 
+// ToVMInt приводит значение к целому числу: дробная часть отбрасывается,
+// строка разбирается как число, булево дает 1 или 0
+func ToVMInt(v VMValuer) (VMInt, error) {
+	switch vv := v.(type) {
+	case VMInt:
+		return vv, nil
+	case VMDecNum:
+		i, err := vv.num.ToInt64(decnum.RoundDown)
+		if err != nil {
+			return 0, VMErrorIntOverflow
+		}
+		return VMInt(i), nil
+	case VMBigInt:
+		if !vv.big().IsInt64() {
+			return 0, VMErrorIntOverflow
+		}
+		return VMInt(vv.Int()), nil
+	case VMBool:
+		if vv {
+			return 1, nil
+		}
+		return 0, nil
+	case VMString:
+		n, err := ParseVMNumberStrict(string(vv))
+		if err != nil {
+			return 0, err
+		}
+		return ToVMInt(n)
+	}
+	return 0, VMErrorNotConverted
+}
+
+// ToVMDecNum приводит значение к десятичному числу: строка разбирается как число, булево дает 1 или 0
+func ToVMDecNum(v VMValuer) (VMDecNum, error) {
+	switch vv := v.(type) {
+	case VMDecNum:
+		return vv, nil
+	case VMInt, VMBigInt:
+		return vv.(VMNumberer).DecNum(), nil
+	case VMBool:
+		if vv {
+			return NewVMDecNumFromInt64(1), nil
+		}
+		return NewVMDecNumFromInt64(0), nil
+	case VMString:
+		n, err := ParseVMNumberStrict(string(vv))
+		if err != nil {
+			return VMDecNum{}, err
+		}
+		return n.DecNum(), nil
+	}
+	return VMDecNum{}, VMErrorNotConverted
+}
+
 func VMSliceFromJson(x string) (VMSlice, error) {
 	//парсим json из строки и пытаемся получить массив
 	var rvms VMSlice
@@ -504,6 +581,42 @@ func EqualVMValues(v1, v2 VMValuer) bool {
 	return BoolOperVMValues(v1, v2, EQL)
 }
 
+// DeepEqualVMValues сравнивает значения с учетом содержимого: массивы - поэлементно по порядку,
+// структуры - по совпадению ключей и значений, вложенные массивы и структуры - рекурсивно.
+// Остальные значения сравниваются операцией равенства
+func DeepEqualVMValues(v1, v2 VMValuer) bool {
+	switch x := v1.(type) {
+	case VMSlice:
+		y, ok := v2.(VMSlice)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !DeepEqualVMValues(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case VMStringMap:
+		y, ok := v2.(VMStringMap)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			yv, ok := y[k]
+			if !ok || !DeepEqualVMValues(v, yv) {
+				return false
+			}
+		}
+		return true
+	}
+	switch v2.(type) {
+	case VMSlice, VMStringMap:
+		return false
+	}
+	return EqualVMValues(v1, v2)
+}
+
 func BoolOperVMValues(v1, v2 VMValuer, op VMOperation) bool {
 	if xop, ok := v1.(VMOperationer); ok {
 		if yop, ok := v2.(VMOperationer); ok {
@@ -579,3 +692,75 @@ func SortLessVMValues(v1, v2 VMValuer) bool {
 	// прочее
 	return BoolOperVMValues(v1, v2, LSS)
 }
+
+// MaxMinVMValues возвращает большее (op == GTR) или меньшее (op == LSS) из двух значений,
+// сравнивая их через EvalBinOp. При равенстве возвращается первое значение.
+// Если значения не сравниваются между собой, возвращается ошибка операции
+func MaxMinVMValues(v1, v2 VMValuer, op VMOperation) (VMValuer, error) {
+	xop, ok := v1.(VMOperationer)
+	if !ok {
+		return VMNil, VMErrorIncorrectOperation
+	}
+	yop, ok := v2.(VMOperationer)
+	if !ok {
+		return VMNil, VMErrorIncorrectOperation
+	}
+	cmp, err := xop.EvalBinOp(op, yop)
+	if err != nil {
+		return VMNil, err
+	}
+	rcmp, ok := cmp.(VMBool)
+	if !ok {
+		return VMNil, VMErrorIncorrectOperation
+	}
+	if bool(rcmp) || !BoolOperVMValues(v2, v1, op) {
+		return v1, nil
+	}
+	return v2, nil
+}
+
+// MaxMinVMSlice возвращает наибольший (op = GTR) или наименьший (op = LSS) элемент непустого массива
+func MaxMinVMSlice(sl VMSlice, op VMOperation) (VMValuer, error) {
+	if len(sl) == 0 {
+		return VMNil, VMErrorEmptySlice
+	}
+	rv := sl[0]
+	for _, v := range sl[1:] {
+		var err error
+		if rv, err = MaxMinVMValues(rv, v, op); err != nil {
+			return VMNil, err
+		}
+	}
+	return rv, nil
+}
+
+// SumVMSlice складывает числа массива. Сумма целых чисел остается целой,
+// при наличии десятичного числа результат десятичный. Сумма пустого массива равна 0
+func SumVMSlice(sl VMSlice) (VMValuer, error) {
+	var rv VMOperationer = VMInt(0)
+	for _, v := range sl {
+		switch v.(type) {
+		case VMInt, VMDecNum:
+		default:
+			return VMNil, VMErrorNeedDecNum
+		}
+		r, err := rv.EvalBinOp(ADD, v.(VMOperationer))
+		if err != nil {
+			return VMNil, err
+		}
+		rv = r.(VMOperationer)
+	}
+	return rv, nil
+}
+
+// AvgVMSlice возвращает среднее арифметическое чисел непустого массива как десятичное число
+func AvgVMSlice(sl VMSlice) (VMValuer, error) {
+	if len(sl) == 0 {
+		return VMNil, VMErrorEmptySlice
+	}
+	sum, err := SumVMSlice(sl)
+	if err != nil {
+		return VMNil, err
+	}
+	return sum.(VMNumberer).DecNum().EvalBinOp(QUO, NewVMDecNumFromInt64(int64(len(sl))))
+}
@@ -0,0 +1,129 @@
+package core
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Элемент XML представляется структурой Гонец с ключами:
+// "Имя" - имя элемента,
+// "Атрибуты" - структура атрибутов,
+// "Дочерние" - массив вложенных элементов,
+// "Текст" - текстовое содержимое элемента
+const (
+	xmlKeyName     = "Имя"
+	xmlKeyAttrs    = "Атрибуты"
+	xmlKeyChildren = "Дочерние"
+	xmlKeyText     = "Текст"
+)
+
+// VMStringMapFromXML разбирает XML документ в дерево структур Гонец, начиная с корневого элемента
+func VMStringMapFromXML(x string) (VMStringMap, error) {
+	dec := xml.NewDecoder(strings.NewReader(x))
+	var stack []VMStringMap
+	var root VMStringMap
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Ошибка разбора XML: %v", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if root != nil && len(stack) == 0 {
+				return nil, errors.New("Ошибка разбора XML: больше одного корневого элемента")
+			}
+			attrs := make(VMStringMap, len(t.Attr))
+			for _, a := range t.Attr {
+				attrs[a.Name.Local] = VMString(a.Value)
+			}
+			el := VMStringMap{
+				xmlKeyName:     VMString(t.Name.Local),
+				xmlKeyAttrs:    attrs,
+				xmlKeyChildren: VMSlice{},
+				xmlKeyText:     VMString(""),
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent[xmlKeyChildren] = append(parent[xmlKeyChildren].(VMSlice), el)
+			} else {
+				root = el
+			}
+			stack = append(stack, el)
+		case xml.EndElement:
+			el := stack[len(stack)-1]
+			el[xmlKeyText] = VMString(strings.TrimSpace(string(el[xmlKeyText].(VMString))))
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				el := stack[len(stack)-1]
+				el[xmlKeyText] = el[xmlKeyText].(VMString) + VMString(t)
+			} else if len(bytes.TrimSpace(t)) > 0 {
+				return nil, errors.New("Ошибка разбора XML: текст вне корневого элемента")
+			}
+		}
+	}
+	if root == nil {
+		return nil, errors.New("Ошибка разбора XML: отсутствует корневой элемент")
+	}
+	return root, nil
+}
+
+// VMStringMapToXML сериализует дерево структур Гонец, полученное из VMStringMapFromXML, обратно в XML
+func VMStringMapToXML(m VMStringMap) (string, error) {
+	var buf bytes.Buffer
+	enc := xml.NewEncoder(&buf)
+	if err := encodeXMLElement(enc, m); err != nil {
+		return "", err
+	}
+	if err := enc.Flush(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func encodeXMLElement(enc *xml.Encoder, m VMStringMap) error {
+	name, ok := m[xmlKeyName].(VMString)
+	if !ok || name == "" {
+		return VMErrorNeedXMLElement
+	}
+	start := xml.StartElement{Name: xml.Name{Local: string(name)}}
+	if attrs, ok := m[xmlKeyAttrs].(VMStringMap); ok {
+		// атрибуты выводим в порядке сортировки имен, чтобы результат был стабильным
+		keys := make([]string, 0, len(attrs))
+		for k := range attrs {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: k}, Value: fmt.Sprint(attrs[k])})
+		}
+	}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	if text, ok := m[xmlKeyText].(VMString); ok && text != "" {
+		if err := enc.EncodeToken(xml.CharData(text)); err != nil {
+			return err
+		}
+	}
+	if children, ok := m[xmlKeyChildren].(VMSlice); ok {
+		for _, ch := range children {
+			chm, ok := ch.(VMStringMap)
+			if !ok {
+				return VMErrorNeedXMLElement
+			}
+			if err := encodeXMLElement(enc, chm); err != nil {
+				return err
+			}
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
@@ -0,0 +1,81 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVMStringMapFromXML(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		want    VMStringMap
+		wantErr bool
+	}{
+		{
+			name: "вложенные элементы с атрибутами",
+			src:  `<заказ номер="15"><товар код="A1">Чай</товар><товар код="B2"/></заказ>`,
+			want: VMStringMap{
+				"Имя":      VMString("заказ"),
+				"Атрибуты": VMStringMap{"номер": VMString("15")},
+				"Текст":    VMString(""),
+				"Дочерние": VMSlice{
+					VMStringMap{
+						"Имя":      VMString("товар"),
+						"Атрибуты": VMStringMap{"код": VMString("A1")},
+						"Текст":    VMString("Чай"),
+						"Дочерние": VMSlice{},
+					},
+					VMStringMap{
+						"Имя":      VMString("товар"),
+						"Атрибуты": VMStringMap{"код": VMString("B2")},
+						"Текст":    VMString(""),
+						"Дочерние": VMSlice{},
+					},
+				},
+			},
+		},
+		{
+			name: "текст с пробелами и заголовок",
+			src:  "<?xml version=\"1.0\"?>\n<а>\n  привет  \n</а>\n",
+			want: VMStringMap{
+				"Имя":      VMString("а"),
+				"Атрибуты": VMStringMap{},
+				"Текст":    VMString("привет"),
+				"Дочерние": VMSlice{},
+			},
+		},
+		{name: "незакрытый элемент", src: `<а><б></а>`, wantErr: true},
+		{name: "пустая строка", src: ``, wantErr: true},
+		{name: "два корня", src: `<а/><б/>`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VMStringMapFromXML(tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VMStringMapFromXML() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("VMStringMapFromXML() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVMStringMapToXML(t *testing.T) {
+	src := `<заказ номер="15" статус="новый"><товар код="A1">Чай &amp; кофе</товар></заказ>`
+	m, err := VMStringMapFromXML(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := VMStringMapToXML(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != src {
+		t.Errorf("VMStringMapToXML() = %s, want %s", got, src)
+	}
+	if _, err := VMStringMapToXML(VMStringMap{"Текст": VMString("без имени")}); err != VMErrorNeedXMLElement {
+		t.Errorf("VMStringMapToXML() error = %v, want %v", err, VMErrorNeedXMLElement)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/daviddengcn/go-colortext"
 	"github.com/mattn/go-isatty"
 	uuid "github.com/satori/go.uuid"
+	"github.com/shinanca/gonec/ast"
 	"github.com/shinanca/gonec/bincode"
 	"github.com/shinanca/gonec/bincode/binstmt"
 	"github.com/shinanca/gonec/core"
@@ -30,6 +31,9 @@ var (
 	line        = fs.String("e", "", "Исполнение одной строчки кода")
 	compile     = fs.Bool("c", false, "Компиляция в файл .gnx")
 	testingMode = fs.Bool("t", false, "Режим вывода отладочной информации")
+	noFolding   = fs.Bool("nofold", false, "Компиляция без свертки констант, для отладки байткода")
+	briefErrors = fs.Bool("brief", false, "Синтаксические ошибки без перечня ожидаемых лексем")
+	callDepth   = fs.Int("depth", bincode.DefaultMaxCallDepth, "Максимальная глубина вложенности вызовов функций")
 	toconsul    = fs.Bool("consul", false, "Зарегистрировать микросервис интерпретатора в Consul")
 	// stackvm     = fs.Bool("stack", false, "Старая стековая виртуальная машина версии 1.8b")
 	v    = fs.Bool("v", false, "Версия программы")
@@ -58,11 +62,13 @@ func main() {
 		fmt.Println(version.Version)
 		os.Exit(0)
 	}
+	parser.SetErrorVerbose(!*briefErrors)
 
 	var (
 		code      string
 		b         []byte
 		reader    *bufio.Reader
+		inc       *parser.Incremental
 		following bool
 		source    string
 	)
@@ -97,6 +103,7 @@ func main() {
 
 	if interactive {
 		reader = bufio.NewReader(os.Stdin)
+		inc = parser.NewIncremental("_")
 		source = "typein"
 		os.Args = append([]string{os.Args[0]}, fs.Args()...)
 	} else {
@@ -119,6 +126,7 @@ func main() {
 	}
 
 	env := core.NewEnv()
+	env.SetMaxCallDepth(*callDepth)
 	env.DefineS("аргументызапуска", core.NewVMSliceFromStrings(fsArgs))
 
 	for {
@@ -135,19 +143,14 @@ func main() {
 			if err != nil {
 				break
 			}
-			if len(b) == 0 {
+			if len(b) == 0 && !inc.Pending() {
 				continue
 			}
-			if code != "" {
-				code += "\n"
-			}
-			code += string(b)
+			code = string(b)
 		} else {
 			code = string(b)
 		}
 
-		parser.EnableErrorVerbose()
-
 		var (
 			bins binstmt.BinCode
 			// stmts          ast.Stmts
@@ -176,34 +179,31 @@ func main() {
 				log.Printf("--Выполняется код--\n%s\n", code)
 			}
 			//замер производительности
-			_, bins, err = bincode.ParseSrc(code)
+			var prs ast.Stmts
+			opts := bincode.CompileOptions{NoFolding: *noFolding}
+			if interactive {
+				// строки накапливаются, пока не сложатся в законченные операторы
+				var more bool
+				prs, more, err = inc.Feed(code)
+				if more {
+					following = true
+					continue
+				}
+				if err == nil {
+					prs, bins, err = bincode.CompileStmts(prs, opts)
+				}
+			} else {
+				prs, bins, err = bincode.ParseSrcWithOptions(code, opts)
+			}
 			tsParse = time.Since(tstart)
 
 			if *testingMode {
 				log.Printf("--Скомпилирован код-- \n%s\n", bins.String())
-			}
-
-			if interactive {
-				if e, ok := err.(*parser.Error); ok {
-					es := e.Error()
-					if strings.HasPrefix(es, "syntax error: unexpected") {
-						if strings.HasPrefix(es, "syntax error: unexpected $end,") {
-							following = true
-							continue
-						}
-					} else {
-						if e.Pos.Column == len(b) && !e.Fatal {
-							println(e.Error())
-							following = true
-							continue
-						}
-						if e.Error() == "unexpected EOF" {
-							following = true
-							continue
-						}
-					}
+				for _, e := range prs.Unreachable() {
+					log.Printf("Предупреждение: %s\n", e)
 				}
 			}
+
 		}
 
 		if *compile {
@@ -4,6 +4,7 @@ package parser
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"unicode"
 
 	"github.com/shinanca/gonec/ast"
@@ -26,8 +27,9 @@ type Error struct {
 }
 
 // Error returns the error message.
+// Позиция выводится так же, как в ошибках компиляции и исполнения: [строка:колонка]
 func (e *Error) Error() string {
-	return e.Message
+	return fmt.Sprintf("[%d:%d] %s", e.Pos.Line, e.Pos.Column, e.Message)
 }
 
 // Scanner stores informations for lexer.
@@ -40,6 +42,7 @@ type Scanner struct {
 	typecast bool
 	castType string
 	afterNew bool
+	afterDot bool
 }
 
 // opName is correction of operation names.
@@ -60,31 +63,35 @@ var opName = map[string]int{
 	"неопределено": NIL,
 	"модуль":       MODULE,
 	"попытка":      TRY,
+	"попытаться":   TRYEXPR,
 	"исключение":   CATCH,
-	// "окончательно":      FINALLY,
-	"выбор":       SWITCH,
-	"когда":       CASE,
-	"другое":      DEFAULT,
-	"старт":       GO,
-	"параллельно": GO,
-	"канал":       CHAN,
-	"новый":       MAKE,
-
-	"или":          OROR,
-	"и":            ANDAND,
-	"не":           int('!'),
-	"конеццикла":   int('}'),
-	"конецесли":    int('}'),
-	"конецфункции": int('}'),
-	"конецпопытки": int('}'),
-	"конецвыбора":  int('}'),
-	"тогда":        int('{'),
-	"цикл":         int('{'),
-	"null":         NULL,
-	"каждого":      EACH,
-	"по":           TO,
-	"пока":         WHILE,
-	"иначеесли":    ELSIF,
+	"используя":    WITH,
+	"глобальная":   GLOBAL,
+	"окончательно": FINALLY,
+	"выбор":        SWITCH,
+	"когда":        CASE,
+	"другое":       DEFAULT,
+	"старт":        GO,
+	"параллельно":  GO,
+	"канал":        CHAN,
+	"новый":        MAKE,
+
+	"или":                OROR,
+	"и":                  ANDAND,
+	"не":                 int('!'),
+	"конеццикла":         int('}'),
+	"конецесли":          int('}'),
+	"конецфункции":       int('}'),
+	"конецпопытки":       int('}'),
+	"конецвыбора":        int('}'),
+	"конециспользования": int('}'),
+	"тогда":              int('{'),
+	"цикл":               int('{'),
+	"null":               NULL,
+	"каждого":            EACH,
+	"по":                 TO,
+	"пока":               WHILE,
+	"иначеесли":          ELSIF,
 
 	"строка":       TYPECAST,
 	"число":        TYPECAST,
@@ -139,6 +146,9 @@ func (s *Scanner) Scan() (tok int, lit string, pos posit.Position, err error) {
 retry:
 	s.skipBlank()
 	pos = s.pos()
+	// после точки всегда идет имя поля или метода, даже если оно совпадает с ключевым словом
+	afterDot := s.afterDot
+	s.afterDot = false
 	switch ch := s.peek(); {
 	case isLetter(ch):
 		lit, err = s.scanIdentifier()
@@ -146,7 +156,7 @@ retry:
 			return
 		}
 		lowlit := names.FastToLower(lit)
-		if name, ok := opName[lowlit]; ok {
+		if name, ok := opName[lowlit]; ok && !afterDot {
 			tok = name
 			_, s.canequal = opCanEqual[tok]
 			switch tok {
@@ -361,6 +371,7 @@ retry:
 				s.back()
 				tok = int(ch)
 				lit = string(ch)
+				s.afterDot = true
 			}
 		case '\n':
 			tok = int(ch)
@@ -396,7 +407,18 @@ retry:
 				tok = int(ch)
 				lit = string(ch)
 			}
-		case ':', '%', ',', '^':
+		case ':':
+			s.next()
+			switch s.peek() {
+			case '=':
+				tok = DEFINE
+				lit = ":="
+			default:
+				s.back()
+				tok = int(ch)
+				lit = string(ch)
+			}
+		case '%', ',', '^':
 			tok = int(ch)
 			lit = string(ch)
 		case '[':
@@ -454,6 +476,30 @@ func isBlank(ch rune) bool {
 	return ch == ' ' || ch == '\t' || ch == '\r'
 }
 
+// nameUseAhead сообщает, что только что прочитанное имя используется как переменная:
+// за ним следует присваивание, обращение к полю, индекс или вызов
+func (s *Scanner) nameUseAhead() bool {
+	i := s.offset
+	if i < len(s.src) && (s.src[i] == '[' || s.src[i] == '(') {
+		return true
+	}
+	for i < len(s.src) && isBlank(s.src[i]) {
+		i++
+	}
+	if i >= len(s.src) {
+		return false
+	}
+	switch s.src[i] {
+	case '=', '.', ',':
+		return true
+	case '+', '-':
+		return i+1 < len(s.src) && (s.src[i+1] == '=' || s.src[i+1] == s.src[i])
+	case '*', '/':
+		return i+1 < len(s.src) && s.src[i+1] == '='
+	}
+	return false
+}
+
 // peek returns current rune in the code.
 func (s *Scanner) peek() rune {
 	if s.reachEOF() {
@@ -622,11 +668,106 @@ eos:
 
 // Lexer provides inteface to parse codes.
 type Lexer struct {
-	s     *Scanner
-	lit   string
-	pos   posit.Position
-	e     error
-	stmts ast.Stmts
+	s    *Scanner
+	lit  string
+	pos  posit.Position
+	tok  int
+	prev int // предыдущая лексема
+	// позиции сразу за текущей и предыдущей лексемами
+	end, prevEnd posit.Position
+	e            error
+	stmts        ast.Stmts
+
+	// первая ошибка возникла из-за того, что текст закончился внутри незавершенной конструкции
+	incomplete bool
+	failed     bool
+
+	// "случай" и "до" - ключевые слова только в заголовках Выбор и Для, в остальном коде это обычные имена.
+	// Для их распознавания ведется стек открытых блоков, завершаемых словом "конец..." или '}',
+	// и глубина вложенности круглых и квадратных скобок
+	blocks []openBlock
+	depth  int
+	// после ИначеЕсли ее "тогда" не открывает нового блока
+	elsif bool
+	// заголовок Для или Когда, где "до" еще может задать диапазон, и глубина скобок в его начале
+	rangeHead  bool
+	rangeDepth int
+}
+
+// openBlock - открытый блок и глубина скобок, на которой он начался
+type openBlock struct {
+	tok   int
+	depth int
+}
+
+// endsOperand - лексемы, которыми может заканчиваться операнд
+var endsOperand = map[int]bool{
+	IDENT: true, NUMBER: true, STRING: true, TRUE: true, FALSE: true, NIL: true, NULL: true,
+	int(')'): true, int(']'): true, int('}'): true,
+}
+
+// fail запоминает, была ли первая ошибка разбора вызвана концом текста
+func (l *Lexer) fail(atEOF bool) {
+	if !l.failed {
+		l.failed = true
+		l.incomplete = atEOF
+	}
+}
+
+// contextual распознает контекстные ключевые слова "случай" и "до" среди имен
+func (l *Lexer) contextual(lit string) int {
+	if l.tok == '.' {
+		return IDENT
+	}
+	switch names.FastToLower(lit) {
+	case "случай":
+		// в начале оператора непосредственно внутри Выбор, если только это не присваивание или вызов
+		if n := len(l.blocks); n > 0 && l.blocks[n-1].tok == SWITCH && l.blocks[n-1].depth == l.depth &&
+			(l.tok == '\n' || l.tok == ';' || l.tok == ':') && !l.s.nameUseAhead() {
+			return CASE
+		}
+	case "до":
+		// между границами диапазона, после первого операнда
+		if l.rangeHead && l.rangeDepth == l.depth && endsOperand[l.tok] {
+			return TO
+		}
+	}
+	return IDENT
+}
+
+// track отслеживает открытые блоки, скобки и заголовки с диапазонами
+func (l *Lexer) track(tok int, lit string) {
+	switch tok {
+	case SWITCH, TRY, WITH, FUNC:
+		l.blocks = append(l.blocks, openBlock{tok, l.depth})
+	case ELSIF:
+		l.elsif = true
+	case '{':
+		// "{" после операции - это литерал структуры, а не начало тела ИначеЕсли
+		if l.elsif && (lit != "{" || !needOperand[l.tok] && l.tok != '(' && l.tok != '[') {
+			l.elsif = false
+		} else {
+			l.blocks = append(l.blocks, openBlock{tok, l.depth})
+		}
+	case '}':
+		if n := len(l.blocks); n > 0 {
+			l.blocks = l.blocks[:n-1]
+		}
+	case '(', '[':
+		l.depth++
+	case ')', ']':
+		l.depth--
+	}
+	switch tok {
+	case FOR, CASE:
+		l.rangeHead, l.rangeDepth = true, l.depth
+	case EACH, TO, ';', '\n':
+		l.rangeHead = false
+	case ':', '{':
+		if l.rangeDepth == l.depth {
+			l.rangeHead = false
+		}
+	}
 }
 
 // Lex scans the token and literals.
@@ -634,17 +775,44 @@ func (l *Lexer) Lex(lval *yySymType) int {
 	tok, lit, pos, err := l.s.Scan()
 	if err != nil {
 		l.e = &Error{Message: fmt.Sprintf("%s", err.Error()), Pos: pos, Fatal: true}
+		// например, незакрытая строка
+		l.fail(l.s.reachEOF())
 	}
+	if tok == IDENT {
+		tok = l.contextual(lit)
+	}
+	l.track(tok, lit)
 	lval.tok = ast.Token{Tok: tok, Lit: lit}
 	lval.tok.SetPosition(pos)
 	l.lit = lit
 	l.pos = pos
+	l.prev, l.tok = l.tok, tok
+	l.prevEnd, l.end = l.end, l.s.pos()
+	lval.tok.SetEndPosition(l.end)
 	return tok
 }
 
+// endPos возвращает позицию окончания правила, которое сворачивает разборщик, - сразу за его последней лексемой.
+// Если разборщик уже прочитал следующую лексему (lookahead), то правило закончилось на предыдущей
+func endPos(yylex yyLexer, lookahead int) posit.Position {
+	l, ok := yylex.(*Lexer)
+	if !ok {
+		return posit.Position{}
+	}
+	if lookahead >= 0 {
+		return l.prevEnd
+	}
+	return l.end
+}
+
 // Error sets parse error.
 func (l *Lexer) Error(msg string) {
-	l.e = &Error{Message: msg, Pos: l.pos, Fatal: false}
+	// вариантов выражения слишком много, чтобы разборщик их перечислил, поэтому подсказываем сами
+	if yyErrorVerbose && !strings.Contains(msg, "expecting") && needOperand[l.prev] {
+		msg += ", expecting выражение (имя, число, строка, '(' или '[')"
+	}
+	l.e = &Error{Message: errorWords.Replace(msg), Pos: l.pos, Fatal: false}
+	l.fail(l.tok == EOF)
 }
 
 // Parser provides way to parse the code using Scanner.
@@ -656,6 +824,157 @@ func Parse(s *Scanner) (ast.Stmts, error) {
 	return l.stmts, l.e
 }
 
+// ParseIncremental разбирает текст, который может быть еще не дописан, как в интерактивном режиме.
+// Если текст закончился внутри незавершенной конструкции (блок без завершающего слова, незакрытая скобка или строка),
+// возвращается more == true без ошибки - нужно дописать следующую строку и разобрать весь текст снова
+func ParseIncremental(s *Scanner) (stmts ast.Stmts, more bool, err error) {
+	l := Lexer{s: s}
+	if yyParse(&l) != 0 || l.e != nil {
+		if l.incomplete {
+			return nil, true, nil
+		}
+		return nil, false, l.e
+	}
+	return l.stmts, false, nil
+}
+
+// Incremental накапливает построчный ввод интерактивного режима, пока он не образует законченные операторы.
+// Каждая законченная часть разбирается как код модуля Module. Таблица имен общая для всех частей,
+// а переменные сохраняются в окружении, в котором вызывающий код исполняет разобранные части
+type Incremental struct {
+	Module string
+	src    string
+}
+
+// NewIncremental создает построчный разбор кода модуля с указанным именем
+func NewIncremental(module string) *Incremental {
+	return &Incremental{Module: module}
+}
+
+// Feed добавляет строку к накопленному тексту и разбирает его. При more == true нужна следующая строка,
+// иначе накопленный текст сбрасывается, а возвращаются разобранные операторы или ошибка
+func (r *Incremental) Feed(line string) (stmts ast.Stmts, more bool, err error) {
+	if r.src != "" {
+		r.src += "\n"
+	}
+	r.src += line
+	scanner := &Scanner{}
+	scanner.Init("Модуль " + r.Module + "\n" + r.src)
+	stmts, more, err = ParseIncremental(scanner)
+	if !more {
+		r.src = ""
+	}
+	if e, ok := err.(*Error); ok {
+		// учитываем вставленную строку с объявлением модуля
+		e.Pos.Line--
+	}
+	return
+}
+
+// Pending сообщает, что есть незавершенный ввод
+func (r *Incremental) Pending() bool {
+	return r.src != ""
+}
+
+// SetErrorVerbose включает или выключает перечисление ожидаемых лексем в синтаксических ошибках.
+// По умолчанию перечисление включено
+func SetErrorVerbose(on bool) {
+	yyErrorVerbose = on
+}
+
 func EnableErrorVerbose() {
-	yyErrorVerbose = true
+	SetErrorVerbose(true)
+}
+
+// tokenNames - понятные пользователю названия лексем вместо внутренних имен грамматики.
+// Лексемы, которым соответствует несколько ключевых слов, перечисляют их через косую черту
+var tokenNames = map[string]string{
+	"$end":       "конец текста",
+	"$unk":       "неизвестная лексема",
+	"IDENT":      "имя",
+	"NUMBER":     "число",
+	"STRING":     "строка",
+	"VARARG":     "'...'",
+	"FUNC":       "Функция",
+	"RETURN":     "Возврат",
+	"THROW":      "ВызватьИсключение",
+	"IF":         "Если",
+	"ELSE":       "Иначе",
+	"FOR":        "Для",
+	"IN":         "Из",
+	"EQEQ":       "'='",
+	"NEQ":        "'<>'",
+	"GE":         "'>='",
+	"LE":         "'<='",
+	"OROR":       "Или",
+	"ANDAND":     "И",
+	"TRUE":       "Истина",
+	"FALSE":      "Ложь",
+	"NIL":        "Неопределено",
+	"MODULE":     "Модуль",
+	"TRY":        "Попытка",
+	"CATCH":      "Исключение",
+	"FINALLY":    "Окончательно",
+	"TRYEXPR":    "Попытаться",
+	"GLOBAL":     "Глобальная",
+	"PLUSEQ":     "'+='",
+	"MINUSEQ":    "'-='",
+	"MULEQ":      "'*='",
+	"DIVEQ":      "'/='",
+	"ANDEQ":      "'&='",
+	"OREQ":       "'|='",
+	"BREAK":      "Прервать",
+	"CONTINUE":   "Продолжить",
+	"PLUSPLUS":   "'++'",
+	"MINUSMINUS": "'--'",
+	"POW":        "'**'",
+	"SHIFTLEFT":  "'<<'",
+	"SHIFTRIGHT": "'>>'",
+	"SWITCH":     "Выбор",
+	"CASE":       "Когда/Случай",
+	"DEFAULT":    "Другое",
+	"GO":         "Старт",
+	"CHAN":       "Канал",
+	"MAKE":       "Новый",
+	"OPCHAN":     "'<-'",
+	"ARRAYLIT":   "'[]'",
+	"NULL":       "NULL",
+	"EACH":       "Каждого",
+	"TO":         "По/До",
+	"ELSIF":      "ИначеЕсли",
+	"WHILE":      "Пока",
+	"TERNARY":    "'?('",
+	"TYPECAST":   "имя типа",
+	"DEFINE":     "':='",
+	"WITH":       "Используя",
+	"'{'":        "Тогда/Цикл ('{')",
+	"'}'":        "КонецЕсли/КонецЦикла/КонецФункции/... ('}')",
+	"'!'":        "Не",
+	"'\\n'":      "конец строки",
+}
+
+// errorWords переводит текст ошибок, которые формирует разборщик yacc
+var errorWords = strings.NewReplacer(
+	"syntax error", "синтаксическая ошибка",
+	"unexpected ", "встретилось: ",
+	", expecting ", ", ожидается: ",
+	" or ", " или ",
+)
+
+// needOperand - лексемы, после которых должен идти операнд
+var needOperand = map[int]bool{
+	EQEQ: true, NEQ: true, GE: true, LE: true, OROR: true, ANDAND: true, POW: true,
+	SHIFTLEFT: true, SHIFTRIGHT: true, PLUSEQ: true, MINUSEQ: true, MULEQ: true, DIVEQ: true,
+	ANDEQ: true, OREQ: true, OPCHAN: true, DEFINE: true, IF: true, ELSIF: true, WHILE: true, TRYEXPR: true,
+	int('+'): true, int('-'): true, int('*'): true, int('/'): true, int('%'): true,
+	int('>'): true, int('<'): true, int('!'): true, int(','): true,
+}
+
+func init() {
+	for i, name := range yyToknames {
+		if friendly, ok := tokenNames[name]; ok {
+			yyToknames[i] = friendly
+		}
+	}
+	SetErrorVerbose(true)
 }
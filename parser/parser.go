@@ -12,7 +12,7 @@ import (
 	"github.com/shinanca/gonec/names"
 )
 
-//line parser.y:30
+//line parser.y:36
 type yySymType struct {
 	yys          int
 	compstmt     ast.Stmts
@@ -92,7 +92,11 @@ const ELSIF = 57395
 const WHILE = 57396
 const TERNARY = 57397
 const TYPECAST = 57398
-const UNARY = 57399
+const DEFINE = 57399
+const WITH = 57400
+const TRYEXPR = 57401
+const GLOBAL = 57402
+const UNARY = 57403
 
 var yyToknames = [...]string{
 	"$end",
@@ -151,6 +155,10 @@ var yyToknames = [...]string{
 	"WHILE",
 	"TERNARY",
 	"TYPECAST",
+	"DEFINE",
+	"WITH",
+	"TRYEXPR",
+	"GLOBAL",
 	"'='",
 	"'?'",
 	"':'",
@@ -177,697 +185,851 @@ var yyToknames = [...]string{
 	"';'",
 	"'\\n'",
 }
+
 var yyStatenames = [...]string{}
 
 const yyEofCode = 1
 const yyErrCode = 2
 const yyInitialStackSize = 16
 
-//line parser.y:738
+//line parser.y:1036
 
 //line yacctab:1
-var yyExca = [...]int{
+var yyExca = [...]int16{
 	-1, 1,
 	1, -1,
 	-2, 0,
 	-1, 6,
 	1, 7,
 	25, 7,
-	-2, 127,
-	-1, 12,
-	60, 50,
-	-2, 5,
+	-2, 159,
 	-1, 16,
-	60, 51,
-	-2, 25,
-	-1, 25,
+	57, 66,
+	64, 73,
+	-2, 32,
+	-1, 26,
 	27, 7,
-	-2, 127,
-	-1, 50,
-	60, 50,
-	-2, 128,
-	-1, 127,
+	28, 7,
+	-2, 159,
+	-1, 145,
 	16, 0,
 	17, 0,
-	-2, 83,
-	-1, 128,
+	-2, 112,
+	-1, 146,
 	16, 0,
 	17, 0,
-	-2, 84,
-	-1, 148,
-	60, 51,
-	-2, 45,
-	-1, 154,
-	70, 7,
-	-2, 127,
-	-1, 155,
-	70, 7,
-	-2, 127,
-	-1, 179,
+	-2, 113,
+	-1, 168,
+	64, 73,
+	-2, 66,
+	-1, 176,
+	74, 7,
+	-2, 159,
+	-1, 177,
+	28, 7,
+	74, 7,
+	-2, 159,
+	-1, 178,
+	74, 7,
+	-2, 159,
+	-1, 208,
 	13, 7,
 	53, 7,
-	70, 7,
-	-2, 127,
-	-1, 224,
+	74, 7,
+	-2, 159,
+	-1, 223,
+	74, 7,
+	-2, 159,
+	-1, 261,
 	16, 0,
-	60, 52,
-	-2, 46,
-	-1, 225,
-	1, 47,
-	13, 47,
-	16, 47,
-	25, 47,
-	27, 47,
-	43, 47,
-	44, 47,
-	53, 47,
-	57, 47,
-	60, 53,
-	70, 47,
-	80, 47,
-	81, 47,
-	-2, 54,
-	-1, 232,
-	1, 53,
-	8, 53,
-	13, 53,
-	25, 53,
-	27, 53,
-	43, 53,
-	44, 53,
-	53, 53,
-	60, 53,
-	70, 53,
-	74, 53,
-	77, 53,
-	80, 53,
-	81, 53,
-	-2, 54,
-	-1, 247,
-	70, 7,
-	-2, 127,
-	-1, 257,
-	1, 104,
-	8, 104,
-	13, 104,
-	25, 104,
-	27, 104,
-	43, 104,
-	44, 104,
-	52, 104,
-	53, 104,
-	57, 104,
-	59, 104,
-	60, 104,
-	69, 104,
-	70, 104,
-	74, 104,
-	77, 104,
-	80, 104,
-	81, 104,
-	-2, 102,
-	-1, 259,
-	1, 108,
-	8, 108,
-	13, 108,
-	25, 108,
-	27, 108,
-	43, 108,
-	44, 108,
-	52, 108,
-	53, 108,
-	57, 108,
-	59, 108,
-	60, 108,
-	69, 108,
-	70, 108,
-	74, 108,
-	77, 108,
-	80, 108,
-	81, 108,
-	-2, 106,
-	-1, 266,
-	70, 7,
-	-2, 127,
+	64, 74,
+	-2, 67,
+	-1, 262,
+	1, 68,
+	13, 68,
+	16, 68,
+	25, 68,
+	27, 68,
+	28, 68,
+	43, 68,
+	44, 68,
+	53, 68,
+	57, 68,
+	61, 68,
+	64, 75,
+	74, 68,
+	84, 68,
+	85, 68,
+	-2, 78,
 	-1, 270,
+	1, 75,
+	8, 75,
+	13, 75,
+	25, 75,
+	27, 75,
+	28, 75,
+	43, 75,
+	44, 75,
+	53, 75,
+	64, 75,
+	74, 75,
+	78, 75,
+	81, 75,
+	84, 75,
+	85, 75,
+	-2, 78,
+	-1, 277,
+	74, 7,
+	-2, 159,
+	-1, 292,
+	74, 7,
+	-2, 159,
+	-1, 304,
+	16, 133,
+	17, 133,
+	18, 133,
+	19, 133,
+	20, 133,
+	21, 133,
+	29, 133,
+	30, 133,
+	31, 133,
+	32, 133,
+	33, 133,
+	34, 133,
+	37, 133,
+	38, 133,
+	39, 133,
+	40, 133,
+	41, 133,
+	48, 133,
+	65, 133,
+	66, 133,
+	67, 133,
+	68, 133,
+	69, 133,
+	70, 133,
+	71, 133,
+	75, 133,
+	79, 133,
+	80, 133,
+	82, 133,
+	83, 133,
+	-2, 135,
+	-1, 306,
+	16, 137,
+	17, 137,
+	18, 137,
+	19, 137,
+	20, 137,
+	21, 137,
+	29, 137,
+	30, 137,
+	31, 137,
+	32, 137,
+	33, 137,
+	34, 137,
+	37, 137,
+	38, 137,
+	39, 137,
+	40, 137,
+	41, 137,
+	48, 137,
+	65, 137,
+	66, 137,
+	67, 137,
+	68, 137,
+	69, 137,
+	70, 137,
+	71, 137,
+	75, 137,
+	79, 137,
+	80, 137,
+	82, 137,
+	83, 137,
+	-2, 139,
+	-1, 312,
+	74, 7,
+	-2, 159,
+	-1, 319,
 	43, 7,
 	44, 7,
-	70, 7,
-	-2, 127,
-	-1, 275,
-	70, 7,
-	-2, 127,
-	-1, 276,
-	70, 7,
-	-2, 127,
-	-1, 281,
-	1, 103,
-	8, 103,
-	13, 103,
-	25, 103,
-	27, 103,
-	43, 103,
-	44, 103,
-	52, 103,
-	53, 103,
-	57, 103,
-	59, 103,
-	60, 103,
-	69, 103,
-	70, 103,
-	74, 103,
-	77, 103,
-	80, 103,
-	81, 103,
-	-2, 101,
-	-1, 282,
-	1, 107,
-	8, 107,
-	13, 107,
-	25, 107,
-	27, 107,
-	43, 107,
-	44, 107,
-	52, 107,
-	53, 107,
-	57, 107,
-	59, 107,
-	60, 107,
-	69, 107,
-	70, 107,
-	74, 107,
-	77, 107,
-	80, 107,
-	81, 107,
-	-2, 105,
-	-1, 286,
-	70, 7,
-	-2, 127,
-	-1, 290,
-	70, 7,
-	-2, 127,
-	-1, 291,
-	70, 7,
-	-2, 127,
-	-1, 292,
+	74, 7,
+	-2, 159,
+	-1, 328,
+	74, 7,
+	-2, 159,
+	-1, 331,
+	74, 7,
+	-2, 159,
+	-1, 337,
+	16, 132,
+	17, 132,
+	18, 132,
+	19, 132,
+	20, 132,
+	21, 132,
+	29, 132,
+	30, 132,
+	31, 132,
+	32, 132,
+	33, 132,
+	34, 132,
+	37, 132,
+	38, 132,
+	39, 132,
+	40, 132,
+	41, 132,
+	48, 132,
+	65, 132,
+	66, 132,
+	67, 132,
+	68, 132,
+	69, 132,
+	70, 132,
+	71, 132,
+	75, 132,
+	79, 132,
+	80, 132,
+	82, 132,
+	83, 132,
+	-2, 134,
+	-1, 338,
+	16, 136,
+	17, 136,
+	18, 136,
+	19, 136,
+	20, 136,
+	21, 136,
+	29, 136,
+	30, 136,
+	31, 136,
+	32, 136,
+	33, 136,
+	34, 136,
+	37, 136,
+	38, 136,
+	39, 136,
+	40, 136,
+	41, 136,
+	48, 136,
+	65, 136,
+	66, 136,
+	67, 136,
+	68, 136,
+	69, 136,
+	70, 136,
+	71, 136,
+	75, 136,
+	79, 136,
+	80, 136,
+	82, 136,
+	83, 136,
+	-2, 138,
+	-1, 342,
+	74, 7,
+	-2, 159,
+	-1, 346,
+	74, 7,
+	-2, 159,
+	-1, 347,
+	74, 7,
+	-2, 159,
+	-1, 349,
 	43, 7,
 	44, 7,
-	70, 7,
-	-2, 127,
-	-1, 298,
-	70, 7,
-	-2, 127,
-	-1, 309,
+	74, 7,
+	-2, 159,
+	-1, 366,
+	74, 7,
+	-2, 159,
+	-1, 387,
 	13, 7,
 	53, 7,
-	70, 7,
-	-2, 127,
+	74, 7,
+	-2, 159,
+	-1, 390,
+	43, 7,
+	44, 7,
+	74, 7,
+	-2, 159,
+	-1, 399,
+	43, 7,
+	44, 7,
+	74, 7,
+	-2, 159,
+	-1, 403,
+	74, 7,
+	-2, 159,
+	-1, 404,
+	74, 7,
+	-2, 159,
 }
 
 const yyPrivate = 57344
 
-const yyLast = 3055
-
-var yyAct = [...]int{
-
-	86, 168, 163, 10, 157, 193, 194, 8, 9, 94,
-	95, 173, 252, 16, 212, 177, 47, 17, 174, 210,
-	171, 95, 87, 165, 111, 90, 282, 92, 173, 91,
-	96, 97, 98, 312, 8, 9, 101, 85, 99, 8,
-	9, 281, 104, 106, 277, 248, 110, 113, 241, 115,
-	227, 16, 258, 117, 256, 119, 120, 121, 122, 123,
-	124, 125, 126, 127, 128, 129, 130, 131, 132, 133,
-	134, 135, 136, 137, 138, 12, 311, 139, 140, 141,
-	142, 199, 144, 146, 148, 148, 180, 169, 108, 49,
-	68, 69, 70, 71, 72, 73, 160, 143, 74, 75,
-	59, 147, 149, 310, 150, 308, 150, 100, 205, 82,
-	175, 159, 176, 306, 150, 102, 103, 109, 259, 166,
-	257, 305, 249, 301, 54, 55, 56, 57, 58, 205,
-	195, 196, 53, 150, 286, 295, 80, 81, 150, 76,
-	78, 254, 237, 206, 195, 196, 184, 200, 114, 236,
-	240, 280, 181, 187, 188, 214, 153, 238, 189, 190,
-	84, 191, 203, 204, 197, 198, 7, 89, 208, 158,
-	107, 192, 155, 11, 288, 218, 15, 3, 223, 224,
-	186, 51, 250, 226, 228, 207, 231, 233, 215, 216,
-	178, 287, 195, 196, 14, 169, 239, 152, 273, 217,
-	6, 83, 209, 242, 164, 151, 118, 110, 50, 5,
-	2, 167, 4, 264, 88, 255, 112, 51, 285, 22,
-	13, 261, 1, 262, 0, 0, 185, 116, 0, 0,
-	0, 0, 158, 0, 0, 267, 268, 0, 0, 0,
-	0, 0, 211, 213, 0, 0, 272, 0, 0, 0,
-	0, 274, 231, 0, 0, 279, 0, 68, 69, 70,
-	71, 72, 73, 0, 0, 0, 0, 59, 0, 0,
-	289, 0, 0, 0, 293, 0, 82, 0, 0, 296,
-	297, 246, 247, 0, 0, 0, 251, 0, 253, 300,
-	299, 0, 0, 0, 302, 303, 304, 0, 0, 53,
-	0, 0, 307, 80, 81, 0, 76, 78, 0, 0,
-	0, 0, 0, 313, 0, 0, 270, 0, 0, 0,
-	0, 0, 0, 0, 275, 276, 0, 0, 27, 28,
-	32, 0, 0, 38, 20, 21, 48, 0, 23, 0,
-	0, 0, 0, 0, 0, 292, 33, 34, 35, 0,
-	25, 0, 0, 298, 0, 0, 0, 0, 0, 18,
-	19, 0, 0, 0, 0, 0, 26, 0, 0, 42,
-	0, 43, 46, 44, 36, 0, 0, 0, 24, 37,
-	45, 0, 0, 0, 0, 0, 0, 0, 29, 0,
-	0, 0, 0, 40, 0, 0, 30, 31, 0, 41,
-	39, 0, 0, 0, 8, 9, 62, 63, 65, 67,
-	77, 79, 0, 0, 0, 0, 0, 0, 0, 68,
-	69, 70, 71, 72, 73, 0, 0, 74, 75, 59,
-	60, 61, 0, 0, 0, 0, 0, 0, 82, 0,
+const yyLast = 3499
+
+var yyAct = [...]int16{
+	10, 12, 199, 226, 227, 97, 353, 53, 181, 17,
+	172, 92, 8, 9, 162, 55, 299, 385, 109, 110,
+	129, 297, 202, 110, 364, 185, 363, 102, 93, 186,
+	8, 9, 204, 30, 31, 37, 118, 194, 43, 20,
+	21, 54, 172, 24, 172, 172, 119, 120, 130, 172,
+	359, 38, 39, 40, 338, 26, 395, 337, 394, 295,
+	332, 306, 127, 242, 18, 19, 47, 48, 304, 294,
+	293, 28, 287, 264, 49, 237, 50, 52, 51, 41,
+	210, 355, 229, 25, 42, 29, 342, 27, 32, 22,
+	200, 277, 8, 9, 163, 128, 34, 33, 161, 167,
+	169, 170, 45, 185, 410, 35, 36, 182, 46, 44,
+	409, 117, 352, 8, 9, 228, 229, 163, 228, 229,
+	396, 187, 389, 192, 188, 388, 344, 196, 386, 383,
+	380, 195, 379, 370, 207, 361, 348, 276, 316, 300,
+	278, 275, 233, 391, 392, 250, 280, 343, 282, 225,
+	248, 211, 74, 75, 76, 77, 78, 79, 336, 172,
+	80, 81, 65, 171, 132, 251, 15, 91, 3, 7,
+	108, 88, 100, 215, 216, 175, 11, 220, 221, 222,
+	179, 400, 182, 329, 57, 217, 230, 231, 305, 224,
+	60, 61, 62, 63, 64, 303, 376, 247, 59, 249,
+	245, 402, 86, 87, 330, 82, 84, 296, 90, 263,
+	163, 163, 89, 265, 252, 254, 378, 253, 255, 99,
+	174, 126, 236, 134, 279, 209, 57, 355, 229, 228,
+	229, 177, 178, 401, 200, 284, 30, 31, 37, 14,
+	406, 43, 244, 393, 292, 6, 243, 98, 377, 334,
+	298, 271, 301, 56, 38, 39, 40, 246, 234, 193,
+	184, 183, 173, 129, 136, 107, 103, 5, 198, 47,
+	48, 2, 197, 4, 310, 341, 375, 49, 315, 50,
+	52, 51, 41, 320, 319, 321, 23, 42, 95, 13,
+	322, 32, 1, 327, 163, 328, 0, 331, 324, 34,
+	33, 323, 0, 0, 335, 45, 0, 0, 35, 36,
+	0, 46, 44, 345, 0, 0, 0, 0, 0, 349,
+	351, 0, 0, 0, 358, 354, 357, 0, 356, 362,
+	0, 0, 365, 0, 366, 0, 367, 0, 0, 0,
+	0, 0, 0, 368, 0, 0, 0, 371, 372, 0,
+	373, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 384, 0, 381,
+	382, 0, 0, 94, 0, 0, 390, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 16, 0, 397, 0,
+	0, 398, 0, 399, 0, 96, 403, 404, 0, 101,
+	405, 0, 104, 0, 407, 408, 111, 112, 113, 114,
+	115, 0, 0, 0, 0, 0, 116, 0, 0, 0,
+	121, 122, 123, 125, 0, 0, 131, 0, 133, 0,
+	16, 0, 135, 0, 137, 138, 139, 140, 141, 142,
+	143, 144, 145, 146, 147, 148, 149, 150, 151, 152,
+	153, 154, 155, 156, 0, 0, 157, 158, 159, 160,
+	0, 164, 166, 168, 168, 168, 74, 75, 76, 77,
+	78, 79, 0, 0, 68, 69, 71, 73, 83, 85,
+	0, 0, 0, 0, 189, 88, 0, 74, 75, 76,
+	77, 78, 79, 0, 0, 80, 81, 65, 66, 67,
+	0, 0, 205, 0, 206, 0, 88, 0, 0, 0,
+	0, 0, 59, 0, 0, 0, 86, 87, 0, 82,
+	84, 0, 0, 70, 72, 60, 61, 62, 63, 64,
+	0, 0, 0, 59, 0, 0, 0, 86, 87, 214,
+	82, 84, 8, 9, 0, 0, 0, 0, 218, 219,
+	0, 0, 0, 223, 0, 0, 0, 232, 0, 0,
+	235, 0, 0, 0, 240, 241, 74, 75, 76, 77,
+	78, 79, 0, 0, 0, 0, 65, 0, 256, 0,
+	0, 261, 0, 0, 0, 88, 0, 266, 0, 269,
+	0, 272, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 281, 0, 0, 0, 62, 63, 64, 0,
+	0, 0, 59, 288, 0, 0, 86, 87, 0, 82,
+	84, 0, 0, 0, 0, 302, 0, 0, 0, 0,
+	0, 0, 308, 0, 309, 0, 0, 0, 0, 269,
+	74, 75, 76, 77, 78, 79, 0, 313, 314, 0,
+	65, 0, 0, 68, 69, 71, 73, 83, 85, 88,
+	0, 0, 0, 0, 0, 326, 74, 75, 76, 77,
+	78, 79, 269, 0, 80, 81, 65, 66, 67, 0,
+	0, 0, 0, 0, 0, 88, 59, 0, 0, 318,
+	86, 87, 350, 82, 84, 0, 0, 0, 0, 0,
+	317, 0, 70, 72, 60, 61, 62, 63, 64, 0,
+	0, 0, 59, 0, 0, 0, 86, 87, 369, 82,
+	84, 68, 69, 71, 73, 83, 85, 0, 0, 0,
+	0, 0, 0, 0, 74, 75, 76, 77, 78, 79,
+	0, 0, 80, 81, 65, 66, 67, 0, 0, 0,
+	0, 0, 0, 88, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 286,
+	70, 72, 60, 61, 62, 63, 64, 0, 0, 0,
+	59, 0, 0, 285, 86, 87, 0, 82, 84, 68,
+	69, 71, 73, 83, 85, 0, 0, 0, 0, 0,
+	0, 0, 74, 75, 76, 77, 78, 79, 0, 0,
+	80, 81, 65, 66, 67, 0, 0, 0, 0, 0,
+	0, 88, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 260, 70, 72,
+	60, 61, 62, 63, 64, 0, 0, 0, 59, 0,
+	0, 259, 86, 87, 0, 82, 84, 68, 69, 71,
+	73, 83, 85, 0, 0, 0, 0, 0, 0, 0,
+	74, 75, 76, 77, 78, 79, 0, 0, 80, 81,
+	65, 66, 67, 0, 0, 0, 0, 0, 0, 88,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 258, 70, 72, 60, 61,
+	62, 63, 64, 0, 0, 0, 59, 0, 0, 257,
+	86, 87, 0, 82, 84, 68, 69, 71, 73, 83,
+	85, 0, 0, 0, 0, 0, 0, 0, 74, 75,
+	76, 77, 78, 79, 0, 0, 80, 81, 65, 66,
+	67, 0, 0, 0, 0, 0, 0, 88, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 239, 0, 70, 72, 60, 61, 62, 63,
+	64, 0, 0, 0, 59, 0, 0, 0, 86, 87,
+	238, 82, 84, 68, 69, 71, 73, 83, 85, 0,
+	0, 0, 0, 0, 0, 0, 74, 75, 76, 77,
+	78, 79, 0, 0, 80, 81, 65, 66, 67, 0,
+	0, 0, 0, 0, 0, 88, 0, 0, 0, 0,
+	0, 30, 31, 37, 0, 0, 43, 20, 21, 54,
+	213, 24, 70, 72, 60, 61, 62, 63, 64, 38,
+	39, 40, 59, 26, 0, 0, 86, 87, 212, 82,
+	84, 0, 18, 19, 47, 48, 0, 0, 0, 28,
+	0, 0, 49, 0, 50, 52, 51, 41, 0, 0,
+	0, 25, 42, 29, 0, 27, 32, 22, 0, 0,
+	0, 0, 0, 0, 34, 33, 0, 0, 0, 0,
+	45, 0, 0, 35, 36, 0, 46, 44, 68, 69,
+	71, 73, 83, 85, 0, 0, 0, 0, 0, 0,
+	0, 74, 75, 76, 77, 78, 79, 0, 0, 80,
+	81, 65, 66, 67, 0, 0, 0, 0, 0, 0,
+	88, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 70, 72, 60,
+	61, 62, 63, 64, 0, 387, 0, 59, 0, 0,
+	0, 86, 87, 0, 82, 84, 68, 69, 71, 73,
+	83, 85, 0, 0, 0, 0, 0, 0, 0, 74,
+	75, 76, 77, 78, 79, 0, 0, 80, 81, 65,
+	66, 67, 0, 0, 0, 0, 0, 0, 88, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 374, 0, 70, 72, 60, 61, 62,
+	63, 64, 0, 0, 0, 59, 0, 0, 0, 86,
+	87, 0, 82, 84, 68, 69, 71, 73, 83, 85,
+	0, 0, 0, 0, 0, 0, 0, 74, 75, 76,
+	77, 78, 79, 0, 0, 80, 81, 65, 66, 67,
+	0, 0, 0, 0, 0, 0, 88, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	222, 64, 66, 54, 55, 56, 57, 58, 0, 0,
-	0, 53, 0, 0, 221, 80, 81, 0, 76, 78,
-	62, 63, 65, 67, 77, 79, 0, 0, 0, 0,
-	0, 0, 0, 68, 69, 70, 71, 72, 73, 0,
-	0, 74, 75, 59, 60, 61, 0, 0, 0, 0,
-	0, 0, 82, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 220, 64, 66, 54, 55, 56,
-	57, 58, 0, 0, 0, 53, 0, 0, 219, 80,
-	81, 0, 76, 78, 62, 63, 65, 67, 77, 79,
-	0, 0, 0, 0, 0, 0, 0, 68, 69, 70,
-	71, 72, 73, 0, 0, 74, 75, 59, 60, 61,
-	0, 0, 0, 0, 0, 0, 82, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 202, 0, 64,
-	66, 54, 55, 56, 57, 58, 0, 0, 0, 53,
-	0, 0, 0, 80, 81, 201, 76, 78, 62, 63,
-	65, 67, 77, 79, 0, 0, 0, 0, 0, 0,
-	0, 68, 69, 70, 71, 72, 73, 0, 0, 74,
-	75, 59, 60, 61, 0, 0, 0, 0, 0, 0,
-	82, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 183, 0, 64, 66, 54, 55, 56, 57, 58,
-	0, 0, 0, 53, 0, 0, 0, 80, 81, 182,
-	76, 78, 62, 63, 65, 67, 77, 79, 0, 0,
-	0, 0, 0, 0, 0, 68, 69, 70, 71, 72,
-	73, 0, 0, 74, 75, 59, 60, 61, 0, 0,
-	0, 0, 0, 0, 82, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 64, 66, 54,
-	55, 56, 57, 58, 0, 309, 0, 53, 0, 0,
-	0, 80, 81, 0, 76, 78, 62, 63, 65, 67,
-	77, 79, 0, 0, 0, 0, 0, 0, 0, 68,
-	69, 70, 71, 72, 73, 0, 0, 74, 75, 59,
-	60, 61, 0, 0, 0, 0, 0, 0, 82, 0,
+	0, 0, 0, 70, 72, 60, 61, 62, 63, 64,
+	0, 0, 0, 59, 0, 0, 360, 86, 87, 0,
+	82, 84, 68, 69, 71, 73, 83, 85, 0, 0,
+	0, 0, 0, 0, 0, 74, 75, 76, 77, 78,
+	79, 0, 0, 80, 81, 65, 66, 67, 0, 0,
+	0, 0, 0, 0, 88, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 64, 66, 54, 55, 56, 57, 58, 0, 0,
-	0, 53, 0, 0, 294, 80, 81, 0, 76, 78,
-	62, 63, 65, 67, 77, 79, 0, 0, 0, 0,
-	0, 0, 0, 68, 69, 70, 71, 72, 73, 0,
-	0, 74, 75, 59, 60, 61, 0, 0, 0, 0,
-	0, 0, 82, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 64, 66, 54, 55, 56,
-	57, 58, 0, 291, 0, 53, 0, 0, 0, 80,
-	81, 0, 76, 78, 62, 63, 65, 67, 77, 79,
-	0, 0, 0, 0, 0, 0, 0, 68, 69, 70,
-	71, 72, 73, 0, 0, 74, 75, 59, 60, 61,
-	0, 0, 0, 0, 0, 0, 82, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 64,
-	66, 54, 55, 56, 57, 58, 0, 290, 0, 53,
-	0, 0, 0, 80, 81, 0, 76, 78, 62, 63,
-	65, 67, 77, 79, 0, 0, 0, 0, 0, 0,
-	0, 68, 69, 70, 71, 72, 73, 0, 0, 74,
-	75, 59, 60, 61, 0, 0, 0, 0, 0, 0,
-	82, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 64, 66, 54, 55, 56, 57, 58,
-	0, 0, 0, 53, 0, 0, 284, 80, 81, 0,
-	76, 78, 62, 63, 65, 67, 77, 79, 0, 0,
-	0, 0, 0, 0, 0, 68, 69, 70, 71, 72,
-	73, 0, 0, 74, 75, 59, 60, 61, 0, 0,
-	0, 0, 0, 0, 82, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 64, 66, 54,
-	55, 56, 57, 58, 0, 0, 0, 53, 0, 0,
-	283, 80, 81, 0, 76, 78, 62, 63, 65, 67,
-	77, 79, 0, 0, 0, 0, 0, 0, 0, 68,
-	69, 70, 71, 72, 73, 0, 0, 74, 75, 59,
-	60, 61, 0, 0, 0, 0, 0, 0, 82, 0,
+	0, 70, 72, 60, 61, 62, 63, 64, 0, 347,
+	0, 59, 0, 0, 0, 86, 87, 0, 82, 84,
+	68, 69, 71, 73, 83, 85, 0, 0, 0, 0,
+	0, 0, 0, 74, 75, 76, 77, 78, 79, 0,
+	0, 80, 81, 65, 66, 67, 0, 0, 0, 0,
+	0, 0, 88, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 70,
+	72, 60, 61, 62, 63, 64, 0, 346, 0, 59,
+	0, 0, 0, 86, 87, 0, 82, 84, 68, 69,
+	71, 73, 83, 85, 0, 0, 0, 0, 0, 0,
+	0, 74, 75, 76, 77, 78, 79, 0, 0, 80,
+	81, 65, 66, 67, 0, 0, 0, 0, 0, 0,
+	88, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 70, 72, 60,
+	61, 62, 63, 64, 0, 0, 0, 59, 0, 0,
+	340, 86, 87, 0, 82, 84, 68, 69, 71, 73,
+	83, 85, 0, 0, 0, 0, 0, 0, 0, 74,
+	75, 76, 77, 78, 79, 0, 0, 80, 81, 65,
+	66, 67, 0, 0, 0, 0, 0, 0, 88, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 64, 66, 54, 55, 56, 57, 58, 0, 0,
-	0, 53, 0, 0, 0, 80, 81, 271, 76, 78,
-	62, 63, 65, 67, 77, 79, 0, 0, 0, 0,
-	0, 0, 0, 68, 69, 70, 71, 72, 73, 0,
-	0, 74, 75, 59, 60, 61, 0, 0, 0, 0,
-	0, 0, 82, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 269, 0, 64, 66, 54, 55, 56,
-	57, 58, 0, 0, 0, 53, 0, 0, 0, 80,
-	81, 0, 76, 78, 62, 63, 65, 67, 77, 79,
-	0, 0, 0, 0, 0, 0, 0, 68, 69, 70,
-	71, 72, 73, 0, 0, 74, 75, 59, 60, 61,
-	0, 0, 0, 0, 0, 0, 82, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 64,
-	66, 54, 55, 56, 57, 58, 0, 266, 0, 53,
-	0, 0, 0, 80, 81, 0, 76, 78, 62, 63,
-	65, 67, 77, 79, 0, 0, 0, 0, 0, 0,
-	0, 68, 69, 70, 71, 72, 73, 0, 0, 74,
-	75, 59, 60, 61, 0, 0, 0, 0, 0, 0,
-	82, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 64, 66, 54, 55, 56, 57, 58,
-	0, 0, 0, 53, 0, 0, 0, 80, 81, 265,
-	76, 78, 62, 63, 65, 67, 77, 79, 0, 0,
-	0, 0, 0, 0, 0, 68, 69, 70, 71, 72,
-	73, 0, 0, 74, 75, 59, 60, 61, 0, 0,
-	0, 0, 0, 0, 82, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 64, 66, 54,
-	55, 56, 57, 58, 0, 0, 0, 53, 0, 0,
-	263, 80, 81, 0, 76, 78, 62, 63, 65, 67,
-	77, 79, 0, 0, 0, 0, 0, 0, 0, 68,
-	69, 70, 71, 72, 73, 0, 0, 74, 75, 59,
-	60, 61, 0, 0, 0, 0, 0, 0, 82, 0,
+	0, 0, 0, 0, 0, 70, 72, 60, 61, 62,
+	63, 64, 0, 0, 0, 59, 0, 0, 339, 86,
+	87, 0, 82, 84, 68, 69, 71, 73, 83, 85,
+	0, 0, 0, 0, 0, 0, 0, 74, 75, 76,
+	77, 78, 79, 0, 0, 80, 81, 65, 66, 67,
+	0, 0, 0, 0, 0, 0, 88, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 64, 66, 54, 55, 56, 57, 58, 0, 0,
-	0, 53, 0, 0, 260, 80, 81, 0, 76, 78,
-	62, 63, 65, 67, 77, 79, 0, 0, 0, 0,
-	0, 0, 0, 68, 69, 70, 71, 72, 73, 0,
-	0, 74, 75, 59, 60, 61, 0, 0, 0, 0,
-	0, 0, 82, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 245, 64, 66, 54, 55, 56,
-	57, 58, 0, 0, 0, 53, 0, 0, 0, 80,
-	81, 0, 76, 78, 62, 63, 65, 67, 77, 79,
-	0, 0, 0, 0, 0, 0, 0, 68, 69, 70,
-	71, 72, 73, 0, 0, 74, 75, 59, 60, 61,
-	0, 0, 0, 0, 0, 0, 82, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 64,
-	66, 54, 55, 56, 57, 58, 0, 0, 0, 53,
-	0, 0, 0, 80, 81, 244, 76, 78, 62, 63,
-	65, 67, 77, 79, 0, 0, 0, 0, 0, 0,
-	0, 68, 69, 70, 71, 72, 73, 0, 0, 74,
-	75, 59, 60, 61, 0, 0, 0, 0, 0, 0,
-	82, 0, 0, 0, 235, 0, 0, 0, 0, 0,
-	0, 0, 0, 64, 66, 54, 55, 56, 57, 58,
-	0, 0, 0, 53, 0, 0, 0, 80, 81, 0,
-	76, 78, 62, 63, 65, 67, 77, 79, 0, 0,
-	0, 0, 0, 0, 0, 68, 69, 70, 71, 72,
-	73, 0, 0, 74, 75, 59, 60, 61, 0, 0,
-	0, 0, 0, 0, 82, 0, 0, 0, 234, 0,
-	0, 0, 0, 0, 0, 0, 0, 64, 66, 54,
-	55, 56, 57, 58, 0, 0, 0, 53, 0, 0,
-	0, 80, 81, 0, 76, 78, 62, 63, 65, 67,
-	77, 79, 0, 0, 0, 0, 0, 0, 0, 68,
-	69, 70, 71, 72, 73, 0, 0, 74, 75, 59,
-	60, 61, 0, 0, 0, 0, 0, 0, 82, 0,
+	0, 0, 0, 70, 72, 60, 61, 62, 63, 64,
+	0, 0, 0, 59, 0, 0, 0, 86, 87, 325,
+	82, 84, 68, 69, 71, 73, 83, 85, 0, 0,
+	0, 0, 0, 0, 0, 74, 75, 76, 77, 78,
+	79, 0, 0, 80, 81, 65, 66, 67, 0, 0,
+	0, 0, 0, 0, 88, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 64, 66, 54, 55, 56, 57, 58, 0, 0,
-	0, 53, 0, 0, 0, 80, 81, 230, 76, 78,
-	62, 63, 65, 67, 77, 79, 0, 0, 0, 0,
-	0, 0, 0, 68, 69, 70, 71, 72, 73, 0,
-	0, 74, 75, 59, 60, 61, 0, 0, 0, 0,
-	0, 0, 82, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 64, 66, 54, 55, 56,
-	57, 58, 0, 179, 0, 53, 0, 0, 0, 80,
-	81, 0, 76, 78, 62, 63, 65, 67, 77, 79,
-	0, 0, 0, 0, 0, 0, 0, 68, 69, 70,
-	71, 72, 73, 0, 0, 74, 75, 59, 60, 61,
-	0, 0, 0, 0, 0, 0, 82, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 64,
-	66, 54, 55, 56, 57, 58, 0, 0, 0, 53,
-	0, 0, 170, 80, 81, 0, 76, 78, 62, 63,
-	65, 67, 77, 79, 0, 0, 0, 0, 0, 0,
-	0, 68, 69, 70, 71, 72, 73, 0, 0, 74,
-	75, 59, 60, 61, 0, 0, 0, 0, 0, 0,
-	82, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 162, 64, 66, 54, 55, 56, 57, 58,
-	0, 0, 0, 53, 0, 0, 0, 80, 81, 0,
-	76, 78, 62, 63, 65, 67, 77, 79, 0, 0,
-	0, 0, 0, 0, 0, 68, 69, 70, 71, 72,
-	73, 0, 0, 74, 75, 59, 60, 61, 0, 0,
-	0, 0, 0, 0, 82, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 156, 0, 64, 66, 54,
-	55, 56, 57, 58, 0, 0, 0, 53, 0, 0,
-	0, 80, 81, 0, 76, 78, 62, 63, 65, 67,
-	77, 79, 0, 0, 0, 0, 0, 0, 0, 68,
-	69, 70, 71, 72, 73, 0, 0, 74, 75, 59,
-	60, 61, 0, 0, 0, 0, 0, 0, 82, 0,
+	0, 70, 72, 60, 61, 62, 63, 64, 0, 312,
+	0, 59, 0, 0, 0, 86, 87, 0, 82, 84,
+	68, 69, 71, 73, 83, 85, 0, 0, 0, 0,
+	0, 0, 0, 74, 75, 76, 77, 78, 79, 0,
+	0, 80, 81, 65, 66, 67, 0, 0, 0, 0,
+	0, 0, 88, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 70,
+	72, 60, 61, 62, 63, 64, 0, 0, 0, 59,
+	0, 0, 0, 86, 87, 311, 82, 84, 68, 69,
+	71, 73, 83, 85, 0, 0, 0, 0, 0, 0,
+	0, 74, 75, 76, 77, 78, 79, 0, 0, 80,
+	81, 65, 66, 67, 0, 0, 0, 0, 0, 0,
+	88, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 70, 72, 60,
+	61, 62, 63, 64, 0, 0, 0, 59, 0, 0,
+	307, 86, 87, 0, 82, 84, 68, 69, 71, 73,
+	83, 85, 0, 0, 0, 0, 0, 0, 0, 74,
+	75, 76, 77, 78, 79, 0, 0, 80, 81, 65,
+	66, 67, 0, 0, 0, 0, 0, 0, 88, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 64, 66, 54, 55, 56, 57, 58, 0, 154,
-	0, 53, 0, 0, 0, 80, 81, 0, 76, 78,
-	62, 63, 65, 67, 77, 79, 0, 0, 0, 0,
-	0, 0, 0, 68, 69, 70, 71, 72, 73, 0,
-	0, 74, 75, 59, 60, 61, 0, 0, 0, 0,
-	0, 0, 82, 0, 0, 0, 0, 0, 0, 0,
-	0, 52, 0, 0, 0, 64, 66, 54, 55, 56,
-	57, 58, 0, 0, 0, 53, 0, 0, 0, 80,
-	81, 0, 76, 78, 62, 63, 65, 67, 77, 79,
-	0, 0, 0, 0, 0, 0, 0, 68, 69, 70,
-	71, 72, 73, 0, 0, 74, 75, 59, 60, 61,
-	0, 0, 0, 0, 0, 0, 82, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 64,
-	66, 54, 55, 56, 57, 58, 0, 0, 0, 53,
-	0, 0, 0, 80, 81, 0, 76, 78, 62, 63,
-	65, 67, 77, 79, 0, 0, 0, 0, 0, 0,
-	0, 68, 69, 70, 71, 72, 73, 0, 0, 74,
-	75, 59, 60, 61, 0, 0, 0, 0, 0, 0,
-	82, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 64, 66, 54, 55, 56, 57, 58,
-	0, 0, 0, 53, 0, 0, 0, 172, 81, 0,
-	76, 78, 63, 65, 67, 77, 79, 0, 0, 0,
-	0, 0, 0, 0, 68, 69, 70, 71, 72, 73,
-	0, 0, 74, 75, 59, 60, 61, 0, 0, 0,
-	0, 0, 0, 82, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 64, 66, 54, 55,
-	56, 57, 58, 0, 0, 0, 53, 0, 0, 0,
-	80, 81, 0, 76, 78, 62, 63, 65, 67, 0,
-	79, 0, 0, 0, 0, 0, 0, 0, 68, 69,
-	70, 71, 72, 73, 0, 0, 74, 75, 59, 60,
-	61, 0, 0, 0, 0, 0, 0, 82, 0, 0,
+	0, 0, 0, 0, 291, 70, 72, 60, 61, 62,
+	63, 64, 0, 0, 0, 59, 0, 0, 0, 86,
+	87, 0, 82, 84, 68, 69, 71, 73, 83, 85,
+	0, 0, 0, 0, 0, 0, 0, 74, 75, 76,
+	77, 78, 79, 0, 0, 80, 81, 65, 66, 67,
+	0, 0, 0, 0, 0, 0, 88, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	64, 66, 54, 55, 56, 57, 58, 0, 0, 0,
-	53, 0, 0, 0, 80, 81, 0, 76, 78, 62,
-	63, 65, 67, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 68, 69, 70, 71, 72, 73, 0, 0,
-	74, 75, 59, 60, 61, 0, 0, 0, 0, 0,
-	0, 82, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 64, 66, 54, 55, 56, 57,
-	58, 0, 65, 67, 53, 0, 0, 0, 80, 81,
-	0, 76, 78, 68, 69, 70, 71, 72, 73, 0,
-	0, 74, 75, 59, 60, 61, 0, 0, 0, 0,
-	0, 0, 82, 0, 27, 28, 32, 0, 0, 38,
-	20, 21, 48, 0, 23, 64, 66, 54, 55, 56,
-	57, 58, 33, 34, 35, 53, 25, 0, 0, 80,
-	81, 0, 76, 78, 0, 18, 19, 0, 0, 232,
-	28, 32, 26, 0, 38, 42, 0, 43, 46, 44,
-	36, 0, 0, 0, 24, 37, 45, 33, 34, 35,
-	0, 0, 0, 0, 29, 0, 0, 0, 0, 40,
-	0, 0, 30, 31, 0, 41, 39, 0, 0, 0,
-	42, 0, 43, 46, 44, 36, 0, 0, 0, 0,
-	37, 45, 0, 0, 0, 27, 28, 32, 0, 29,
-	38, 0, 0, 0, 40, 0, 0, 30, 31, 0,
-	41, 39, 278, 33, 34, 35, 0, 0, 0, 0,
+	0, 0, 0, 70, 72, 60, 61, 62, 63, 64,
+	0, 0, 0, 59, 0, 0, 0, 86, 87, 290,
+	82, 84, 68, 69, 71, 73, 83, 85, 0, 0,
+	0, 0, 0, 0, 0, 74, 75, 76, 77, 78,
+	79, 0, 0, 80, 81, 65, 66, 67, 0, 0,
+	0, 0, 0, 0, 88, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 283,
+	0, 70, 72, 60, 61, 62, 63, 64, 0, 0,
+	0, 59, 0, 0, 0, 86, 87, 0, 82, 84,
+	68, 69, 71, 73, 83, 85, 0, 0, 0, 0,
+	0, 0, 0, 74, 75, 76, 77, 78, 79, 0,
+	0, 80, 81, 65, 66, 67, 0, 0, 0, 0,
+	0, 0, 88, 0, 0, 0, 274, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 70,
+	72, 60, 61, 62, 63, 64, 0, 0, 0, 59,
+	0, 0, 0, 86, 87, 0, 82, 84, 68, 69,
+	71, 73, 83, 85, 0, 0, 0, 0, 0, 0,
+	0, 74, 75, 76, 77, 78, 79, 0, 0, 80,
+	81, 65, 66, 67, 0, 0, 0, 0, 0, 0,
+	88, 0, 0, 0, 273, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 70, 72, 60,
+	61, 62, 63, 64, 0, 0, 0, 59, 0, 0,
+	0, 86, 87, 0, 82, 84, 68, 69, 71, 73,
+	83, 85, 0, 0, 0, 0, 0, 0, 0, 74,
+	75, 76, 77, 78, 79, 0, 0, 80, 81, 65,
+	66, 67, 0, 0, 0, 0, 0, 0, 88, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	27, 28, 32, 0, 0, 38, 42, 0, 43, 46,
-	44, 36, 0, 0, 0, 0, 37, 45, 33, 34,
-	35, 0, 0, 0, 0, 29, 0, 0, 0, 0,
-	40, 0, 0, 30, 31, 0, 41, 39, 243, 0,
-	0, 42, 0, 43, 46, 44, 36, 0, 0, 0,
-	0, 37, 45, 0, 0, 0, 27, 28, 32, 0,
-	29, 38, 0, 0, 0, 40, 0, 0, 30, 31,
-	0, 41, 39, 229, 33, 34, 35, 0, 0, 0,
+	0, 0, 0, 0, 0, 70, 72, 60, 61, 62,
+	63, 64, 0, 0, 0, 59, 0, 0, 0, 86,
+	87, 268, 82, 84, 68, 69, 71, 73, 83, 85,
+	0, 0, 0, 0, 0, 0, 0, 74, 75, 76,
+	77, 78, 79, 0, 0, 80, 81, 65, 66, 67,
+	0, 0, 0, 0, 0, 0, 88, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 42, 0, 43,
-	46, 44, 36, 0, 0, 0, 0, 37, 45, 0,
-	0, 161, 27, 28, 32, 0, 29, 38, 0, 0,
-	0, 40, 0, 0, 30, 31, 0, 41, 39, 0,
-	33, 34, 35, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 70, 72, 60, 61, 62, 63, 64,
+	0, 208, 0, 59, 0, 0, 0, 86, 87, 0,
+	82, 84, 68, 69, 71, 73, 83, 85, 0, 0,
+	0, 0, 0, 0, 0, 74, 75, 76, 77, 78,
+	79, 0, 0, 80, 81, 65, 66, 67, 0, 0,
+	0, 0, 0, 0, 88, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 42, 0, 43, 46, 44, 36, 0,
-	0, 0, 0, 37, 45, 0, 0, 145, 27, 28,
-	32, 0, 29, 38, 0, 0, 0, 40, 0, 0,
-	30, 31, 0, 41, 39, 0, 33, 34, 35, 0,
+	0, 70, 72, 60, 61, 62, 63, 64, 0, 0,
+	0, 59, 0, 0, 201, 86, 87, 0, 82, 84,
+	68, 69, 71, 73, 83, 85, 0, 0, 0, 0,
+	0, 0, 0, 74, 75, 76, 77, 78, 79, 0,
+	0, 80, 81, 65, 66, 67, 0, 0, 0, 0,
+	0, 0, 88, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 191, 70,
+	72, 60, 61, 62, 63, 64, 0, 0, 0, 59,
+	0, 0, 0, 86, 87, 0, 82, 84, 68, 69,
+	71, 73, 83, 85, 0, 0, 0, 0, 0, 0,
+	0, 74, 75, 76, 77, 78, 79, 0, 0, 80,
+	81, 65, 66, 67, 0, 0, 0, 0, 0, 0,
+	88, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 180, 0, 70, 72, 60,
+	61, 62, 63, 64, 0, 0, 0, 59, 0, 0,
+	0, 86, 87, 0, 82, 84, 68, 69, 71, 73,
+	83, 85, 0, 0, 0, 0, 0, 0, 0, 74,
+	75, 76, 77, 78, 79, 0, 0, 80, 81, 65,
+	66, 67, 0, 0, 0, 0, 0, 0, 88, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 42,
-	0, 43, 46, 44, 36, 0, 0, 0, 0, 37,
-	45, 0, 0, 93, 27, 28, 32, 0, 29, 38,
-	0, 0, 0, 40, 0, 0, 30, 31, 0, 41,
-	39, 0, 33, 34, 35, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 232,
-	28, 32, 0, 0, 38, 42, 0, 43, 46, 44,
-	36, 0, 0, 0, 0, 37, 45, 33, 34, 35,
-	0, 0, 0, 0, 29, 0, 0, 0, 0, 40,
-	0, 0, 30, 31, 0, 41, 39, 0, 0, 0,
-	42, 0, 43, 46, 44, 36, 0, 0, 0, 0,
-	37, 45, 0, 0, 0, 225, 28, 32, 0, 29,
-	38, 0, 0, 0, 40, 0, 0, 30, 31, 0,
-	41, 39, 0, 33, 34, 35, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 70, 72, 60, 61, 62,
+	63, 64, 0, 176, 0, 59, 0, 0, 0, 86,
+	87, 0, 82, 84, 68, 69, 71, 73, 83, 85,
+	0, 0, 0, 0, 0, 0, 0, 74, 75, 76,
+	77, 78, 79, 0, 0, 80, 81, 65, 66, 67,
+	0, 0, 0, 0, 0, 0, 88, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 58,
+	0, 0, 0, 70, 72, 60, 61, 62, 63, 64,
+	0, 0, 0, 59, 0, 0, 0, 86, 87, 0,
+	82, 84, 68, 69, 71, 73, 83, 85, 0, 0,
+	0, 0, 0, 0, 0, 74, 75, 76, 77, 78,
+	79, 0, 0, 80, 81, 65, 66, 67, 0, 0,
+	0, 0, 0, 0, 88, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	105, 28, 32, 0, 0, 38, 42, 0, 43, 46,
-	44, 36, 0, 0, 0, 0, 37, 45, 33, 34,
-	35, 0, 0, 0, 0, 29, 0, 0, 0, 0,
-	40, 0, 0, 30, 31, 0, 41, 39, 0, 0,
-	0, 42, 0, 43, 46, 44, 36, 0, 0, 0,
-	0, 37, 45, 0, 68, 69, 70, 71, 72, 73,
-	29, 0, 0, 0, 59, 40, 0, 0, 30, 31,
-	0, 41, 39, 82, 0, 0, 0, 0, 0, 0,
+	0, 70, 72, 60, 61, 62, 63, 64, 0, 0,
+	0, 59, 0, 0, 0, 86, 87, 0, 82, 84,
+	68, 69, 71, 73, 83, 85, 0, 0, 0, 0,
+	0, 0, 0, 74, 75, 76, 77, 78, 79, 0,
+	0, 80, 81, 65, 66, 67, 0, 0, 0, 0,
+	0, 0, 88, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 70,
+	72, 60, 61, 62, 63, 64, 0, 0, 0, 59,
+	0, 0, 0, 203, 87, 0, 82, 84, 69, 71,
+	73, 83, 85, 0, 0, 0, 0, 0, 0, 0,
+	74, 75, 76, 77, 78, 79, 0, 0, 80, 81,
+	65, 66, 67, 0, 0, 0, 0, 0, 0, 88,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	56, 57, 58, 0, 0, 0, 53, 0, 0, 0,
-	80, 81, 0, 76, 78,
+	0, 0, 0, 0, 0, 0, 70, 72, 60, 61,
+	62, 63, 64, 0, 0, 0, 59, 0, 0, 0,
+	86, 87, 0, 82, 84, 68, 69, 71, 73, 0,
+	85, 0, 0, 0, 0, 0, 0, 0, 74, 75,
+	76, 77, 78, 79, 0, 0, 80, 81, 65, 66,
+	67, 0, 0, 0, 0, 0, 0, 88, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 70, 72, 60, 61, 62, 63,
+	64, 0, 0, 0, 59, 0, 0, 0, 86, 87,
+	0, 82, 84, 68, 69, 71, 73, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 74, 75, 76, 77,
+	78, 79, 0, 0, 80, 81, 65, 66, 67, 0,
+	0, 0, 0, 0, 0, 88, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 70, 72, 60, 61, 62, 63, 64, 0,
+	71, 73, 59, 0, 0, 0, 86, 87, 0, 82,
+	84, 74, 75, 76, 77, 78, 79, 0, 0, 80,
+	81, 65, 66, 67, 0, 0, 270, 31, 37, 0,
+	88, 43, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 38, 39, 40, 70, 72, 60,
+	61, 62, 63, 64, 0, 0, 0, 59, 0, 47,
+	48, 86, 87, 0, 82, 84, 0, 49, 0, 50,
+	52, 51, 41, 0, 30, 31, 37, 42, 95, 43,
+	0, 32, 0, 0, 0, 0, 0, 0, 0, 34,
+	33, 0, 38, 39, 40, 45, 0, 0, 35, 36,
+	0, 46, 44, 333, 0, 0, 0, 47, 48, 0,
+	0, 0, 0, 0, 0, 49, 0, 50, 52, 51,
+	41, 0, 30, 31, 37, 42, 95, 43, 0, 32,
+	0, 0, 0, 0, 0, 0, 0, 34, 33, 0,
+	38, 39, 40, 45, 0, 0, 35, 36, 0, 46,
+	44, 289, 0, 0, 0, 47, 48, 0, 0, 0,
+	0, 0, 0, 49, 0, 50, 52, 51, 41, 0,
+	30, 31, 37, 42, 95, 43, 0, 32, 0, 0,
+	0, 0, 0, 0, 0, 34, 33, 0, 38, 39,
+	40, 45, 0, 0, 35, 36, 0, 46, 44, 267,
+	0, 0, 0, 47, 48, 0, 0, 0, 0, 0,
+	0, 49, 0, 50, 52, 51, 41, 0, 106, 0,
+	0, 42, 95, 0, 0, 32, 0, 0, 0, 105,
+	30, 31, 37, 34, 33, 43, 0, 0, 0, 45,
+	0, 0, 35, 36, 0, 46, 44, 0, 38, 39,
+	40, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 47, 48, 0, 0, 0, 0, 0,
+	0, 49, 0, 50, 52, 51, 41, 0, 30, 31,
+	37, 42, 95, 43, 0, 32, 0, 0, 0, 190,
+	0, 0, 0, 34, 33, 0, 38, 39, 40, 45,
+	0, 0, 35, 36, 0, 46, 44, 0, 0, 0,
+	0, 47, 48, 0, 0, 0, 0, 0, 0, 49,
+	0, 50, 52, 51, 41, 0, 270, 31, 37, 42,
+	95, 43, 0, 32, 0, 0, 0, 165, 0, 0,
+	0, 34, 33, 0, 38, 39, 40, 45, 0, 0,
+	35, 36, 0, 46, 44, 0, 0, 0, 0, 47,
+	48, 0, 0, 0, 0, 0, 0, 49, 0, 50,
+	52, 51, 41, 0, 262, 31, 37, 42, 95, 43,
+	0, 32, 0, 0, 0, 0, 0, 0, 0, 34,
+	33, 0, 38, 39, 40, 45, 0, 0, 35, 36,
+	0, 46, 44, 0, 0, 0, 0, 47, 48, 0,
+	0, 0, 0, 0, 0, 49, 0, 50, 52, 51,
+	41, 0, 124, 31, 37, 42, 95, 43, 0, 32,
+	0, 0, 0, 0, 0, 0, 0, 34, 33, 0,
+	38, 39, 40, 45, 0, 0, 35, 36, 0, 46,
+	44, 0, 0, 0, 0, 47, 48, 0, 0, 0,
+	0, 0, 0, 49, 0, 50, 52, 51, 41, 0,
+	0, 0, 0, 42, 95, 0, 0, 32, 0, 0,
+	0, 0, 0, 0, 0, 34, 33, 0, 0, 0,
+	0, 45, 0, 0, 35, 36, 0, 46, 44,
 }
-var yyPact = [...]int{
-
-	152, 152, -1000, 205, -1000, -73, -73, -1000, -1000, -1000,
-	-1000, -1000, 2470, -73, -73, -1000, 2054, 144, -1000, -1000,
-	2820, 2820, -1000, 163, 2820, -73, 2764, -66, -1000, 2820,
-	2820, 2820, -1000, -1000, -1000, -1000, -1000, 2820, 32, -73,
-	-73, 2820, 2946, 42, -51, 203, 2820, 88, 2820, -1000,
-	324, -1000, 2820, 202, 2820, 2820, 2820, 2820, 2820, 2820,
-	2820, 2820, 2820, 2820, 2820, 2820, 2820, 2820, 2820, 2820,
-	2820, 2820, 2820, 2820, -1000, -1000, 2820, 2820, 2820, 2820,
-	2820, 2708, 2820, 2820, 2820, 54, 2118, 2118, 201, 140,
-	1990, 145, 1926, -73, 2820, 2652, 228, 228, 228, 1862,
-	200, -52, 2820, 189, 1798, -55, 2182, -43, -57, 2820,
-	-1000, 2820, -60, 2118, -73, 1734, -1000, 2118, -1000, 2975,
-	2975, 228, 228, 228, 2118, 61, 61, 2424, 2424, 61,
-	61, 61, 61, 2118, 2118, 2118, 2118, 2118, 2118, 2118,
-	2309, 2118, 2373, 78, 582, 2820, 2118, -1000, 2118, -1000,
-	-73, 165, 2820, 2820, -73, -73, -73, 101, 149, 73,
-	518, 2820, 2820, 69, 177, 198, -41, -46, -1000, 96,
-	-1000, 2820, 2820, 195, 2820, 454, 390, 2820, 2911, -73,
-	-24, -1000, -1000, 2596, 1670, 2855, 2820, 1606, 1542, 79,
-	72, 87, -1000, -1000, -1000, 2820, 91, -1000, -1000, -26,
-	-1000, -1000, 2561, 1478, 1414, -73, -73, -29, 48, 174,
-	-73, -65, -73, 71, 2820, 46, 44, -1000, 1350, -1000,
-	2820, -1000, 2820, 1286, 2245, -66, -1000, -1000, 1222, -1000,
-	-1000, 2118, -66, 1158, 2820, 2820, -1000, -1000, -1000, 1094,
-	-73, -1000, 1030, -1000, -1000, 2820, 194, -73, -73, -73,
-	-30, 2505, -1000, 81, -1000, 2118, -33, -1000, -48, -1000,
-	-1000, 966, 902, -1000, 121, -1000, -73, 838, 774, -73,
-	-73, -1000, 710, -1000, 65, -73, -73, -73, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -73, -1000, 2820, 53,
-	-73, -73, -73, -1000, -1000, -1000, 51, 43, -73, 35,
-	646, -1000, 33, 6, -1000, -1000, -1000, -37, -1000, -73,
-	-1000, -1000, -1000, -1000,
+
+var yyPact = [...]int16{
+	143, 143, -32768, 263, -32768, -72, -72, -32768, -32768, -32768,
+	-32768, -32768, 1027, -72, -72, -32768, 2588, 151, -32768, -32768,
+	232, 232, 243, -32768, 168, 232, -72, 262, 3166, 261,
+	-61, -32768, 232, 232, 232, 232, 232, -32768, -32768, -32768,
+	-32768, -32768, 232, 32, -72, -72, 232, 232, 232, 3418,
+	16, -31, 232, 100, 232, -32768, 29, -32768, 232, 260,
+	232, 232, 232, 232, 232, 232, 232, 232, 232, 232,
+	232, 232, 232, 232, 232, 232, 232, 232, 232, 232,
+	-32768, -32768, 232, 232, 232, 232, 232, 3274, 232, 232,
+	232, 232, -32768, 99, 2656, 259, 2656, 95, -32768, 258,
+	159, 2520, 204, 164, 2452, -72, 257, 256, -50, 232,
+	3226, 2656, 437, 437, 437, 437, 2384, 255, -42, 232,
+	228, 2316, 437, 437, -57, 2724, 28, -47, 232, -32768,
+	232, 2656, -72, 2248, -32768, 2656, -32768, 537, 537, 611,
+	611, 611, 611, 123, 123, 2982, 2982, 123, 123, 123,
+	123, 2656, 2656, 2656, 2656, 2656, 2656, 2656, 2859, 2656,
+	2927, 217, 2, 87, 977, 232, 2656, -32768, 2656, -32768,
+	-32768, -72, -72, 170, 232, 232, -72, -72, -72, 232,
+	-72, 75, 186, 232, 69, 254, 232, 214, -3, 909,
+	232, 232, -15, 238, 253, -72, 86, -72, 81, -32768,
+	102, -32768, 232, 232, 232, 841, 773, 3370, -72, -5,
+	-32768, -72, -32768, 3118, 2180, 3322, 247, 232, 2112, 2044,
+	67, 63, 66, 458, 72, -32768, -32768, -32768, 232, 85,
+	-32768, -32768, 1976, -72, -32768, 705, -6, -32768, -32768, 3070,
+	1908, 1840, -72, -8, -9, -19, 199, -60, 8, 65,
+	-72, 232, 187, -10, 180, -17, 1772, -32768, 232, -32768,
+	232, 2791, -61, -32768, -32768, 3322, 1704, -32768, -32768, 2656,
+	-61, -32768, 1636, 232, 232, -32768, -32768, -72, -32768, 64,
+	-32768, 637, -72, -72, 243, -32768, 232, -32768, 1568, -32768,
+	-32768, 232, -72, -72, 179, -72, -18, -32768, 3022, 245,
+	-32768, 84, 2656, -21, -32768, -24, -32768, -32768, 1500, 1432,
+	73, -32768, -72, 1364, 1296, 62, -32768, -72, 232, -72,
+	38, 184, -54, -28, -32768, -32768, 1228, 61, -72, -53,
+	-55, -72, -72, -32768, -72, -32768, -32768, -32768, -32768, -32768,
+	-32768, -32768, -72, -32768, 232, 59, -72, -72, -32768, -72,
+	1160, -32768, -32768, -32768, -32768, 192, -32768, -32768, 58, -32768,
+	-32768, -32768, 56, 243, 243, 55, -72, -64, 54, 1092,
+	-32768, 51, 48, -32768, -72, 80, -32768, 239, -32768, -32768,
+	-32768, -20, -22, -32768, 46, -32768, -32768, -72, -32768, -32768,
+	-72, -72, 177, -32768, -72, -72, -32768, -32768, -32768, -72,
+	-32768, 236, -32768, -72, -72, -32768, -32768, 36, 30, -32768,
+	-32768,
 }
-var yyPgo = [...]int{
 
-	0, 3, 222, 210, 220, 176, 219, 6, 5, 4,
-	218, 213, 170, 0, 16, 17, 1, 211, 2, 194,
-	75, 166,
+var yyPgo = [...]int16{
+	0, 0, 292, 271, 289, 166, 286, 4, 3, 8,
+	283, 6, 276, 275, 274, 170, 373, 11, 7, 14,
+	9, 2, 272, 268, 5, 239, 1, 169,
 }
-var yyR1 = [...]int{
 
+var yyR1 = [...]int8{
 	0, 2, 2, 2, 3, 1, 1, 4, 4, 4,
 	5, 5, 5, 5, 5, 5, 5, 5, 5, 5,
-	5, 5, 5, 5, 5, 5, 11, 11, 10, 6,
-	6, 9, 9, 9, 9, 9, 8, 7, 16, 17,
-	17, 17, 18, 18, 18, 15, 15, 15, 12, 12,
-	14, 14, 14, 14, 13, 13, 13, 13, 13, 13,
-	13, 13, 13, 13, 13, 13, 13, 13, 13, 13,
-	13, 13, 13, 13, 13, 13, 13, 13, 13, 13,
-	13, 13, 13, 13, 13, 13, 13, 13, 13, 13,
-	13, 13, 13, 13, 13, 13, 13, 13, 13, 13,
-	13, 13, 13, 13, 13, 13, 13, 13, 13, 13,
-	13, 13, 13, 13, 13, 13, 13, 13, 13, 13,
-	13, 13, 13, 13, 13, 13, 13, 20, 20, 19,
-	19, 21, 21,
+	5, 5, 5, 5, 5, 5, 5, 5, 5, 5,
+	5, 5, 5, 14, 14, 13, 6, 6, 9, 9,
+	9, 9, 9, 10, 10, 10, 10, 10, 11, 12,
+	12, 12, 12, 12, 12, 8, 8, 7, 21, 22,
+	22, 23, 23, 24, 24, 24, 20, 20, 20, 15,
+	15, 17, 17, 18, 18, 18, 19, 19, 16, 16,
+	16, 16, 16, 16, 16, 16, 16, 16, 16, 16,
+	16, 16, 16, 16, 16, 16, 16, 16, 16, 16,
+	16, 16, 16, 16, 16, 16, 16, 16, 16, 16,
+	16, 16, 16, 16, 16, 16, 16, 16, 16, 16,
+	16, 16, 16, 16, 16, 16, 16, 16, 16, 16,
+	16, 16, 16, 16, 16, 16, 16, 16, 16, 16,
+	16, 16, 16, 16, 16, 16, 16, 16, 16, 16,
+	16, 16, 16, 16, 16, 16, 16, 16, 16, 26,
+	26, 25, 25, 27, 27,
 }
-var yyR2 = [...]int{
 
+var yyR2 = [...]int8{
 	0, 0, 1, 2, 4, 1, 2, 0, 2, 3,
-	3, 3, 3, 1, 1, 2, 2, 1, 8, 9,
-	9, 5, 5, 5, 4, 1, 0, 2, 4, 8,
-	6, 0, 2, 2, 2, 2, 5, 4, 3, 0,
-	1, 4, 0, 1, 4, 1, 4, 4, 1, 3,
-	0, 1, 4, 4, 1, 1, 2, 2, 2, 1,
-	1, 1, 1, 1, 7, 3, 7, 8, 8, 9,
-	5, 6, 5, 6, 3, 3, 3, 3, 3, 3,
+	3, 3, 3, 3, 1, 1, 2, 2, 2, 1,
+	8, 9, 9, 5, 5, 7, 5, 6, 5, 4,
+	7, 8, 1, 0, 2, 4, 8, 6, 0, 2,
+	2, 2, 2, 0, 2, 2, 2, 2, 5, 1,
+	2, 1, 3, 4, 3, 5, 7, 4, 3, 0,
+	1, 1, 4, 0, 1, 4, 1, 4, 4, 1,
+	3, 0, 1, 1, 4, 4, 1, 3, 1, 1,
+	2, 2, 2, 2, 2, 1, 1, 1, 1, 1,
+	7, 3, 7, 8, 8, 9, 12, 12, 5, 6,
+	8, 5, 6, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 3, 2, 2, 3, 3, 3,
-	3, 5, 4, 6, 5, 5, 4, 6, 5, 4,
-	4, 6, 5, 5, 6, 5, 5, 2, 2, 5,
-	4, 6, 5, 4, 6, 3, 2, 0, 1, 1,
-	2, 1, 1,
+	3, 3, 3, 3, 2, 2, 2, 2, 3, 3,
+	3, 3, 5, 4, 6, 5, 5, 4, 6, 5,
+	4, 4, 6, 5, 5, 6, 5, 5, 2, 2,
+	5, 4, 6, 5, 7, 4, 6, 3, 2, 0,
+	1, 1, 2, 1, 1,
 }
-var yyChk = [...]int{
-
-	-1000, -2, -3, 25, -3, 4, -19, -21, 80, 81,
-	-1, -21, -20, -4, -19, -5, -13, -15, 35, 36,
-	10, 11, -6, 14, 54, 26, 42, 4, 5, 64,
-	72, 73, 6, 22, 23, 24, 50, 55, 9, 76,
-	69, 75, 45, 47, 49, 56, 48, -14, 12, -20,
-	-19, -21, 57, 71, 63, 64, 65, 66, 67, 39,
-	40, 41, 16, 17, 61, 18, 62, 19, 29, 30,
-	31, 32, 33, 34, 37, 38, 78, 20, 79, 21,
-	75, 76, 48, 57, 16, -14, -13, -13, 51, 4,
-	-13, -1, -13, 59, 75, 76, -13, -13, -13, -13,
-	75, 4, -20, -20, -13, 4, -13, -12, 46, 75,
-	4, 75, -12, -13, 60, -13, -5, -13, 4, -13,
-	-13, -13, -13, -13, -13, -13, -13, -13, -13, -13,
-	-13, -13, -13, -13, -13, -13, -13, -13, -13, -13,
-	-13, -13, -13, -14, -13, 59, -13, -15, -13, -15,
-	60, 4, 57, 16, 69, 27, 59, -9, -20, -14,
-	-13, 59, 60, -18, 4, 75, -14, -17, -16, 6,
-	74, 75, 75, 71, 75, -13, -13, 75, -20, 69,
-	8, 74, 77, 59, -13, -20, 15, -13, -13, -1,
-	-1, -9, 70, -8, -7, 43, 44, -8, -7, 8,
-	74, 77, 59, -13, -13, 60, 74, 8, -18, 4,
-	60, -20, 60, -20, 59, -14, -14, 4, -13, 74,
-	60, 74, 60, -13, -13, 4, -1, 74, -13, 77,
-	77, -13, 4, -13, 52, 52, 70, 70, 70, -13,
-	59, 74, -13, 77, 77, 60, -20, -20, 74, 74,
-	8, -20, 77, -20, 70, -13, 8, 74, 8, 74,
-	74, -13, -13, 74, -11, 77, 69, -13, -13, 59,
-	-20, 77, -13, 4, -1, -20, -20, 74, 77, -16,
-	70, 74, 74, 74, 74, -10, 13, 70, 53, -1,
-	69, 69, -20, -1, 74, 70, -1, -1, -20, -1,
-	-13, 70, -1, -1, -1, 70, 70, -1, 70, 69,
-	70, 70, 70, -1,
+
+var yyChk = [...]int16{
+	-32768, -2, -3, 25, -3, 4, -25, -27, 84, 85,
+	-1, -27, -26, -4, -25, -5, -16, -20, 35, 36,
+	10, 11, 60, -6, 14, 54, 26, 58, 42, 56,
+	4, 5, 59, 68, 67, 76, 77, 6, 22, 23,
+	24, 50, 55, 9, 80, 73, 79, 37, 38, 45,
+	47, 49, 48, -18, 12, -26, -25, -27, 61, 75,
+	67, 68, 69, 70, 71, 39, 40, 41, 16, 17,
+	65, 18, 66, 19, 29, 30, 31, 32, 33, 34,
+	37, 38, 82, 20, 83, 21, 79, 80, 48, 61,
+	57, 16, -17, -18, -16, 56, -16, -24, 4, 51,
+	4, -16, -1, 4, -16, 63, 52, 4, -15, 79,
+	80, -16, -16, -16, -16, -16, -16, 79, 4, -26,
+	-26, -16, -16, -16, 4, -16, -15, 46, 79, 4,
+	79, -16, 64, -16, -5, -16, 4, -16, -16, -16,
+	-16, -16, -16, -16, -16, -16, -16, -16, -16, -16,
+	-16, -16, -16, -16, -16, -16, -16, -16, -16, -16,
+	-16, -17, -19, -18, -16, 63, -16, -20, -16, -20,
+	-20, 64, 64, 4, 61, 16, 73, 27, 28, 16,
+	63, -9, -26, 4, 4, 75, 79, -17, -19, -16,
+	63, 64, -24, 4, 79, -17, -18, -22, -23, -21,
+	6, 78, 79, 79, 79, -16, -16, -26, 73, 8,
+	78, 64, 81, 63, -16, -26, -26, 15, -16, -16,
+	-1, -1, -1, -16, -9, 74, -8, -7, 43, 44,
+	-8, -7, -16, 73, 4, -16, 8, 78, 81, 63,
+	-16, -16, 78, 8, 4, -24, 4, -26, 64, -26,
+	64, 63, -17, -19, -17, -19, -16, 78, 64, 78,
+	64, -16, 4, -1, 78, -26, -16, 81, 81, -16,
+	4, 4, -16, 52, 52, 74, 74, 28, 74, -1,
+	74, -16, 63, 63, -26, 78, 64, 78, -16, 81,
+	81, 64, -26, 78, 78, 78, 8, 81, -26, 8,
+	74, -26, -16, 8, 78, 8, 78, 78, -16, -16,
+	-14, 81, 73, -16, -16, -1, 74, 63, 52, -26,
+	-10, -26, -24, -19, -17, 81, -16, -1, -26, 4,
+	25, -26, 78, 81, 4, -21, 74, 78, 78, 78,
+	78, -13, 13, 74, 53, -1, 73, 73, 74, -26,
+	-16, -1, 74, -11, -7, 43, -11, -7, -26, 78,
+	78, 74, -1, 79, 79, -1, -26, -26, -1, -16,
+	74, -1, -1, -1, 63, -12, 4, 56, 24, 74,
+	74, -24, -24, 74, -1, 81, 74, 73, 74, 74,
+	-26, 63, 64, 4, 78, 78, 74, -1, -1, -26,
+	4, 56, 24, -26, -26, -1, 4, -1, -1, 74,
+	74,
 }
-var yyDef = [...]int{
-
-	1, -2, 2, 0, 3, 0, -2, 129, 131, 132,
-	4, 129, -2, 127, 128, 8, -2, 0, 13, 14,
-	50, 0, 17, 0, 0, -2, 0, 54, 55, 0,
-	0, 0, 59, 60, 61, 62, 63, 0, 0, 127,
-	127, 0, 0, 0, 0, 0, 0, 0, 0, 6,
-	-2, 130, 0, 0, 0, 0, 0, 0, 0, 0,
+
+var yyDef = [...]int16{
+	1, -2, 2, 0, 3, 0, -2, 161, 163, 164,
+	4, 161, 5, 159, 160, 8, -2, 0, 14, 15,
+	71, 0, 63, 19, 0, 0, -2, 0, 0, 0,
+	78, 79, 0, 0, 0, 0, 0, 85, 86, 87,
+	88, 89, 0, 0, 159, 159, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 6, 160, 162, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 95, 96, 0, 0, 0, 0,
-	50, 0, 0, 50, 50, 15, 51, 16, 0, 0,
-	0, 0, 0, 31, 50, 0, 56, 57, 58, 0,
-	42, 0, 50, 39, 0, 54, 0, 117, 118, 0,
-	48, 0, 0, 126, 127, 0, 9, 10, 65, 75,
-	76, 77, 78, 79, 80, 81, 82, -2, -2, 85,
-	86, 87, 88, 89, 90, 91, 92, 93, 94, 97,
-	98, 99, 100, 0, 0, 0, 125, 11, -2, 12,
-	127, 0, 0, 0, -2, -2, 31, 0, 0, 0,
-	0, 0, 0, 0, 43, 42, 127, 127, 40, 0,
-	74, 50, 50, 0, 0, 0, 0, 0, 0, -2,
-	0, 106, 110, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 24, 34, 35, 0, 0, 32, 33, 0,
-	102, 109, 0, 0, 0, 127, 127, 0, 0, 43,
-	127, 0, 127, 0, 0, 0, 0, 49, 0, 123,
-	0, 120, 0, 0, -2, -2, 26, 105, 0, 115,
-	116, 52, -2, 0, 0, 0, 21, 22, 23, 0,
-	127, 101, 0, 112, 113, 0, 0, -2, 127, 127,
-	0, 0, 70, 0, 72, 38, 0, -2, 0, -2,
-	119, 0, 0, 122, 0, 114, -2, 0, 0, 127,
-	-2, 111, 0, 44, 0, -2, -2, 127, 71, 41,
-	73, -2, -2, 124, 121, 27, -2, 30, 0, 0,
-	-2, -2, -2, 37, 64, 66, 0, 0, -2, 0,
-	0, 18, 0, 0, 36, 67, 68, 0, 29, -2,
-	19, 20, 69, 28,
+	124, 125, 0, 0, 0, 0, 71, 0, 0, 0,
+	0, 0, 16, 72, 73, 0, 17, 18, 64, 0,
+	0, 0, 0, 0, 0, 38, 0, 69, 0, 71,
+	0, 80, 81, 82, 83, 84, 0, 63, 0, 71,
+	59, 0, 126, 127, 78, 0, 148, 149, 0, 69,
+	0, 158, 159, 0, 9, 10, 91, 104, 105, 106,
+	107, 108, 109, 110, 111, -2, -2, 114, 115, 116,
+	117, 118, 119, 120, 121, 122, 123, 128, 129, 130,
+	131, 76, 0, 72, 0, 0, 157, 11, -2, 12,
+	13, 159, 159, 0, 0, 0, -2, -2, -2, 0,
+	38, 0, 0, 0, 0, 0, 0, 76, 0, 0,
+	0, 0, 0, 64, 63, 159, 72, 159, 60, 61,
+	0, 103, 71, 71, 0, 0, 0, 0, -2, 0,
+	137, 159, 141, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, -2, 0, 29, 41, 42, 0, 0,
+	39, 40, 0, 159, 70, 0, 0, 133, 140, 0,
+	0, 0, 159, 0, 0, 0, 64, 0, 159, 0,
+	159, 0, 76, 0, 76, 0, 0, 155, 0, 151,
+	0, -2, -2, 33, 136, 77, 0, 146, 147, 74,
+	-2, 65, 0, 0, 0, 23, 24, -2, 26, 0,
+	28, 0, 159, 43, 63, 153, 71, 132, 0, 143,
+	144, 0, -2, 159, 0, 159, 0, 98, 0, 0,
+	101, 0, 58, 0, -2, 0, -2, 150, 0, 0,
+	0, 145, -2, 0, 0, 0, 27, 159, 0, -2,
+	0, 0, 159, 0, 76, 142, 0, 0, -2, 0,
+	0, -2, 159, 99, 159, 62, 102, -2, -2, 156,
+	152, 34, -2, 37, 0, 0, -2, -2, 25, -2,
+	0, 57, 30, 46, 47, 0, 44, 45, 0, 154,
+	90, 92, 0, 63, 63, 0, -2, 0, 0, 0,
+	20, 0, 0, 55, 159, 0, 49, 0, 51, 31,
+	93, 0, 0, 94, 0, 100, 36, -2, 21, 22,
+	-2, 159, 0, 50, 159, 159, 95, 35, 56, -2,
+	52, 0, 54, -2, -2, 48, 53, 0, 0, 96,
+	97,
 }
-var yyTok1 = [...]int{
 
+var yyTok1 = [...]int8{
 	1, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	81, 3, 3, 3, 3, 3, 3, 3, 3, 3,
+	85, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 72, 3, 3, 3, 67, 79, 3,
-	75, 74, 65, 63, 60, 64, 71, 66, 3, 3,
-	3, 3, 3, 3, 3, 3, 3, 3, 59, 80,
-	62, 57, 61, 58, 3, 3, 3, 3, 3, 3,
+	3, 3, 3, 76, 3, 3, 3, 71, 83, 3,
+	79, 78, 69, 67, 64, 68, 75, 70, 3, 3,
+	3, 3, 3, 3, 3, 3, 3, 3, 63, 84,
+	66, 61, 65, 62, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 76, 3, 77, 73, 3, 3, 3, 3, 3,
+	3, 80, 3, 81, 77, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 69, 78, 70,
+	3, 3, 3, 73, 82, 74,
 }
-var yyTok2 = [...]int{
 
+var yyTok2 = [...]int8{
 	2, 3, 4, 5, 6, 7, 8, 9, 10, 11,
 	12, 13, 14, 15, 16, 17, 18, 19, 20, 21,
 	22, 23, 24, 25, 26, 27, 28, 29, 30, 31,
 	32, 33, 34, 35, 36, 37, 38, 39, 40, 41,
 	42, 43, 44, 45, 46, 47, 48, 49, 50, 51,
-	52, 53, 54, 55, 56, 68,
+	52, 53, 54, 55, 56, 57, 58, 59, 60, 72,
 }
-var yyTok3 = [...]int{
+
+var yyTok3 = [...]int8{
 	0,
 }
 
@@ -910,7 +1072,7 @@ func yyNewParser() yyParser {
 	return &yyParserImpl{}
 }
 
-const yyFlag = -1000
+const yyFlag = -32768
 
 func yyTokname(c int) string {
 	if c >= 1 && c-1 < len(yyToknames) {
@@ -949,9 +1111,9 @@ func yyErrorMessage(state, lookAhead int) string {
 	expected := make([]int, 0, 4)
 
 	// Look for shiftable tokens.
-	base := yyPact[state]
+	base := int(yyPact[state])
 	for tok := TOKSTART; tok-1 < len(yyToknames); tok++ {
-		if n := base + tok; n >= 0 && n < yyLast && yyChk[yyAct[n]] == tok {
+		if n := base + tok; n >= 0 && n < yyLast && int(yyChk[int(yyAct[n])]) == tok {
 			if len(expected) == cap(expected) {
 				return res
 			}
@@ -961,13 +1123,13 @@ func yyErrorMessage(state, lookAhead int) string {
 
 	if yyDef[state] == -2 {
 		i := 0
-		for yyExca[i] != -1 || yyExca[i+1] != state {
+		for yyExca[i] != -1 || int(yyExca[i+1]) != state {
 			i += 2
 		}
 
 		// Look for tokens that we accept or reduce.
 		for i += 2; yyExca[i] >= 0; i += 2 {
-			tok := yyExca[i]
+			tok := int(yyExca[i])
 			if tok < TOKSTART || yyExca[i+1] == 0 {
 				continue
 			}
@@ -998,30 +1160,30 @@ func yylex1(lex yyLexer, lval *yySymType) (char, token int) {
 	token = 0
 	char = lex.Lex(lval)
 	if char <= 0 {
-		token = yyTok1[0]
+		token = int(yyTok1[0])
 		goto out
 	}
 	if char < len(yyTok1) {
-		token = yyTok1[char]
+		token = int(yyTok1[char])
 		goto out
 	}
 	if char >= yyPrivate {
 		if char < yyPrivate+len(yyTok2) {
-			token = yyTok2[char-yyPrivate]
+			token = int(yyTok2[char-yyPrivate])
 			goto out
 		}
 	}
 	for i := 0; i < len(yyTok3); i += 2 {
-		token = yyTok3[i+0]
+		token = int(yyTok3[i+0])
 		if token == char {
-			token = yyTok3[i+1]
+			token = int(yyTok3[i+1])
 			goto out
 		}
 	}
 
 out:
 	if token == 0 {
-		token = yyTok2[1] /* unknown char */
+		token = int(yyTok2[1]) /* unknown char */
 	}
 	if yyDebug >= 3 {
 		__yyfmt__.Printf("lex %s(%d)\n", yyTokname(token), uint(char))
@@ -1076,7 +1238,7 @@ yystack:
 	yyS[yyp].yys = yystate
 
 yynewstate:
-	yyn = yyPact[yystate]
+	yyn = int(yyPact[yystate])
 	if yyn <= yyFlag {
 		goto yydefault /* simple state */
 	}
@@ -1087,8 +1249,8 @@ yynewstate:
 	if yyn < 0 || yyn >= yyLast {
 		goto yydefault
 	}
-	yyn = yyAct[yyn]
-	if yyChk[yyn] == yytoken { /* valid shift */
+	yyn = int(yyAct[yyn])
+	if int(yyChk[yyn]) == yytoken { /* valid shift */
 		yyrcvr.char = -1
 		yytoken = -1
 		yyVAL = yyrcvr.lval
@@ -1101,7 +1263,7 @@ yynewstate:
 
 yydefault:
 	/* default state action */
-	yyn = yyDef[yystate]
+	yyn = int(yyDef[yystate])
 	if yyn == -2 {
 		if yyrcvr.char < 0 {
 			yyrcvr.char, yytoken = yylex1(yylex, &yyrcvr.lval)
@@ -1110,18 +1272,18 @@ yydefault:
 		/* look through exception table */
 		xi := 0
 		for {
-			if yyExca[xi+0] == -1 && yyExca[xi+1] == yystate {
+			if yyExca[xi+0] == -1 && int(yyExca[xi+1]) == yystate {
 				break
 			}
 			xi += 2
 		}
 		for xi += 2; ; xi += 2 {
-			yyn = yyExca[xi+0]
+			yyn = int(yyExca[xi+0])
 			if yyn < 0 || yyn == yytoken {
 				break
 			}
 		}
-		yyn = yyExca[xi+1]
+		yyn = int(yyExca[xi+1])
 		if yyn < 0 {
 			goto ret0
 		}
@@ -1143,10 +1305,10 @@ yydefault:
 
 			/* find a state where "error" is a legal shift action */
 			for yyp >= 0 {
-				yyn = yyPact[yyS[yyp].yys] + yyErrCode
+				yyn = int(yyPact[yyS[yyp].yys]) + yyErrCode
 				if yyn >= 0 && yyn < yyLast {
-					yystate = yyAct[yyn] /* simulate a shift of "error" */
-					if yyChk[yystate] == yyErrCode {
+					yystate = int(yyAct[yyn]) /* simulate a shift of "error" */
+					if int(yyChk[yystate]) == yyErrCode {
 						goto yystack
 					}
 				}
@@ -1182,7 +1344,7 @@ yydefault:
 	yypt := yyp
 	_ = yypt // guard against "declared and not used"
 
-	yyp -= yyR2[yyn]
+	yyp -= int(yyR2[yyn])
 	// yyp is now the index of $0. Perform the default action. Iff the
 	// reduced production is ε, $1 is possibly out of range.
 	if yyp+1 >= len(yyS) {
@@ -1193,16 +1355,16 @@ yydefault:
 	yyVAL = yyS[yyp+1]
 
 	/* consult goto table to find next state */
-	yyn = yyR1[yyn]
-	yyg := yyPgo[yyn]
+	yyn = int(yyR1[yyn])
+	yyg := int(yyPgo[yyn])
 	yyj := yyg + yyS[yyp].yys + 1
 
 	if yyj >= yyLast {
-		yystate = yyAct[yyg]
+		yystate = int(yyAct[yyg])
 	} else {
-		yystate = yyAct[yyj]
-		if yyChk[yystate] != -yyn {
-			yystate = yyAct[yyg]
+		yystate = int(yyAct[yyj])
+		if int(yyChk[yystate]) != -yyn {
+			yystate = int(yyAct[yyg])
 		}
 	}
 	// dummy call; replaced with literal code
@@ -1210,7 +1372,7 @@ yydefault:
 
 	case 1:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line parser.y:72
+//line parser.y:80
 		{
 			yyVAL.modules = nil
 			if l, ok := yylex.(*Lexer); ok {
@@ -1219,7 +1381,7 @@ yydefault:
 		}
 	case 2:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:79
+//line parser.y:87
 		{
 			yyVAL.modules = ast.Stmts{yyDollar[1].module}
 			if l, ok := yylex.(*Lexer); ok {
@@ -1228,7 +1390,7 @@ yydefault:
 		}
 	case 3:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:86
+//line parser.y:94
 		{
 			if yyDollar[2].module != nil {
 				yyVAL.modules = append(yyDollar[1].modules, yyDollar[2].module)
@@ -1239,38 +1401,39 @@ yydefault:
 		}
 	case 4:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:97
+//line parser.y:105
 		{
 			yyVAL.module = &ast.ModuleStmt{Name: names.UniqueNames.Set(yyDollar[2].tok.Lit), Stmts: yyDollar[4].compstmt}
 			yyVAL.module.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.module.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
 	case 5:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:103
+//line parser.y:112
 		{
 			yyVAL.compstmt = nil
 		}
 	case 6:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:107
+//line parser.y:116
 		{
 			yyVAL.compstmt = yyDollar[1].stmts
 		}
 	case 7:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line parser.y:112
+//line parser.y:121
 		{
 			yyVAL.stmts = nil
 		}
 	case 8:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:116
+//line parser.y:125
 		{
 			yyVAL.stmts = ast.Stmts{yyDollar[2].stmt}
 		}
 	case 9:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:120
+//line parser.y:129
 		{
 			if yyDollar[3].stmt != nil {
 				yyVAL.stmts = append(yyDollar[1].stmts, yyDollar[3].stmt)
@@ -1278,172 +1441,260 @@ yydefault:
 		}
 	case 10:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:128
+//line parser.y:137
 		{
 			yyVAL.stmt = &ast.LetsStmt{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "=", Rhss: []ast.Expr{yyDollar[3].expr}}
 		}
 	case 11:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:132
+//line parser.y:141
 		{
 			yyVAL.stmt = &ast.LetsStmt{Lhss: yyDollar[1].expr_many, Operator: "=", Rhss: yyDollar[3].expr_many}
 		}
 	case 12:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:136
+//line parser.y:145
 		{
-			yyVAL.stmt = &ast.ExprStmt{Expr: &ast.BinOpExpr{Lhss: yyDollar[1].expr_many, Operator: "==", Rhss: yyDollar[3].expr_many}}
+			yyVAL.stmt = &ast.LetsStmt{Lhss: yyDollar[1].expr_many, Operator: ":=", Rhss: yyDollar[3].expr_many, Declare: true}
+			yyVAL.stmt.SetPosition(yyDollar[1].expr_many[0].Position())
+			yyVAL.stmt.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
 	case 13:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:151
+		{
+			yyVAL.stmt = &ast.ExprStmt{Expr: &ast.BinOpExpr{Lhss: yyDollar[1].expr_many, Operator: "==", Rhss: yyDollar[3].expr_many}}
+		}
+	case 14:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:140
+//line parser.y:155
 		{
 			yyVAL.stmt = &ast.BreakStmt{}
 			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.stmt.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 14:
+	case 15:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:145
+//line parser.y:161
 		{
 			yyVAL.stmt = &ast.ContinueStmt{}
 			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.stmt.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 15:
+	case 16:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:150
+//line parser.y:167
 		{
 			yyVAL.stmt = &ast.ReturnStmt{Exprs: yyDollar[2].exprs}
 			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.stmt.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 16:
+	case 17:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:155
+//line parser.y:173
 		{
 			yyVAL.stmt = &ast.ThrowStmt{Expr: yyDollar[2].expr}
 			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.stmt.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 17:
+	case 18:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:179
+		{
+			if len(yyDollar[2].expr_idents) == 0 {
+				yylex.Error("ожидается имя переменной")
+			}
+			yyVAL.stmt = &ast.GlobalStmt{Names: yyDollar[2].expr_idents}
+			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.stmt.SetEndPosition(endPos(yylex, yyrcvr.char))
+		}
+	case 19:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:160
+//line parser.y:188
 		{
 			yyVAL.stmt = yyDollar[1].stmt_if
 			yyVAL.stmt.SetPosition(yyDollar[1].stmt_if.Position())
+			yyVAL.stmt.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 18:
+	case 20:
 		yyDollar = yyS[yypt-8 : yypt+1]
-//line parser.y:165
+//line parser.y:194
 		{
 			yyVAL.stmt = &ast.ForStmt{Var: names.UniqueNames.Set(yyDollar[3].tok.Lit), Value: yyDollar[5].expr, Stmts: yyDollar[7].compstmt}
 			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.stmt.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 19:
+	case 21:
 		yyDollar = yyS[yypt-9 : yypt+1]
-//line parser.y:170
+//line parser.y:200
 		{
 			yyVAL.stmt = &ast.NumForStmt{Name: names.UniqueNames.Set(yyDollar[2].tok.Lit), Expr1: yyDollar[4].expr, Expr2: yyDollar[6].expr, Stmts: yyDollar[8].compstmt}
 			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.stmt.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 20:
+	case 22:
 		yyDollar = yyS[yypt-9 : yypt+1]
-//line parser.y:175
+//line parser.y:206
 		{
 			yyVAL.stmt = &ast.NumForStmt{Name: names.UniqueNames.Set(yyDollar[2].tok.Lit), Expr1: yyDollar[4].expr, Expr2: yyDollar[6].expr, Stmts: yyDollar[8].compstmt}
 			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.stmt.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 21:
+	case 23:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:180
+//line parser.y:212
 		{
 			yyVAL.stmt = &ast.LoopStmt{Expr: yyDollar[2].expr, Stmts: yyDollar[4].compstmt}
 			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.stmt.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 22:
+	case 24:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:185
+//line parser.y:218
 		{
 			yyVAL.stmt = &ast.TryStmt{Try: yyDollar[2].compstmt, Catch: yyDollar[4].compstmt}
 			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.stmt.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 23:
+	case 25:
+		yyDollar = yyS[yypt-7 : yypt+1]
+//line parser.y:224
+		{
+			yyVAL.stmt = &ast.TryStmt{Try: yyDollar[2].compstmt, Catch: yyDollar[4].compstmt, Finally: yyDollar[6].compstmt}
+			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.stmt.SetEndPosition(endPos(yylex, yyrcvr.char))
+		}
+	case 26:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:190
+//line parser.y:230
+		{
+			yyVAL.stmt = &ast.TryStmt{Try: yyDollar[2].compstmt, Finally: yyDollar[4].compstmt, NoCatch: true}
+			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.stmt.SetEndPosition(endPos(yylex, yyrcvr.char))
+		}
+	case 27:
+		yyDollar = yyS[yypt-6 : yypt+1]
+//line parser.y:236
+		{
+			yyVAL.stmt = &ast.WithStmt{Var: names.UniqueNames.Set(yyDollar[2].tok.Lit), Expr: yyDollar[4].expr, Stmts: yyDollar[5].compstmt}
+			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.stmt.SetEndPosition(endPos(yylex, yyrcvr.char))
+		}
+	case 28:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line parser.y:242
 		{
 			yyVAL.stmt = &ast.SwitchStmt{Expr: yyDollar[2].expr, Cases: yyDollar[4].stmt_cases}
 			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.stmt.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 24:
+	case 29:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:195
+//line parser.y:248
 		{
 			yyVAL.stmt = &ast.SelectStmt{Cases: yyDollar[3].stmt_cases}
 			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.stmt.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 25:
+	case 30:
+		yyDollar = yyS[yypt-7 : yypt+1]
+//line parser.y:254
+		{
+			if names.FastToLower(yyDollar[3].tok.Lit) != "типу" {
+				yylex.Error("ожидается выбор по типу")
+			}
+			ts := &ast.TypeSwitchStmt{Expr: yyDollar[4].expr, Cases: yyDollar[6].stmt_cases}
+			// выбор по типу ч = значение: - значение присваивается переменной ч
+			if be, ok := yyDollar[4].expr.(*ast.BinOpExpr); ok && be.Operator == "==" && len(be.Lhss) == 1 && len(be.Rhss) == 1 {
+				if id, ok := be.Lhss[0].(*ast.IdentExpr); ok {
+					ts.Var = id.Id
+					ts.Expr = be.Rhss[0]
+				}
+			}
+			yyVAL.stmt = ts
+			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.stmt.SetEndPosition(endPos(yylex, yyrcvr.char))
+		}
+	case 31:
+		yyDollar = yyS[yypt-8 : yypt+1]
+//line parser.y:271
+		{
+			if yyDollar[2].tok.Lit != "структура" {
+				yylex.Error("ожидается объявление структуры")
+			}
+			yyVAL.stmt = &ast.StructStmt{Name: names.UniqueNames.Set(yyDollar[3].tok.Lit), Fields: yyDollar[6].expr_idents}
+			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.stmt.SetEndPosition(endPos(yylex, yyrcvr.char))
+		}
+	case 32:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:200
+//line parser.y:280
 		{
 			yyVAL.stmt = &ast.ExprStmt{Expr: yyDollar[1].expr}
 			yyVAL.stmt.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.stmt.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 26:
+	case 33:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line parser.y:206
+//line parser.y:287
 		{
 			yyVAL.stmt_elsifs = ast.Stmts{}
 		}
-	case 27:
+	case 34:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:210
+//line parser.y:291
 		{
 			yyVAL.stmt_elsifs = append(yyDollar[1].stmt_elsifs, yyDollar[2].stmt_elsif)
 		}
-	case 28:
+	case 35:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:216
+//line parser.y:297
 		{
 			yyVAL.stmt_elsif = &ast.IfStmt{If: yyDollar[2].expr, Then: yyDollar[4].compstmt}
 		}
-	case 29:
+	case 36:
 		yyDollar = yyS[yypt-8 : yypt+1]
-//line parser.y:222
+//line parser.y:303
 		{
 			yyVAL.stmt_if = &ast.IfStmt{If: yyDollar[2].expr, Then: yyDollar[4].compstmt, ElseIf: yyDollar[5].stmt_elsifs, Else: yyDollar[7].compstmt}
 			yyVAL.stmt_if.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.stmt_if.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 30:
+	case 37:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:227
+//line parser.y:309
 		{
 			yyVAL.stmt_if = &ast.IfStmt{If: yyDollar[2].expr, Then: yyDollar[4].compstmt, ElseIf: yyDollar[5].stmt_elsifs, Else: nil}
 			yyVAL.stmt_if.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.stmt_if.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 31:
+	case 38:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line parser.y:233
+//line parser.y:316
 		{
 			yyVAL.stmt_cases = ast.Stmts{}
 		}
-	case 32:
+	case 39:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:237
+//line parser.y:320
 		{
 			yyVAL.stmt_cases = ast.Stmts{yyDollar[2].stmt_case}
 		}
-	case 33:
+	case 40:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:241
+//line parser.y:324
 		{
 			yyVAL.stmt_cases = ast.Stmts{yyDollar[2].stmt_default}
 		}
-	case 34:
+	case 41:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:245
+//line parser.y:328
 		{
 			yyVAL.stmt_cases = append(yyDollar[1].stmt_cases, yyDollar[2].stmt_case)
 		}
-	case 35:
+	case 42:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:249
+//line parser.y:332
 		{
 			for _, stmt := range yyDollar[1].stmt_cases {
 				if _, ok := stmt.(*ast.DefaultStmt); ok {
@@ -1452,247 +1703,421 @@ yydefault:
 			}
 			yyVAL.stmt_cases = append(yyDollar[1].stmt_cases, yyDollar[2].stmt_default)
 		}
-	case 36:
+	case 43:
+		yyDollar = yyS[yypt-0 : yypt+1]
+//line parser.y:342
+		{
+			yyVAL.stmt_cases = ast.Stmts{}
+		}
+	case 44:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:346
+		{
+			yyVAL.stmt_cases = ast.Stmts{yyDollar[2].stmt_case}
+		}
+	case 45:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:350
+		{
+			yyVAL.stmt_cases = ast.Stmts{yyDollar[2].stmt_default}
+		}
+	case 46:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:354
+		{
+			yyVAL.stmt_cases = append(yyDollar[1].stmt_cases, yyDollar[2].stmt_case)
+		}
+	case 47:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:358
+		{
+			for _, stmt := range yyDollar[1].stmt_cases {
+				if _, ok := stmt.(*ast.DefaultStmt); ok {
+					yylex.Error("multiple default statement")
+				}
+			}
+			yyVAL.stmt_cases = append(yyDollar[1].stmt_cases, yyDollar[2].stmt_default)
+		}
+	case 48:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:260
+//line parser.y:369
+		{
+			yyVAL.stmt_case = &ast.TypeCaseStmt{Types: yyDollar[2].expr_idents, Stmts: yyDollar[5].compstmt}
+			yyVAL.stmt_case.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.stmt_case.SetEndPosition(endPos(yylex, yyrcvr.char))
+		}
+	case 49:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:377
+		{
+			yyVAL.expr_idents = []int{names.UniqueNames.Set(yyDollar[1].tok.Lit)}
+		}
+	case 50:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:381
+		{
+			yyVAL.expr_idents = []int{names.UniqueNames.Set(yyDollar[2].tok.Lit)}
+		}
+	case 51:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:385
+		{
+			yyVAL.expr_idents = []int{names.UniqueNames.Set("неопределено")}
+		}
+	case 52:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:389
+		{
+			yyVAL.expr_idents = append(yyDollar[1].expr_idents, names.UniqueNames.Set(yyDollar[3].tok.Lit))
+		}
+	case 53:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line parser.y:393
+		{
+			yyVAL.expr_idents = append(yyDollar[1].expr_idents, names.UniqueNames.Set(yyDollar[4].tok.Lit))
+		}
+	case 54:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:397
+		{
+			yyVAL.expr_idents = append(yyDollar[1].expr_idents, names.UniqueNames.Set("неопределено"))
+		}
+	case 55:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line parser.y:403
 		{
 			yyVAL.stmt_case = &ast.CaseStmt{Expr: yyDollar[2].expr, Stmts: yyDollar[5].compstmt}
+			yyVAL.stmt_case.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.stmt_case.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 37:
+	case 56:
+		yyDollar = yyS[yypt-7 : yypt+1]
+//line parser.y:409
+		{
+			yyVAL.stmt_case = &ast.CaseStmt{Expr: yyDollar[2].expr, To: yyDollar[4].expr, Stmts: yyDollar[7].compstmt}
+			yyVAL.stmt_case.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.stmt_case.SetEndPosition(endPos(yylex, yyrcvr.char))
+		}
+	case 57:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:266
+//line parser.y:417
 		{
 			yyVAL.stmt_default = &ast.DefaultStmt{Stmts: yyDollar[4].compstmt}
 		}
-	case 38:
+	case 58:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:272
+//line parser.y:423
 		{
 			yyVAL.expr_pair = &ast.PairExpr{Key: yyDollar[1].tok.Lit, Value: yyDollar[3].expr}
 		}
-	case 39:
+	case 59:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line parser.y:277
+//line parser.y:428
 		{
 			yyVAL.expr_pairs = []ast.Expr{}
 		}
-	case 40:
+	case 60:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:432
+		{
+			yyVAL.expr_pairs = yyDollar[1].expr_pairs
+		}
+	case 61:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:281
+//line parser.y:438
 		{
 			yyVAL.expr_pairs = []ast.Expr{yyDollar[1].expr_pair}
 		}
-	case 41:
+	case 62:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:285
+//line parser.y:442
 		{
 			yyVAL.expr_pairs = append(yyDollar[1].expr_pairs, yyDollar[4].expr_pair)
 		}
-	case 42:
+	case 63:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line parser.y:290
+//line parser.y:447
 		{
 			yyVAL.expr_idents = []int{}
 		}
-	case 43:
+	case 64:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:294
+//line parser.y:451
 		{
 			yyVAL.expr_idents = []int{names.UniqueNames.Set(yyDollar[1].tok.Lit)}
 		}
-	case 44:
+	case 65:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:298
+//line parser.y:455
 		{
 			yyVAL.expr_idents = append(yyDollar[1].expr_idents, names.UniqueNames.Set(yyDollar[4].tok.Lit))
 		}
-	case 45:
+	case 66:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:304
+//line parser.y:461
 		{
 			yyVAL.expr_many = []ast.Expr{yyDollar[1].expr}
 		}
-	case 46:
+	case 67:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:308
+//line parser.y:465
 		{
 			yyVAL.expr_many = append(yyDollar[1].exprs, yyDollar[4].expr)
 		}
-	case 47:
+	case 68:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:312
+//line parser.y:469
 		{
 			yyVAL.expr_many = append(yyDollar[1].exprs, &ast.IdentExpr{Lit: yyDollar[4].tok.Lit, Id: names.UniqueNames.Set(yyDollar[4].tok.Lit)})
 		}
-	case 48:
+	case 69:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:317
+//line parser.y:474
 		{
 			yyVAL.typ = ast.Type{Name: names.UniqueNames.Set(yyDollar[1].tok.Lit)}
 		}
-	case 49:
+	case 70:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:321
+//line parser.y:478
 		{
 			yyVAL.typ = ast.Type{Name: names.UniqueNames.Set(names.UniqueNames.Get(yyDollar[1].typ.Name) + "." + yyDollar[3].tok.Lit)}
 		}
-	case 50:
+	case 71:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line parser.y:326
+//line parser.y:483
 		{
 			yyVAL.exprs = nil
 		}
-	case 51:
+	case 72:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:487
+		{
+			yyVAL.exprs = yyDollar[1].exprs
+		}
+	case 73:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:330
+//line parser.y:494
 		{
 			yyVAL.exprs = []ast.Expr{yyDollar[1].expr}
 		}
-	case 52:
+	case 74:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:334
+//line parser.y:498
 		{
 			yyVAL.exprs = append(yyDollar[1].exprs, yyDollar[4].expr)
 		}
-	case 53:
+	case 75:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:338
+//line parser.y:502
 		{
 			yyVAL.exprs = append(yyDollar[1].exprs, &ast.IdentExpr{Lit: yyDollar[4].tok.Lit, Id: names.UniqueNames.Set(yyDollar[4].tok.Lit)})
 		}
-	case 54:
+	case 76:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:344
+//line parser.y:509
+		{
+			yyVAL.exprs = yyDollar[1].exprs
+		}
+	case 77:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:513
+		{
+			yyVAL.exprs = yyDollar[1].exprs
+		}
+	case 78:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:519
 		{
 			yyVAL.expr = &ast.IdentExpr{Lit: yyDollar[1].tok.Lit, Id: names.UniqueNames.Set(yyDollar[1].tok.Lit)}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 55:
+	case 79:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:349
+//line parser.y:525
 		{
 			yyVAL.expr = &ast.NumberExpr{Lit: yyDollar[1].tok.Lit}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 56:
+	case 80:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:354
+//line parser.y:531
+		{
+			yyVAL.expr = &ast.TryExpr{Expr: yyDollar[2].expr}
+			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
+		}
+	case 81:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:537
 		{
 			yyVAL.expr = &ast.UnaryExpr{Operator: "-", Expr: yyDollar[2].expr}
 			yyVAL.expr.SetPosition(yyDollar[2].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 57:
+	case 82:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:543
+		{
+			yyVAL.expr = &ast.UnaryExpr{Operator: "+", Expr: yyDollar[2].expr}
+			yyVAL.expr.SetPosition(yyDollar[2].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
+		}
+	case 83:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:359
+//line parser.y:549
 		{
 			yyVAL.expr = &ast.UnaryExpr{Operator: "!", Expr: yyDollar[2].expr}
 			yyVAL.expr.SetPosition(yyDollar[2].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 58:
+	case 84:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:364
+//line parser.y:555
 		{
 			yyVAL.expr = &ast.UnaryExpr{Operator: "^", Expr: yyDollar[2].expr}
 			yyVAL.expr.SetPosition(yyDollar[2].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 59:
+	case 85:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:369
+//line parser.y:561
 		{
 			yyVAL.expr = &ast.StringExpr{Lit: yyDollar[1].tok.Lit}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 60:
+	case 86:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:374
+//line parser.y:567
 		{
 			yyVAL.expr = &ast.ConstExpr{Value: "истина"}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 61:
+	case 87:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:379
+//line parser.y:573
 		{
 			yyVAL.expr = &ast.ConstExpr{Value: "ложь"}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 62:
+	case 88:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:384
+//line parser.y:579
 		{
 			yyVAL.expr = &ast.ConstExpr{Value: "неопределено"}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 63:
+	case 89:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:389
+//line parser.y:585
 		{
 			yyVAL.expr = &ast.ConstExpr{Value: "null"}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 64:
+	case 90:
 		yyDollar = yyS[yypt-7 : yypt+1]
-//line parser.y:394
+//line parser.y:591
 		{
 			yyVAL.expr = &ast.TernaryOpExpr{Expr: yyDollar[2].expr, Lhs: yyDollar[4].expr, Rhs: yyDollar[6].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 65:
+	case 91:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:399
+//line parser.y:597
 		{
 			yyVAL.expr = &ast.MemberExpr{Expr: yyDollar[1].expr, Name: names.UniqueNames.Set(yyDollar[3].tok.Lit)}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 66:
+	case 92:
 		yyDollar = yyS[yypt-7 : yypt+1]
-//line parser.y:404
+//line parser.y:603
 		{
 			yyVAL.expr = &ast.FuncExpr{Name: names.UniqueNames.Set("<анонимная функция>"), Args: yyDollar[3].expr_idents, Stmts: yyDollar[6].compstmt}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 67:
+	case 93:
 		yyDollar = yyS[yypt-8 : yypt+1]
-//line parser.y:409
+//line parser.y:609
 		{
 			yyVAL.expr = &ast.FuncExpr{Name: names.UniqueNames.Set("<анонимная функция>"), Args: []int{names.UniqueNames.Set(yyDollar[3].tok.Lit)}, Stmts: yyDollar[7].compstmt, VarArg: true}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 68:
+	case 94:
 		yyDollar = yyS[yypt-8 : yypt+1]
-//line parser.y:414
+//line parser.y:615
 		{
 			yyVAL.expr = &ast.FuncExpr{Name: names.UniqueNames.Set(yyDollar[2].tok.Lit), Args: yyDollar[4].expr_idents, Stmts: yyDollar[7].compstmt}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 69:
+	case 95:
 		yyDollar = yyS[yypt-9 : yypt+1]
-//line parser.y:419
+//line parser.y:621
 		{
 			yyVAL.expr = &ast.FuncExpr{Name: names.UniqueNames.Set(yyDollar[2].tok.Lit), Args: []int{names.UniqueNames.Set(yyDollar[4].tok.Lit)}, Stmts: yyDollar[8].compstmt, VarArg: true}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 70:
+	case 96:
+		yyDollar = yyS[yypt-12 : yypt+1]
+//line parser.y:627
+		{
+			yyVAL.expr = &ast.FuncExpr{Name: names.UniqueNames.Set(yyDollar[6].tok.Lit), Args: yyDollar[8].expr_idents, Stmts: yyDollar[11].compstmt, Receiver: names.UniqueNames.Set(yyDollar[3].tok.Lit), RecvType: names.UniqueNames.Set(yyDollar[4].tok.Lit)}
+			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
+		}
+	case 97:
+		yyDollar = yyS[yypt-12 : yypt+1]
+//line parser.y:633
+		{
+			// имя метода может совпадать с ключевым словом "модуль"
+			yyVAL.expr = &ast.FuncExpr{Name: names.UniqueNames.Set(yyDollar[6].tok.Lit), Args: yyDollar[8].expr_idents, Stmts: yyDollar[11].compstmt, Receiver: names.UniqueNames.Set(yyDollar[3].tok.Lit), RecvType: names.UniqueNames.Set(yyDollar[4].tok.Lit)}
+			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
+		}
+	case 98:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:424
+//line parser.y:640
 		{
 			yyVAL.expr = &ast.ArrayExpr{Exprs: yyDollar[3].exprs}
 			if l, ok := yylex.(*Lexer); ok {
 				yyVAL.expr.SetPosition(l.pos)
 			}
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 71:
+	case 99:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:429
+//line parser.y:646
 		{
 			yyVAL.expr = &ast.ArrayExpr{Exprs: yyDollar[3].exprs}
 			if l, ok := yylex.(*Lexer); ok {
 				yyVAL.expr.SetPosition(l.pos)
 			}
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 72:
+	case 100:
+		yyDollar = yyS[yypt-8 : yypt+1]
+//line parser.y:652
+		{
+			yyVAL.expr = &ast.ArrayExpr{Exprs: yyDollar[3].exprs, Rest: &ast.IdentExpr{Lit: yyDollar[6].tok.Lit, Id: names.UniqueNames.Set(yyDollar[6].tok.Lit)}}
+			if l, ok := yylex.(*Lexer); ok {
+				yyVAL.expr.SetPosition(l.pos)
+			}
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
+		}
+	case 101:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:434
+//line parser.y:658
 		{
 			mapExpr := make(map[string]ast.Expr)
 			for _, v := range yyDollar[3].expr_pairs {
@@ -1702,10 +2127,11 @@ yydefault:
 			if l, ok := yylex.(*Lexer); ok {
 				yyVAL.expr.SetPosition(l.pos)
 			}
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 73:
+	case 102:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:443
+//line parser.y:668
 		{
 			mapExpr := make(map[string]ast.Expr)
 			for _, v := range yyDollar[3].expr_pairs {
@@ -1715,398 +2141,477 @@ yydefault:
 			if l, ok := yylex.(*Lexer); ok {
 				yyVAL.expr.SetPosition(l.pos)
 			}
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 74:
+	case 103:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:452
+//line parser.y:678
 		{
 			yyVAL.expr = &ast.ParenExpr{SubExpr: yyDollar[2].expr}
 			if l, ok := yylex.(*Lexer); ok {
 				yyVAL.expr.SetPosition(l.pos)
 			}
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 75:
+	case 104:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:457
+//line parser.y:684
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "+", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 76:
+	case 105:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:462
+//line parser.y:690
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "-", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 77:
+	case 106:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:467
+//line parser.y:696
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "*", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 78:
+	case 107:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:472
+//line parser.y:702
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "/", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 79:
+	case 108:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:477
+//line parser.y:708
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "%", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 80:
+	case 109:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:482
+//line parser.y:714
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "**", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 81:
+	case 110:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:487
+//line parser.y:720
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "<<", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 82:
+	case 111:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:492
+//line parser.y:726
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: ">>", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 83:
+	case 112:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:497
+//line parser.y:732
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "==", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 84:
+	case 113:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:502
+//line parser.y:738
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "!=", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 85:
+	case 114:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:507
+//line parser.y:744
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: ">", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 86:
+	case 115:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:512
+//line parser.y:750
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: ">=", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 87:
+	case 116:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:517
+//line parser.y:756
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "<", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 88:
+	case 117:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:522
+//line parser.y:762
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "<=", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 89:
+	case 118:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:527
+//line parser.y:768
 		{
 			yyVAL.expr = &ast.AssocExpr{Lhs: yyDollar[1].expr, Operator: "+=", Rhs: yyDollar[3].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 90:
+	case 119:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:532
+//line parser.y:774
 		{
 			yyVAL.expr = &ast.AssocExpr{Lhs: yyDollar[1].expr, Operator: "-=", Rhs: yyDollar[3].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 91:
+	case 120:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:537
+//line parser.y:780
 		{
 			yyVAL.expr = &ast.AssocExpr{Lhs: yyDollar[1].expr, Operator: "*=", Rhs: yyDollar[3].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 92:
+	case 121:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:542
+//line parser.y:786
 		{
 			yyVAL.expr = &ast.AssocExpr{Lhs: yyDollar[1].expr, Operator: "/=", Rhs: yyDollar[3].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 93:
+	case 122:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:547
+//line parser.y:792
 		{
 			yyVAL.expr = &ast.AssocExpr{Lhs: yyDollar[1].expr, Operator: "&=", Rhs: yyDollar[3].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 94:
+	case 123:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:552
+//line parser.y:798
 		{
 			yyVAL.expr = &ast.AssocExpr{Lhs: yyDollar[1].expr, Operator: "|=", Rhs: yyDollar[3].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 95:
+	case 124:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:557
+//line parser.y:804
 		{
 			yyVAL.expr = &ast.AssocExpr{Lhs: yyDollar[1].expr, Operator: "++"}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 96:
+	case 125:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:562
+//line parser.y:810
 		{
 			yyVAL.expr = &ast.AssocExpr{Lhs: yyDollar[1].expr, Operator: "--"}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 97:
+	case 126:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:816
+		{
+			yyVAL.expr = &ast.AssocExpr{Lhs: yyDollar[2].expr, Operator: "++", Prefix: true}
+			yyVAL.expr.SetPosition(yyDollar[2].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
+		}
+	case 127:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:822
+		{
+			yyVAL.expr = &ast.AssocExpr{Lhs: yyDollar[2].expr, Operator: "--", Prefix: true}
+			yyVAL.expr.SetPosition(yyDollar[2].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
+		}
+	case 128:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:567
+//line parser.y:828
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "|", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 98:
+	case 129:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:572
+//line parser.y:834
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "||", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 99:
+	case 130:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:577
+//line parser.y:840
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "&", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 100:
+	case 131:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:582
+//line parser.y:846
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "&&", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 101:
+	case 132:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:587
+//line parser.y:852
 		{
 			yyVAL.expr = &ast.CallExpr{Name: names.UniqueNames.Set(yyDollar[1].tok.Lit), SubExprs: yyDollar[3].exprs, VarArg: true}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 102:
+	case 133:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:592
+//line parser.y:858
 		{
 			yyVAL.expr = &ast.CallExpr{Name: names.UniqueNames.Set(yyDollar[1].tok.Lit), SubExprs: yyDollar[3].exprs}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 103:
+	case 134:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:597
+//line parser.y:864
 		{
 			yyVAL.expr = &ast.CallExpr{Name: names.UniqueNames.Set(yyDollar[2].tok.Lit), SubExprs: yyDollar[4].exprs, VarArg: true, Go: true}
 			yyVAL.expr.SetPosition(yyDollar[2].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 104:
+	case 135:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:602
+//line parser.y:870
 		{
 			yyVAL.expr = &ast.CallExpr{Name: names.UniqueNames.Set(yyDollar[2].tok.Lit), SubExprs: yyDollar[4].exprs, Go: true}
 			yyVAL.expr.SetPosition(yyDollar[2].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 105:
+	case 136:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:607
+//line parser.y:876
 		{
 			yyVAL.expr = &ast.AnonCallExpr{Expr: yyDollar[1].expr, SubExprs: yyDollar[3].exprs, VarArg: true}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 106:
+	case 137:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:612
+//line parser.y:882
 		{
 			yyVAL.expr = &ast.AnonCallExpr{Expr: yyDollar[1].expr, SubExprs: yyDollar[3].exprs}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 107:
+	case 138:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:617
+//line parser.y:888
 		{
 			yyVAL.expr = &ast.AnonCallExpr{Expr: yyDollar[2].expr, SubExprs: yyDollar[4].exprs, VarArg: true, Go: true}
 			yyVAL.expr.SetPosition(yyDollar[2].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 108:
+	case 139:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:622
+//line parser.y:894
 		{
 			yyVAL.expr = &ast.AnonCallExpr{Expr: yyDollar[2].expr, SubExprs: yyDollar[4].exprs, Go: true}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 109:
+	case 140:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:627
+//line parser.y:900
 		{
 			yyVAL.expr = &ast.ItemExpr{Value: &ast.IdentExpr{Lit: yyDollar[1].tok.Lit, Id: names.UniqueNames.Set(yyDollar[1].tok.Lit)}, Index: yyDollar[3].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 110:
+	case 141:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:632
+//line parser.y:906
 		{
 			yyVAL.expr = &ast.ItemExpr{Value: yyDollar[1].expr, Index: yyDollar[3].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 111:
+	case 142:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:637
+//line parser.y:912
 		{
 			yyVAL.expr = &ast.SliceExpr{Value: &ast.IdentExpr{Lit: yyDollar[1].tok.Lit, Id: names.UniqueNames.Set(yyDollar[1].tok.Lit)}, Begin: yyDollar[3].expr, End: yyDollar[5].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 112:
+	case 143:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:642
+//line parser.y:918
 		{
 			yyVAL.expr = &ast.SliceExpr{Value: &ast.IdentExpr{Lit: yyDollar[1].tok.Lit, Id: names.UniqueNames.Set(yyDollar[1].tok.Lit)}, Begin: yyDollar[3].expr, End: &ast.NoneExpr{}}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 113:
+	case 144:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:647
+//line parser.y:924
 		{
 			yyVAL.expr = &ast.SliceExpr{Value: &ast.IdentExpr{Lit: yyDollar[1].tok.Lit, Id: names.UniqueNames.Set(yyDollar[1].tok.Lit)}, Begin: &ast.NoneExpr{}, End: yyDollar[4].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 114:
+	case 145:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:652
+//line parser.y:930
 		{
 			yyVAL.expr = &ast.SliceExpr{Value: yyDollar[1].expr, Begin: yyDollar[3].expr, End: yyDollar[5].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 115:
+	case 146:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:657
+//line parser.y:936
 		{
 			yyVAL.expr = &ast.SliceExpr{Value: yyDollar[1].expr, Begin: yyDollar[3].expr, End: &ast.NoneExpr{}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 116:
+	case 147:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:662
+//line parser.y:942
 		{
 			yyVAL.expr = &ast.SliceExpr{Value: yyDollar[1].expr, Begin: &ast.NoneExpr{}, End: yyDollar[4].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 117:
+	case 148:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:667
+//line parser.y:948
 		{
 			yyVAL.expr = &ast.MakeExpr{Type: yyDollar[2].typ.Name}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 118:
+	case 149:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:672
+//line parser.y:954
 		{
 			yyVAL.expr = &ast.MakeChanExpr{SizeExpr: &ast.NoneExpr{}}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 119:
+	case 150:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:677
+//line parser.y:960
 		{
 			yyVAL.expr = &ast.MakeChanExpr{SizeExpr: yyDollar[4].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 120:
+	case 151:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:682
+//line parser.y:966
 		{
 			yyVAL.expr = &ast.MakeArrayExpr{LenExpr: yyDollar[3].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 121:
+	case 152:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:687
+//line parser.y:972
 		{
 			yyVAL.expr = &ast.MakeArrayExpr{LenExpr: yyDollar[3].expr, CapExpr: yyDollar[5].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 122:
+	case 153:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:692
+//line parser.y:978
 		{
 			yyVAL.expr = &ast.TypeCast{Type: yyDollar[2].typ.Name, CastExpr: yyDollar[4].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 123:
+	case 154:
+		yyDollar = yyS[yypt-7 : yypt+1]
+//line parser.y:984
+		{
+			// приведение с параметрами - вызов одноименной функции, например Число(строка, разделитель)
+			yyVAL.expr = &ast.CallExpr{Name: yyDollar[2].typ.Name, SubExprs: append([]ast.Expr{yyDollar[4].expr}, yyDollar[6].exprs...)}
+			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
+		}
+	case 155:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:697
+//line parser.y:991
 		{
 			yyVAL.expr = &ast.MakeExpr{TypeExpr: yyDollar[3].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 124:
+	case 156:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:702
+//line parser.y:997
 		{
 			yyVAL.expr = &ast.TypeCast{TypeExpr: yyDollar[3].expr, CastExpr: yyDollar[5].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 125:
+	case 157:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:707
+//line parser.y:1003
 		{
 			yyVAL.expr = &ast.ChanExpr{Lhs: yyDollar[1].expr, Rhs: yyDollar[3].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 126:
+	case 158:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:712
+//line parser.y:1009
 		{
 			yyVAL.expr = &ast.ChanExpr{Rhs: yyDollar[2].expr}
 			yyVAL.expr.SetPosition(yyDollar[2].expr.Position())
+			yyVAL.expr.SetEndPosition(endPos(yylex, yyrcvr.char))
 		}
-	case 129:
+	case 161:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:723
+//line parser.y:1021
 		{
 		}
-	case 130:
+	case 162:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:726
+//line parser.y:1024
 		{
 		}
-	case 131:
+	case 163:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:731
+//line parser.y:1029
 		{
 		}
-	case 132:
+	case 164:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:734
+//line parser.y:1032
 		{
 		}
 	}
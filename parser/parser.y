@@ -17,14 +17,20 @@ import (
 %type<stmt_default> stmt_default
 %type<stmt_case> stmt_case
 %type<stmt_cases> stmt_cases
+%type<stmt_cases> stmt_typecases
+%type<stmt_case> stmt_typecase
+%type<expr_idents> typ_names
 %type<stmt_elsif> stmt_elsif
 %type<stmt_elsifs> stmt_elsifs
 %type<typ> typ
 %type<expr> expr
 %type<exprs> exprs
+%type<exprs> expr_list
+%type<exprs> call_args
 %type<expr_many> expr_many
 %type<expr_pair> expr_pair
 %type<expr_pairs> expr_pairs
+%type<expr_pairs> expr_pair_list
 %type<expr_idents> expr_idents
 
 %union{
@@ -52,8 +58,9 @@ import (
 	opt_terms              ast.Token
 }
 
-%token<tok> IDENT NUMBER STRING ARRAY VARARG FUNC RETURN THROW IF ELSE FOR IN EQEQ NEQ GE LE OROR ANDAND TRUE FALSE NIL MODULE TRY CATCH FINALLY PLUSEQ MINUSEQ MULEQ DIVEQ ANDEQ OREQ BREAK CONTINUE PLUSPLUS MINUSMINUS POW SHIFTLEFT SHIFTRIGHT SWITCH CASE DEFAULT GO CHAN MAKE OPCHAN ARRAYLIT NULL EACH TO ELSIF WHILE TERNARY TYPECAST
+%token<tok> IDENT NUMBER STRING ARRAY VARARG FUNC RETURN THROW IF ELSE FOR IN EQEQ NEQ GE LE OROR ANDAND TRUE FALSE NIL MODULE TRY CATCH FINALLY PLUSEQ MINUSEQ MULEQ DIVEQ ANDEQ OREQ BREAK CONTINUE PLUSPLUS MINUSMINUS POW SHIFTLEFT SHIFTRIGHT SWITCH CASE DEFAULT GO CHAN MAKE OPCHAN ARRAYLIT NULL EACH TO ELSIF WHILE TERNARY TYPECAST DEFINE WITH TRYEXPR GLOBAL
 
+%right TRYEXPR
 %right '='
 %right '?' ':'
 %left OROR
@@ -64,6 +71,7 @@ import (
 
 %left '+' '-' PLUSPLUS MINUSMINUS
 %left '*' '/' '%'
+%right POW
 %right UNARY
 
 %%
@@ -97,6 +105,7 @@ module :
 	{
 		$$ = &ast.ModuleStmt{Name: names.UniqueNames.Set($2.Lit), Stmts: $4}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 
 compstmt : opt_terms
@@ -132,6 +141,12 @@ stmt :
 	{
 		$$ = &ast.LetsStmt{Lhss: $1, Operator: "=", Rhss: $3}
 	}
+	| expr_many DEFINE expr_many
+	{
+		$$ = &ast.LetsStmt{Lhss: $1, Operator: ":=", Rhss: $3, Declare: true}
+		$$.SetPosition($1[0].Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
+	}
 	| expr_many EQEQ expr_many
 	{
 		$$ = &ast.ExprStmt{Expr: &ast.BinOpExpr{Lhss: $1, Operator: "==", Rhss: $3}}
@@ -140,66 +155,132 @@ stmt :
 	{
 		$$ = &ast.BreakStmt{}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| CONTINUE
 	{
 		$$ = &ast.ContinueStmt{}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| RETURN exprs
 	{
 		$$ = &ast.ReturnStmt{Exprs: $2}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| THROW expr
 	{
 		$$ = &ast.ThrowStmt{Expr: $2}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
+	}
+	| GLOBAL expr_idents
+	{
+		if len($2) == 0 {
+			yylex.Error("ожидается имя переменной")
+		}
+		$$ = &ast.GlobalStmt{Names: $2}
+		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| stmt_if
 	{
 		$$ = $1
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| FOR EACH IDENT IN expr '{' compstmt '}'
 	{
 		$$ = &ast.ForStmt{Var: names.UniqueNames.Set($3.Lit), Value: $5, Stmts: $7}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| FOR IDENT '=' expr TO expr '{' compstmt '}'
 	{
 		$$ = &ast.NumForStmt{Name: names.UniqueNames.Set($2.Lit), Expr1: $4, Expr2: $6, Stmts: $8}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| FOR IDENT EQEQ expr TO expr '{' compstmt '}'
 	{
 		$$ = &ast.NumForStmt{Name: names.UniqueNames.Set($2.Lit), Expr1: $4, Expr2: $6, Stmts: $8}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| WHILE expr '{' compstmt '}'
 	{
 		$$ = &ast.LoopStmt{Expr: $2, Stmts: $4}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| TRY compstmt CATCH compstmt '}'
 	{
 		$$ = &ast.TryStmt{Try: $2, Catch: $4}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
+	}
+	| TRY compstmt CATCH compstmt FINALLY compstmt '}'
+	{
+		$$ = &ast.TryStmt{Try: $2, Catch: $4, Finally: $6}
+		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
+	}
+	| TRY compstmt FINALLY compstmt '}'
+	{
+		$$ = &ast.TryStmt{Try: $2, Finally: $4, NoCatch: true}
+		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
+	}
+	| WITH IDENT EQEQ expr compstmt '}'
+	{
+		$$ = &ast.WithStmt{Var: names.UniqueNames.Set($2.Lit), Expr: $4, Stmts: $5}
+		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| SWITCH expr ':' stmt_cases '}'
 	{
 		$$ = &ast.SwitchStmt{Expr: $2, Cases: $4}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| SWITCH ':' stmt_cases '}'
 	{
 		$$ = &ast.SelectStmt{Cases: $3}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
+	}
+	| SWITCH TO IDENT expr ':' stmt_typecases '}'
+	{
+		if names.FastToLower($3.Lit) != "типу" {
+			yylex.Error("ожидается выбор по типу")
+		}
+		ts := &ast.TypeSwitchStmt{Expr: $4, Cases: $6}
+		// выбор по типу ч = значение: - значение присваивается переменной ч
+		if be, ok := $4.(*ast.BinOpExpr); ok && be.Operator == "==" && len(be.Lhss) == 1 && len(be.Rhss) == 1 {
+			if id, ok := be.Lhss[0].(*ast.IdentExpr); ok {
+				ts.Var = id.Id
+				ts.Expr = be.Rhss[0]
+			}
+		}
+		$$ = ts
+		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
+	}
+	| TYPECAST IDENT IDENT '{' opt_terms expr_idents opt_terms '}'
+	{
+		if $2.Lit != "структура" {
+			yylex.Error("ожидается объявление структуры")
+		}
+		$$ = &ast.StructStmt{Name: names.UniqueNames.Set($3.Lit), Fields: $6}
+		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr
 	{
 		$$ = &ast.ExprStmt{Expr: $1}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 
 stmt_elsifs:
@@ -222,11 +303,13 @@ stmt_if :
 	{
 		$$ = &ast.IfStmt{If: $2, Then: $4, ElseIf: $5, Else: $7}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| IF expr '{' compstmt stmt_elsifs '}'
 	{
 		$$ = &ast.IfStmt{If: $2, Then: $4, ElseIf: $5, Else: nil}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 
 stmt_cases :
@@ -255,10 +338,78 @@ stmt_cases :
 		$$ = append($1, $2)
 	}
 
+stmt_typecases :
+	{
+		$$ = ast.Stmts{}
+	}
+	| opt_terms stmt_typecase
+	{
+		$$ = ast.Stmts{$2}
+	}
+	| opt_terms stmt_default
+	{
+		$$ = ast.Stmts{$2}
+	}
+	| stmt_typecases stmt_typecase
+	{
+		$$ = append($1, $2)
+	}
+	| stmt_typecases stmt_default
+	{
+		for _, stmt := range $1 {
+			if _, ok := stmt.(*ast.DefaultStmt); ok {
+				yylex.Error("multiple default statement")
+			}
+		}
+		$$ = append($1, $2)
+	}
+
+stmt_typecase :
+	CASE typ_names ':' opt_terms compstmt
+	{
+		$$ = &ast.TypeCaseStmt{Types: $2, Stmts: $5}
+		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
+	}
+
+typ_names :
+	IDENT
+	{
+		$$ = []int{names.UniqueNames.Set($1.Lit)}
+	}
+	| TYPECAST IDENT
+	{
+		$$ = []int{names.UniqueNames.Set($2.Lit)}
+	}
+	| NIL
+	{
+		$$ = []int{names.UniqueNames.Set("неопределено")}
+	}
+	| typ_names ',' IDENT
+	{
+		$$ = append($1, names.UniqueNames.Set($3.Lit))
+	}
+	| typ_names ',' TYPECAST IDENT
+	{
+		$$ = append($1, names.UniqueNames.Set($4.Lit))
+	}
+	| typ_names ',' NIL
+	{
+		$$ = append($1, names.UniqueNames.Set("неопределено"))
+	}
+
 stmt_case :
 	CASE expr ':' opt_terms compstmt
 	{
 		$$ = &ast.CaseStmt{Expr: $2, Stmts: $5}
+		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
+	}
+	| CASE expr TO expr ':' opt_terms compstmt
+	{
+		$$ = &ast.CaseStmt{Expr: $2, To: $4, Stmts: $7}
+		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 
 stmt_default :
@@ -277,11 +428,17 @@ expr_pairs :
 	{
 		$$ = []ast.Expr{}
 	}
-	| expr_pair
+	| expr_pair_list
+	{
+		$$ = $1
+	}
+
+expr_pair_list :
+	expr_pair
 	{
 		$$ = []ast.Expr{$1}
 	}
-	| expr_pairs ',' opt_terms expr_pair
+	| expr_pair_list ',' opt_terms expr_pair
 	{
 		$$ = append($1, $4)
 	}
@@ -304,11 +461,11 @@ expr_many :
 	{
 		$$ = []ast.Expr{$1}
 	}
-	| exprs ',' opt_terms expr
+	| expr_list ',' opt_terms expr
 	{
 		$$ = append($1, $4)
 	}
-	| exprs ',' opt_terms IDENT
+	| expr_list ',' opt_terms IDENT
 	{
 		$$ = append($1, &ast.IdentExpr{Lit: $4.Lit, Id: names.UniqueNames.Set($4.Lit)})
 	}
@@ -326,109 +483,176 @@ exprs :
 	{
 		$$ = nil
 	}
-	| expr 
+	| expr_list
+	{
+		$$ = $1
+	}
+
+// непустой список выражений: запятая допустима только между выражениями
+expr_list :
+	expr
 	{
 		$$ = []ast.Expr{$1}
 	}
-	| exprs ',' opt_terms expr
+	| expr_list ',' opt_terms expr
 	{
 		$$ = append($1, $4)
 	}
-	| exprs ',' opt_terms IDENT
+	| expr_list ',' opt_terms IDENT
 	{
 		$$ = append($1, &ast.IdentExpr{Lit: $4.Lit, Id: names.UniqueNames.Set($4.Lit)})
 	}
 
+// аргументы вызова, после последнего допустима запятая
+call_args :
+	exprs
+	{
+		$$ = $1
+	}
+	| expr_list ',' opt_terms
+	{
+		$$ = $1
+	}
+
 expr :
 	IDENT
 	{
 		$$ = &ast.IdentExpr{Lit: $1.Lit, Id: names.UniqueNames.Set($1.Lit)}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| NUMBER
 	{
 		$$ = &ast.NumberExpr{Lit: $1.Lit}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
+	}
+	| TRYEXPR expr
+	{
+		$$ = &ast.TryExpr{Expr: $2}
+		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| '-' expr %prec UNARY
 	{
 		$$ = &ast.UnaryExpr{Operator: "-", Expr: $2}
 		$$.SetPosition($2.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
+	}
+	| '+' expr %prec UNARY
+	{
+		$$ = &ast.UnaryExpr{Operator: "+", Expr: $2}
+		$$.SetPosition($2.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| '!' expr %prec UNARY
 	{
 		$$ = &ast.UnaryExpr{Operator: "!", Expr: $2}
 		$$.SetPosition($2.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| '^' expr %prec UNARY
 	{
 		$$ = &ast.UnaryExpr{Operator: "^", Expr: $2}
 		$$.SetPosition($2.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| STRING
 	{
 		$$ = &ast.StringExpr{Lit: $1.Lit}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| TRUE
 	{
 		$$ = &ast.ConstExpr{Value: "истина"}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| FALSE
 	{
 		$$ = &ast.ConstExpr{Value: "ложь"}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| NIL
 	{
 		$$ = &ast.ConstExpr{Value: "неопределено"}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| NULL
 	{
 		$$ = &ast.ConstExpr{Value: "null"}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| TERNARY expr ',' expr ',' expr ')'
 	{
 		$$ = &ast.TernaryOpExpr{Expr: $2, Lhs: $4, Rhs: $6}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr '.' IDENT
 	{
 		$$ = &ast.MemberExpr{Expr: $1, Name: names.UniqueNames.Set($3.Lit)}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| FUNC '(' expr_idents ')' opt_terms compstmt '}'
 	{
 		$$ = &ast.FuncExpr{Name:names.UniqueNames.Set("<анонимная функция>"), Args: $3, Stmts: $6}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| FUNC '(' IDENT VARARG ')' opt_terms compstmt '}'
 	{
 		$$ = &ast.FuncExpr{Name:names.UniqueNames.Set("<анонимная функция>"), Args: []int{names.UniqueNames.Set($3.Lit)}, Stmts: $7, VarArg: true}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| FUNC IDENT '(' expr_idents ')' opt_terms compstmt '}'
 	{
 		$$ = &ast.FuncExpr{Name: names.UniqueNames.Set($2.Lit), Args: $4, Stmts: $7}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| FUNC IDENT '(' IDENT VARARG ')' opt_terms compstmt '}'
 	{
 		$$ = &ast.FuncExpr{Name: names.UniqueNames.Set($2.Lit), Args: []int{names.UniqueNames.Set($4.Lit)}, Stmts: $8, VarArg: true}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
+	}
+	| FUNC '(' IDENT IDENT ')' IDENT '(' expr_idents ')' opt_terms compstmt '}'
+	{
+		$$ = &ast.FuncExpr{Name: names.UniqueNames.Set($6.Lit), Args: $8, Stmts: $11, Receiver: names.UniqueNames.Set($3.Lit), RecvType: names.UniqueNames.Set($4.Lit)}
+		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
+	}
+	| FUNC '(' IDENT IDENT ')' MODULE '(' expr_idents ')' opt_terms compstmt '}'
+	{
+		// имя метода может совпадать с ключевым словом "модуль"
+		$$ = &ast.FuncExpr{Name: names.UniqueNames.Set($6.Lit), Args: $8, Stmts: $11, Receiver: names.UniqueNames.Set($3.Lit), RecvType: names.UniqueNames.Set($4.Lit)}
+		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| '[' opt_terms exprs opt_terms ']'
 	{
 		$$ = &ast.ArrayExpr{Exprs: $3}
 		if l, ok := yylex.(*Lexer); ok { $$.SetPosition(l.pos) }
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
-	| '[' opt_terms exprs ',' opt_terms ']'
+	| '[' opt_terms expr_list ',' opt_terms ']'
 	{
 		$$ = &ast.ArrayExpr{Exprs: $3}
 		if l, ok := yylex.(*Lexer); ok { $$.SetPosition(l.pos) }
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
+	}
+	| '[' opt_terms expr_list ',' VARARG IDENT opt_terms ']'
+	{
+		$$ = &ast.ArrayExpr{Exprs: $3, Rest: &ast.IdentExpr{Lit: $6.Lit, Id: names.UniqueNames.Set($6.Lit)}}
+		if l, ok := yylex.(*Lexer); ok { $$.SetPosition(l.pos) }
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| '{' opt_terms expr_pairs opt_terms '}'
 	{
@@ -438,8 +662,9 @@ expr :
 		}
 		$$ = &ast.MapExpr{MapExpr: mapExpr}
 		if l, ok := yylex.(*Lexer); ok { $$.SetPosition(l.pos) }
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
-	| '{' opt_terms expr_pairs ',' opt_terms '}'
+	| '{' opt_terms expr_pair_list ',' opt_terms '}'
 	{
 		mapExpr := make(map[string]ast.Expr)
 		for _, v := range $3 {
@@ -447,271 +672,344 @@ expr :
 		}
 		$$ = &ast.MapExpr{MapExpr: mapExpr}
 		if l, ok := yylex.(*Lexer); ok { $$.SetPosition(l.pos) }
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| '(' expr ')'
 	{
 		$$ = &ast.ParenExpr{SubExpr: $2}
 		if l, ok := yylex.(*Lexer); ok { $$.SetPosition(l.pos) }
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr '+' expr
 	{
 		$$ = &ast.BinOpExpr{Lhss: []ast.Expr{$1}, Operator: "+", Rhss: []ast.Expr{$3}}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr '-' expr
 	{
 		$$ = &ast.BinOpExpr{Lhss: []ast.Expr{$1}, Operator: "-", Rhss: []ast.Expr{$3}}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr '*' expr
 	{
 		$$ = &ast.BinOpExpr{Lhss: []ast.Expr{$1}, Operator: "*", Rhss: []ast.Expr{$3}}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr '/' expr
 	{
 		$$ = &ast.BinOpExpr{Lhss: []ast.Expr{$1}, Operator: "/", Rhss: []ast.Expr{$3}}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr '%' expr
 	{
 		$$ = &ast.BinOpExpr{Lhss: []ast.Expr{$1}, Operator: "%", Rhss: []ast.Expr{$3}}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr POW expr
 	{
 		$$ = &ast.BinOpExpr{Lhss: []ast.Expr{$1}, Operator: "**", Rhss: []ast.Expr{$3}}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr SHIFTLEFT expr
 	{
 		$$ = &ast.BinOpExpr{Lhss: []ast.Expr{$1}, Operator: "<<", Rhss: []ast.Expr{$3}}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr SHIFTRIGHT expr
 	{
 		$$ = &ast.BinOpExpr{Lhss: []ast.Expr{$1}, Operator: ">>", Rhss: []ast.Expr{$3}}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr EQEQ expr
 	{
 		$$ = &ast.BinOpExpr{Lhss: []ast.Expr{$1}, Operator: "==", Rhss: []ast.Expr{$3}}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr NEQ expr
 	{
 		$$ = &ast.BinOpExpr{Lhss: []ast.Expr{$1}, Operator: "!=", Rhss: []ast.Expr{$3}}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr '>' expr
 	{
 		$$ = &ast.BinOpExpr{Lhss: []ast.Expr{$1}, Operator: ">", Rhss: []ast.Expr{$3}}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr GE expr
 	{
 		$$ = &ast.BinOpExpr{Lhss: []ast.Expr{$1}, Operator: ">=", Rhss: []ast.Expr{$3}}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr '<' expr
 	{
 		$$ = &ast.BinOpExpr{Lhss: []ast.Expr{$1}, Operator: "<", Rhss: []ast.Expr{$3}}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr LE expr
 	{
 		$$ = &ast.BinOpExpr{Lhss: []ast.Expr{$1}, Operator: "<=", Rhss: []ast.Expr{$3}}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr PLUSEQ expr
 	{
 		$$ = &ast.AssocExpr{Lhs: $1, Operator: "+=", Rhs: $3}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr MINUSEQ expr
 	{
 		$$ = &ast.AssocExpr{Lhs: $1, Operator: "-=", Rhs: $3}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr MULEQ expr
 	{
 		$$ = &ast.AssocExpr{Lhs: $1, Operator: "*=", Rhs: $3}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr DIVEQ expr
 	{
 		$$ = &ast.AssocExpr{Lhs: $1, Operator: "/=", Rhs: $3}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr ANDEQ expr
 	{
 		$$ = &ast.AssocExpr{Lhs: $1, Operator: "&=", Rhs: $3}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr OREQ expr
 	{
 		$$ = &ast.AssocExpr{Lhs: $1, Operator: "|=", Rhs: $3}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr PLUSPLUS
 	{
 		$$ = &ast.AssocExpr{Lhs: $1, Operator: "++"}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr MINUSMINUS
 	{
 		$$ = &ast.AssocExpr{Lhs: $1, Operator: "--"}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
+	}
+	| PLUSPLUS expr %prec UNARY
+	{
+		$$ = &ast.AssocExpr{Lhs: $2, Operator: "++", Prefix: true}
+		$$.SetPosition($2.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
+	}
+	| MINUSMINUS expr %prec UNARY
+	{
+		$$ = &ast.AssocExpr{Lhs: $2, Operator: "--", Prefix: true}
+		$$.SetPosition($2.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr '|' expr
 	{
 		$$ = &ast.BinOpExpr{Lhss: []ast.Expr{$1}, Operator: "|", Rhss: []ast.Expr{$3}}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr OROR expr
 	{
 		$$ = &ast.BinOpExpr{Lhss: []ast.Expr{$1}, Operator: "||", Rhss: []ast.Expr{$3}}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr '&' expr
 	{
 		$$ = &ast.BinOpExpr{Lhss: []ast.Expr{$1}, Operator: "&", Rhss: []ast.Expr{$3}}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr ANDAND expr
 	{
 		$$ = &ast.BinOpExpr{Lhss: []ast.Expr{$1}, Operator: "&&", Rhss: []ast.Expr{$3}}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| IDENT '(' exprs VARARG ')'
 	{
 		$$ = &ast.CallExpr{Name: names.UniqueNames.Set($1.Lit), SubExprs: $3, VarArg: true}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
-	| IDENT '(' exprs ')'
+	| IDENT '(' call_args ')'
 	{
 		$$ = &ast.CallExpr{Name: names.UniqueNames.Set($1.Lit), SubExprs: $3}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| GO IDENT '(' exprs VARARG ')'
 	{
 		$$ = &ast.CallExpr{Name: names.UniqueNames.Set($2.Lit), SubExprs: $4, VarArg: true, Go: true}
 		$$.SetPosition($2.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
-	| GO IDENT '(' exprs ')'
+	| GO IDENT '(' call_args ')'
 	{
 		$$ = &ast.CallExpr{Name: names.UniqueNames.Set($2.Lit), SubExprs: $4, Go: true}
 		$$.SetPosition($2.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr '(' exprs VARARG ')'
 	{
 		$$ = &ast.AnonCallExpr{Expr: $1, SubExprs: $3, VarArg: true}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
-	| expr '(' exprs ')'
+	| expr '(' call_args ')'
 	{
 		$$ = &ast.AnonCallExpr{Expr: $1, SubExprs: $3}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| GO expr '(' exprs VARARG ')'
 	{
 		$$ = &ast.AnonCallExpr{Expr: $2, SubExprs: $4, VarArg: true, Go: true}
 		$$.SetPosition($2.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
-	| GO expr '(' exprs ')'
+	| GO expr '(' call_args ')'
 	{
 		$$ = &ast.AnonCallExpr{Expr: $2, SubExprs: $4, Go: true}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| IDENT '[' expr ']'
 	{
 		$$ = &ast.ItemExpr{Value: &ast.IdentExpr{Lit: $1.Lit, Id: names.UniqueNames.Set($1.Lit)}, Index: $3}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr '[' expr ']'
 	{
 		$$ = &ast.ItemExpr{Value: $1, Index: $3}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| IDENT '[' expr ':' expr ']'
 	{
 		$$ = &ast.SliceExpr{Value: &ast.IdentExpr{Lit: $1.Lit, Id: names.UniqueNames.Set($1.Lit)}, Begin: $3, End: $5}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| IDENT '[' expr ':' ']'
 	{
 		$$ = &ast.SliceExpr{Value: &ast.IdentExpr{Lit: $1.Lit, Id: names.UniqueNames.Set($1.Lit)}, Begin: $3, End: &ast.NoneExpr{}}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| IDENT '[' ':' expr ']'
 	{
 		$$ = &ast.SliceExpr{Value: &ast.IdentExpr{Lit: $1.Lit, Id: names.UniqueNames.Set($1.Lit)}, Begin: &ast.NoneExpr{}, End: $4}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr '[' expr ':' expr ']'
 	{
 		$$ = &ast.SliceExpr{Value: $1, Begin: $3, End: $5}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr '[' expr ':' ']'
 	{
 		$$ = &ast.SliceExpr{Value: $1, Begin: $3, End: &ast.NoneExpr{}}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr '[' ':' expr ']'
 	{
 		$$ = &ast.SliceExpr{Value: $1, Begin: &ast.NoneExpr{}, End: $4}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| MAKE typ
 	{
 		$$ = &ast.MakeExpr{Type: $2.Name}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| MAKE CHAN
 	{
 		$$ = &ast.MakeChanExpr{SizeExpr: &ast.NoneExpr{}}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| MAKE CHAN '(' expr ')'
 	{
 		$$ = &ast.MakeChanExpr{SizeExpr: $4}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| ARRAYLIT '(' expr ')'
 	{
 		$$ = &ast.MakeArrayExpr{LenExpr: $3}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| ARRAYLIT '(' expr ',' expr ')'
 	{
 		$$ = &ast.MakeArrayExpr{LenExpr: $3, CapExpr: $5}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| TYPECAST typ '(' expr ')'
 	{
 		$$ = &ast.TypeCast{Type: $2.Name, CastExpr: $4}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
+	}
+	| TYPECAST typ '(' expr ',' call_args ')'
+	{
+		// приведение с параметрами - вызов одноименной функции, например Число(строка, разделитель)
+		$$ = &ast.CallExpr{Name: $2.Name, SubExprs: append([]ast.Expr{$4}, $6...)}
+		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| MAKE '(' expr ')'
 	{
 		$$ = &ast.MakeExpr{TypeExpr: $3}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| MAKE '(' expr ',' expr ')'
 	{
 		$$ = &ast.TypeCast{TypeExpr: $3, CastExpr: $5}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| expr OPCHAN expr
 	{
 		$$ = &ast.ChanExpr{Lhs: $1, Rhs: $3}
 		$$.SetPosition($1.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 	| OPCHAN expr
 	{
 		$$ = &ast.ChanExpr{Rhs: $2}
 		$$.SetPosition($2.Position())
+		$$.SetEndPosition(endPos(yylex, yyrcvr.char))
 	}
 
 opt_terms : /* none */
@@ -0,0 +1,273 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/shinanca/gonec/ast"
+	posit "github.com/shinanca/gonec/pos"
+)
+
+// parseExpr разбирает одно выражение-оператор и возвращает его дерево
+func parseExpr(t *testing.T, src string) ast.Expr {
+	t.Helper()
+	scanner := &Scanner{}
+	scanner.Init("модуль _\n" + src)
+	stmts, err := Parse(scanner)
+	if err != nil {
+		t.Fatalf("ошибка разбора %q: %v", src, err)
+	}
+	mod := stmts[0].(*ast.ModuleStmt)
+	if len(mod.Stmts) != 1 {
+		t.Fatalf("ожидался один оператор, получено %d", len(mod.Stmts))
+	}
+	return mod.Stmts[0].(*ast.ExprStmt).Expr
+}
+
+func TestOperatorAssociativity(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		// степень правоассоциативна
+		{src: "2 ** 3 ** 2", want: "(2 ** (3 ** 2))"},
+		// и имеет приоритет выше умножения, но ниже унарных операций
+		{src: "2 * 3 ** 2", want: "(2 * (3 ** 2))"},
+		{src: "2 ** 3 * 2", want: "((2 ** 3) * 2)"},
+		{src: "-2 ** 2", want: "(-2 ** 2)"},
+		// остальные бинарные операции левоассоциативны
+		{src: "2 - 3 - 1", want: "((2 - 3) - 1)"},
+		{src: "8 / 4 / 2", want: "((8 / 4) / 2)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			if got := nested(parseExpr(t, tt.src)); got != tt.want {
+				t.Errorf("%s разобрано как %s, ожидалось %s", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+// nested выводит бинарные операции со скобками, показывающими порядок вложенности
+func nested(e ast.Expr) string {
+	if b, ok := e.(*ast.BinOpExpr); ok {
+		return "(" + nested(b.Lhss[0]) + " " + b.Operator + " " + nested(b.Rhss[0]) + ")"
+	}
+	return ast.ExprString(e)
+}
+
+func TestIncremental(t *testing.T) {
+	inc := NewIncremental("_")
+
+	// законченный оператор разбирается сразу
+	stmts, more, err := inc.Feed("а = 1")
+	if err != nil || more || len(stmts) != 1 || len(stmts[0].(*ast.ModuleStmt).Stmts) != 1 {
+		t.Fatalf("законченный оператор: %v, more %v, ошибка %v", stmts, more, err)
+	}
+
+	// незавершенный блок требует продолжения
+	for _, line := range []string{"если а = 1 тогда", "\tм = [1,", "\t\t2]", "\tсообщить(м)"} {
+		if _, more, err = inc.Feed(line); err != nil || !more {
+			t.Fatalf("строка %q: more %v, ошибка %v", line, more, err)
+		}
+		if !inc.Pending() {
+			t.Fatalf("строка %q: ввод не накапливается", line)
+		}
+	}
+	stmts, more, err = inc.Feed("конецесли")
+	if err != nil || more {
+		t.Fatalf("завершение блока: more %v, ошибка %v", more, err)
+	}
+	mod := stmts[0].(*ast.ModuleStmt)
+	if _, ok := mod.Stmts[0].(*ast.IfStmt); !ok || len(mod.Stmts) != 1 || inc.Pending() {
+		t.Errorf("ожидался один оператор Если, получено %#v", mod.Stmts)
+	}
+
+	// незакрытая многострочная строка тоже требует продолжения
+	if _, more, _ = inc.Feed("с = `начало"); !more {
+		t.Errorf("незакрытая строка не требует продолжения")
+	}
+	if _, more, err = inc.Feed("конец`"); more || err != nil {
+		t.Errorf("завершение строки: more %v, ошибка %v", more, err)
+	}
+	// а обычная строка не может продолжаться на следующей строке
+	if _, more, err = inc.Feed("с = \"начало\nконец\""); more || err == nil {
+		t.Errorf("перенос в строке: more %v, ошибка %v", more, err)
+	}
+
+	// настоящая ошибка сбрасывает накопленный ввод
+	if _, more, err = inc.Feed("а = )"); more || err == nil || inc.Pending() {
+		t.Errorf("ошибка не обнаружена: more %v, ошибка %v", more, err)
+	}
+}
+
+func TestSyntaxErrorPosition(t *testing.T) {
+	src := "Модуль _\nа = 1\nб = 2\nв = (а + )"
+	s := new(Scanner)
+	s.Init(src)
+	_, err := Parse(s)
+	if err == nil || !strings.HasPrefix(err.Error(), "[4:10] ") {
+		t.Errorf("ожидалась ошибка в [4:10], получено %v", err)
+	}
+
+	// в интерактивном режиме строка заголовка модуля не учитывается
+	_, _, err = NewIncremental("_").Feed("а = 1\nб = 2\nфункция ф(а б)")
+	if err == nil || err.Error() != "[3:13] синтаксическая ошибка: встретилось: имя, ожидается: ',' или ')'" {
+		t.Errorf("неверная ошибка: %v", err)
+	}
+}
+
+func TestExpectedTokens(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		// блок без завершающего слова
+		{"Если а Тогда\n\tб = 1\n", "ожидается: Иначе или ИначеЕсли или КонецЕсли/КонецЦикла/КонецФункции/... ('}')"},
+		{"м = [1, 2", "[1:10] синтаксическая ошибка: встретилось: конец текста, ожидается: ']'"},
+		{"Для Каждого х м Цикл\nКонецЦикла", "встретилось: имя, ожидается: Из"},
+		// пропущенный операнд
+		{"в = (а + )", "встретилось: ')', ожидается: выражение (имя, число, строка, '(' или '[')"},
+		{"а = \nб = 2", "[1:5] синтаксическая ошибка: встретилось: конец строки, ожидается: выражение"},
+	}
+	for _, tt := range tests {
+		s := new(Scanner)
+		s.Init("Модуль _\n" + tt.src)
+		_, err := Parse(s)
+		if err == nil {
+			t.Errorf("%q: ошибка не обнаружена", tt.src)
+			continue
+		}
+		err.(*Error).Pos.Line--
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: ожидалось %q, получено %q", tt.src, tt.want, err.Error())
+		}
+	}
+
+	// без подробностей остается только сам факт ошибки
+	SetErrorVerbose(false)
+	defer SetErrorVerbose(true)
+	s := new(Scanner)
+	s.Init("Модуль _\nв = (а + )")
+	if _, err := Parse(s); err == nil || err.Error() != "[2:10] синтаксическая ошибка" {
+		t.Errorf("краткая ошибка: %v", err)
+	}
+}
+
+func TestNodeRange(t *testing.T) {
+	span := func(p interface {
+		Position() posit.Position
+		EndPosition() posit.Position
+	}) string {
+		return fmt.Sprintf("%d:%d-%d:%d", p.Position().Line, p.Position().Column, p.EndPosition().Line, p.EndPosition().Column)
+	}
+
+	// строки считаются с учетом заголовка модуля, конец - позиция сразу за последним символом
+	e := parseExpr(t, "а + б * в").(*ast.BinOpExpr)
+	if got := span(e); got != "2:1-2:10" {
+		t.Errorf("а + б * в: %s", got)
+	}
+	if got := span(e.Rhss[0]); got != "2:5-2:10" {
+		t.Errorf("б * в: %s", got)
+	}
+
+	call := parseExpr(t, "ф(а, (1 + 2))").(*ast.CallExpr)
+	if got := span(call); got != "2:1-2:14" {
+		t.Errorf("вызов: %s", got)
+	}
+	// вызов на нескольких строках, после которого есть еще операторы
+	scanner := &Scanner{}
+	scanner.Init("модуль _\nф(1,\n  2)   \nб = 1")
+	stmts, err := Parse(scanner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mod := stmts[0].(*ast.ModuleStmt)
+	if got := span(mod.Stmts[0].(*ast.ExprStmt).Expr); got != "2:1-3:5" {
+		t.Errorf("многострочный вызов: %s", got)
+	}
+
+	scanner = &Scanner{}
+	scanner.Init("модуль _\nЕсли а Тогда\n\tб = 1\nКонецЕсли")
+	if stmts, err = Parse(scanner); err != nil {
+		t.Fatal(err)
+	}
+	if got := span(stmts[0].(*ast.ModuleStmt).Stmts[0]); got != "2:1-4:10" {
+		t.Errorf("Если: %s", got)
+	}
+}
+
+func TestTrailingComma(t *testing.T) {
+	// запятая после последнего элемента допустима
+	for _, src := range []string{
+		"[1, 2, 3,]",
+		"[1,\n\t2,\n]",
+		`{"а": 1, "б": 2,}`,
+		"ф(х, у,)",
+		"ф(х,\n)",
+		"м.Метод(1,)",
+	} {
+		e := parseExpr(t, src)
+		n := 0
+		switch x := e.(type) {
+		case *ast.ArrayExpr:
+			n = len(x.Exprs)
+		case *ast.MapExpr:
+			n = len(x.MapExpr)
+		case *ast.CallExpr:
+			n = len(x.SubExprs)
+		case *ast.AnonCallExpr:
+			n = len(x.SubExprs)
+		}
+		if want := strings.Count(src, ","); n != want {
+			t.Errorf("%q: %d элементов, ожидалось %d", src, n, want)
+		}
+	}
+
+	// а перед первым элементом и между элементами - нет
+	for _, src := range []string{"а = [,1]", "а = [,]", `а = {,"б": 1}`, "ф(,1)", "ф(1,,2)"} {
+		s := new(Scanner)
+		s.Init("Модуль _\n" + src)
+		if _, err := Parse(s); err == nil {
+			t.Errorf("%q: ожидалась синтаксическая ошибка", src)
+		}
+	}
+}
+
+func TestContextualKeywords(t *testing.T) {
+	// "до" и "случай" - ключевые слова только в заголовках Выбор и Для, в остальном коде это имена
+	for _, src := range []string{
+		"до = 5\nсообщить(до)",
+		"случай = 1\nслучай.поле = 2\nм[до] = случай",
+		"ф(до, случай)",
+		"для каждого до из м цикл\nконеццикла",
+		"для до = 1 по 3 цикл\nконеццикла",
+		"выбор х:\n\tкогда 1:\n\t\tслучай = 2\n\t\tслучай(до)\nконецвыбора",
+		"выбор х:\n\tкогда 1:\n\t\tа = м[1:случай]\nконецвыбора",
+		"функция ф(до, случай)\n\tвозврат до + случай\nконецфункции",
+	} {
+		s := new(Scanner)
+		s.Init("Модуль _\n" + src)
+		if _, err := Parse(s); err != nil {
+			t.Errorf("%q: %v", src, err)
+		}
+	}
+
+	// а в заголовках задают диапазон
+	s := new(Scanner)
+	s.Init("Модуль _\nдо = 1\nвыбор х:\n\tслучай до до 10:\n\t\tдо = 2\n\tкогда 11 до 20:\nконецвыбора\nдля н = до до 3 цикл\nконеццикла")
+	stmts, err := Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mod := stmts[0].(*ast.ModuleStmt).Stmts
+	for i, c := range mod[1].(*ast.SwitchStmt).Cases {
+		if c.(*ast.CaseStmt).To == nil {
+			t.Errorf("случай %d разобран без диапазона", i)
+		}
+	}
+	if _, ok := mod[2].(*ast.NumForStmt); !ok {
+		t.Errorf("Для с \"до\" разобран как %T", mod[2])
+	}
+}
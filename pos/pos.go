@@ -7,14 +7,19 @@ type Position struct {
 }
 
 // Pos interface provies two functions to get/set the position for expression or statement.
+// EndPosition и SetEndPosition задают позицию окончания, чтобы инструменты могли выделить весь фрагмент кода
 type Pos interface {
 	Position() Position
 	SetPosition(Position)
+	EndPosition() Position
+	SetEndPosition(Position)
 }
 
 // PosImpl provies commonly implementations for Pos.
+// EndPos - позиция сразу за последним символом фрагмента
 type PosImpl struct {
-	Pos Position
+	Pos    Position
+	EndPos Position
 }
 
 // Position return the position of the expression or statement.
@@ -26,3 +31,13 @@ func (x *PosImpl) Position() Position {
 func (x *PosImpl) SetPosition(pos Position) {
 	x.Pos = pos
 }
+
+// EndPosition возвращает позицию сразу за последним символом выражения или оператора
+func (x *PosImpl) EndPosition() Position {
+	return x.EndPos
+}
+
+// SetEndPosition задает позицию окончания выражения или оператора
+func (x *PosImpl) SetEndPosition(pos Position) {
+	x.EndPos = pos
+}